@@ -38,6 +38,15 @@ build_function() {
         cp "${FUNC_DIR}/go.sum" "$BUILD_DIR/"
     fi
 
+    # Bundle the shared module and point the replace directive at the bundled copy
+    mkdir -p "$BUILD_DIR/shared"
+    cp "${PROJECT_ROOT}/shared/go.mod" "${PROJECT_ROOT}/shared/go.sum" "$BUILD_DIR/shared/"
+    (cd "${PROJECT_ROOT}/shared" && find . -name "*.go" ! -name "*_test.go" -type f) | while read -r file; do
+        mkdir -p "$BUILD_DIR/shared/$(dirname "$file")"
+        cp "${PROJECT_ROOT}/shared/$file" "$BUILD_DIR/shared/$file"
+    done
+    sed -i.bak 's#=> ../../shared#=> ./shared#' "$BUILD_DIR/go.mod" && rm -f "$BUILD_DIR/go.mod.bak"
+
     cd "$BUILD_DIR"
 
     # Run go mod download to populate go.sum if needed
@@ -51,7 +60,8 @@ build_function() {
         ./*.go \
         ./*.mod \
         ./*.sum \
-        ./internal/
+        ./internal/ \
+        ./shared/
 
     echo "  Created ${FUNC_NAME}.zip"
 }
@@ -4,7 +4,9 @@ go 1.23.9
 
 require (
 	github.com/arseniisemenow/review-slot-guard-bot-common v0.1.0
+	github.com/arseniisemenow/review-slot-guard-bot/shared v0.0.0
 	github.com/arseniisemenow/s21auto-client-go v0.1.6
+	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
 	github.com/google/uuid v1.6.0
 	github.com/stretchr/testify v1.10.0
 )
@@ -12,7 +14,6 @@ require (
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/go-resty/resty/v2 v2.7.0 // indirect
-	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1 // indirect
 	github.com/golang-jwt/jwt/v4 v4.5.2 // indirect
 	github.com/jonboulle/clockwork v0.5.0 // indirect
 	github.com/kr/text v0.2.0 // indirect
@@ -31,3 +32,5 @@ require (
 	google.golang.org/protobuf v1.35.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/arseniisemenow/review-slot-guard-bot/shared => ../../shared
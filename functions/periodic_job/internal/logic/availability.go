@@ -0,0 +1,29 @@
+package logic
+
+import (
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/external"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/availability"
+)
+
+// PlanAvailabilitySlots picks the template occurrences that still need a calendar slot.
+// Occurrences that were already opened (keyed by Occurrence.Key) are skipped, as are
+// occurrences overlapping any existing calendar slot, so slots the user created or
+// changed by hand are never duplicated.
+func PlanAvailabilitySlots(occurrences []availability.Occurrence, opened map[string]bool, existing []external.CalendarSlot) []availability.Occurrence {
+	var planned []availability.Occurrence
+
+	for _, occ := range occurrences {
+		if opened[occ.Key()] {
+			continue
+		}
+
+		if availability.FindConflict(occ.Start, occ.End, existing, "") != nil {
+			continue
+		}
+
+		planned = append(planned, occ)
+		existing = append(existing, external.CalendarSlot{Start: occ.Start, End: occ.End})
+	}
+
+	return planned
+}
@@ -0,0 +1,48 @@
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/external"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/availability"
+)
+
+func TestPlanAvailabilitySlots(t *testing.T) {
+	monday := availability.Occurrence{TemplateID: "t1", Date: "2026-01-12", Start: at(19, 0), End: at(21, 0)}
+	tuesday := availability.Occurrence{TemplateID: "t1", Date: "2026-01-13", Start: at(19, 0).AddDate(0, 0, 1), End: at(21, 0).AddDate(0, 0, 1)}
+
+	t.Run("opens new occurrences", func(t *testing.T) {
+		planned := PlanAvailabilitySlots([]availability.Occurrence{monday, tuesday}, nil, nil)
+		assert.Equal(t, []availability.Occurrence{monday, tuesday}, planned)
+	})
+
+	t.Run("skips occurrences already opened", func(t *testing.T) {
+		opened := map[string]bool{monday.Key(): true}
+		planned := PlanAvailabilitySlots([]availability.Occurrence{monday, tuesday}, opened, nil)
+		assert.Equal(t, []availability.Occurrence{tuesday}, planned)
+	})
+
+	t.Run("skips occurrences overlapping existing slots", func(t *testing.T) {
+		existing := []external.CalendarSlot{
+			{ID: "manual", Start: at(20, 0), End: at(22, 0), Type: "FREE_TIME"},
+		}
+		planned := PlanAvailabilitySlots([]availability.Occurrence{monday, tuesday}, nil, existing)
+		assert.Equal(t, []availability.Occurrence{tuesday}, planned)
+	})
+
+	t.Run("adjacent slots do not overlap", func(t *testing.T) {
+		existing := []external.CalendarSlot{
+			{ID: "manual", Start: at(17, 0), End: at(19, 0), Type: "FREE_TIME"},
+		}
+		planned := PlanAvailabilitySlots([]availability.Occurrence{monday}, nil, existing)
+		assert.Equal(t, []availability.Occurrence{monday}, planned)
+	})
+
+	t.Run("overlapping templates open only one slot", func(t *testing.T) {
+		other := availability.Occurrence{TemplateID: "t2", Date: "2026-01-12", Start: at(20, 0), End: at(22, 0)}
+		planned := PlanAvailabilitySlots([]availability.Occurrence{monday, other}, nil, nil)
+		assert.Equal(t, []availability.Occurrence{monday}, planned)
+	})
+}
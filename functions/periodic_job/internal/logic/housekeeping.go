@@ -0,0 +1,156 @@
+package logic
+
+import (
+	"sort"
+	"time"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/external"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/s21auto-client-go/requests"
+)
+
+// MinSlotDuration is the shortest free slot housekeeping will leave behind
+const MinSlotDuration = 15 * time.Minute
+
+// HousekeepingHorizon is how far ahead partially booked slots are tidied
+const HousekeepingHorizon = 24 * time.Hour
+
+// SlotAdjustment describes a planned change to a free calendar slot
+type SlotAdjustment struct {
+	SlotID   string
+	OldStart time.Time
+	OldEnd   time.Time
+	NewStart time.Time
+	NewEnd   time.Time
+	Cancel   bool
+}
+
+// ExtractEventSlots groups calendar slots by the event they belong to
+func ExtractEventSlots(data *requests.CalendarGetEvents_Data) [][]external.CalendarSlot {
+	var events [][]external.CalendarSlot
+
+	for _, event := range data.CalendarEventS21.GetMyCalendarEvents {
+		var slots []external.CalendarSlot
+		for _, slot := range event.EventSlots {
+			slots = append(slots, external.CalendarSlot{
+				ID:    slot.ID,
+				Start: slot.Start,
+				End:   slot.End,
+				Type:  slot.Type,
+			})
+		}
+		if len(slots) > 0 {
+			events = append(events, slots)
+		}
+	}
+
+	return events
+}
+
+// PlanSlotHousekeeping decides how the free slots of one calendar event should change.
+//
+// TRIM shrinks the free time before the first booking and after the last one
+// down to bufferMinutes, so the event ends up as its bookings plus a buffer.
+// SPLIT keeps the free time but cuts a bufferMinutes break out of it next to
+// every booking, so the remaining free slots are split away from the bookings.
+// Events without bookings and slots that have already started are left alone.
+// Free slots shorter than MinSlotDuration after the change are cancelled.
+func PlanSlotHousekeeping(slots []external.CalendarSlot, mode string, bufferMinutes int, now time.Time) []SlotAdjustment {
+	if mode != store.HousekeepingTrim && mode != store.HousekeepingSplit {
+		return nil
+	}
+
+	var bookings, free []external.CalendarSlot
+	for _, slot := range slots {
+		switch slot.Type {
+		case models.SlotTypeBooking:
+			bookings = append(bookings, slot)
+		case models.SlotTypeFreeTime:
+			free = append(free, slot)
+		}
+	}
+
+	if len(bookings) == 0 || len(free) == 0 {
+		return nil
+	}
+
+	sort.Slice(bookings, func(i, j int) bool { return bookings[i].Start.Before(bookings[j].Start) })
+	buffer := time.Duration(bufferMinutes) * time.Minute
+
+	var adjustments []SlotAdjustment
+	for _, slot := range free {
+		if !slot.Start.After(now) {
+			continue
+		}
+
+		var newStart, newEnd time.Time
+		if mode == store.HousekeepingTrim {
+			newStart, newEnd = trimToBookings(slot, bookings, buffer)
+		} else {
+			newStart, newEnd = splitFromBookings(slot, bookings, buffer)
+		}
+
+		if newStart.Equal(slot.Start) && newEnd.Equal(slot.End) {
+			continue
+		}
+
+		adjustment := SlotAdjustment{
+			SlotID:   slot.ID,
+			OldStart: slot.Start,
+			OldEnd:   slot.End,
+		}
+		if newEnd.Sub(newStart) < MinSlotDuration {
+			adjustment.Cancel = true
+		} else {
+			adjustment.NewStart = newStart
+			adjustment.NewEnd = newEnd
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+
+	return adjustments
+}
+
+// trimToBookings clips a free slot to [first booking - buffer, last booking + buffer]
+func trimToBookings(slot external.CalendarSlot, bookings []external.CalendarSlot, buffer time.Duration) (time.Time, time.Time) {
+	windowStart := bookings[0].Start.Add(-buffer)
+	windowEnd := bookings[0].End
+	for _, b := range bookings {
+		if b.End.After(windowEnd) {
+			windowEnd = b.End
+		}
+	}
+	windowEnd = windowEnd.Add(buffer)
+
+	return clip(slot.Start, slot.End, windowStart, windowEnd)
+}
+
+// splitFromBookings moves the edges of a free slot buffer away from neighbouring bookings
+func splitFromBookings(slot external.CalendarSlot, bookings []external.CalendarSlot, buffer time.Duration) (time.Time, time.Time) {
+	start, end := slot.Start, slot.End
+	for _, b := range bookings {
+		if !b.End.After(slot.Start) && b.End.Add(buffer).After(start) {
+			start = b.End.Add(buffer)
+		}
+		if !b.Start.Before(slot.End) && b.Start.Add(-buffer).Before(end) {
+			end = b.Start.Add(-buffer)
+		}
+	}
+	return start, end
+}
+
+// clip returns the intersection of [start, end] and [windowStart, windowEnd].
+// An empty intersection is returned as start == end
+func clip(start, end, windowStart, windowEnd time.Time) (time.Time, time.Time) {
+	if windowStart.After(start) {
+		start = windowStart
+	}
+	if windowEnd.Before(end) {
+		end = windowEnd
+	}
+	if end.Before(start) {
+		end = start
+	}
+	return start, end
+}
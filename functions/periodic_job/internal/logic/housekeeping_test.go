@@ -0,0 +1,148 @@
+package logic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/external"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/s21auto-client-go/requests"
+)
+
+// at returns the test day at the given hour and minute
+func at(hour, minute int) time.Time {
+	return time.Date(2026, 1, 11, hour, minute, 0, 0, time.UTC)
+}
+
+// partiallyBookedEvent is a 10:00-12:00 slot with a booking at 11:00-11:30
+func partiallyBookedEvent() []external.CalendarSlot {
+	return []external.CalendarSlot{
+		{ID: "free-1", Start: at(10, 0), End: at(11, 0), Type: models.SlotTypeFreeTime},
+		{ID: "booking-1", Start: at(11, 0), End: at(11, 30), Type: models.SlotTypeBooking},
+		{ID: "free-2", Start: at(11, 30), End: at(12, 0), Type: models.SlotTypeFreeTime},
+	}
+}
+
+func TestPlanSlotHousekeeping_Off(t *testing.T) {
+	adjustments := PlanSlotHousekeeping(partiallyBookedEvent(), store.HousekeepingOff, 15, at(8, 0))
+	assert.Empty(t, adjustments)
+}
+
+func TestPlanSlotHousekeeping_NoBookings(t *testing.T) {
+	slots := []external.CalendarSlot{
+		{ID: "free-1", Start: at(10, 0), End: at(12, 0), Type: models.SlotTypeFreeTime},
+	}
+
+	assert.Empty(t, PlanSlotHousekeeping(slots, store.HousekeepingTrim, 15, at(8, 0)))
+	assert.Empty(t, PlanSlotHousekeeping(slots, store.HousekeepingSplit, 15, at(8, 0)))
+}
+
+func TestPlanSlotHousekeeping_Trim(t *testing.T) {
+	adjustments := PlanSlotHousekeeping(partiallyBookedEvent(), store.HousekeepingTrim, 15, at(8, 0))
+	require.Len(t, adjustments, 2)
+
+	assert.Equal(t, "free-1", adjustments[0].SlotID)
+	assert.False(t, adjustments[0].Cancel)
+	assert.Equal(t, at(10, 45), adjustments[0].NewStart)
+	assert.Equal(t, at(11, 0), adjustments[0].NewEnd)
+
+	assert.Equal(t, "free-2", adjustments[1].SlotID)
+	assert.False(t, adjustments[1].Cancel)
+	assert.Equal(t, at(11, 30), adjustments[1].NewStart)
+	assert.Equal(t, at(11, 45), adjustments[1].NewEnd)
+}
+
+func TestPlanSlotHousekeeping_TrimBelowMinimumCancels(t *testing.T) {
+	adjustments := PlanSlotHousekeeping(partiallyBookedEvent(), store.HousekeepingTrim, 10, at(8, 0))
+	require.Len(t, adjustments, 2)
+
+	for _, adj := range adjustments {
+		assert.True(t, adj.Cancel, "slot %s should be cancelled", adj.SlotID)
+		assert.True(t, adj.NewStart.IsZero())
+	}
+	assert.Equal(t, at(10, 0), adjustments[0].OldStart)
+	assert.Equal(t, at(11, 0), adjustments[0].OldEnd)
+}
+
+func TestPlanSlotHousekeeping_TrimKeepsGapsBetweenBookings(t *testing.T) {
+	slots := []external.CalendarSlot{
+		{ID: "booking-1", Start: at(10, 0), End: at(10, 30), Type: models.SlotTypeBooking},
+		{ID: "free-1", Start: at(10, 30), End: at(11, 30), Type: models.SlotTypeFreeTime},
+		{ID: "booking-2", Start: at(11, 30), End: at(12, 0), Type: models.SlotTypeBooking},
+	}
+
+	assert.Empty(t, PlanSlotHousekeeping(slots, store.HousekeepingTrim, 15, at(8, 0)))
+}
+
+func TestPlanSlotHousekeeping_Split(t *testing.T) {
+	adjustments := PlanSlotHousekeeping(partiallyBookedEvent(), store.HousekeepingSplit, 15, at(8, 0))
+	require.Len(t, adjustments, 2)
+
+	assert.Equal(t, "free-1", adjustments[0].SlotID)
+	assert.Equal(t, at(10, 0), adjustments[0].NewStart)
+	assert.Equal(t, at(10, 45), adjustments[0].NewEnd)
+
+	assert.Equal(t, "free-2", adjustments[1].SlotID)
+	assert.Equal(t, at(11, 45), adjustments[1].NewStart)
+	assert.Equal(t, at(12, 0), adjustments[1].NewEnd)
+}
+
+func TestPlanSlotHousekeeping_SplitBetweenBookings(t *testing.T) {
+	slots := []external.CalendarSlot{
+		{ID: "booking-1", Start: at(10, 0), End: at(10, 30), Type: models.SlotTypeBooking},
+		{ID: "free-1", Start: at(10, 30), End: at(11, 30), Type: models.SlotTypeFreeTime},
+		{ID: "booking-2", Start: at(11, 30), End: at(12, 0), Type: models.SlotTypeBooking},
+	}
+
+	adjustments := PlanSlotHousekeeping(slots, store.HousekeepingSplit, 15, at(8, 0))
+	require.Len(t, adjustments, 1)
+	assert.Equal(t, at(10, 45), adjustments[0].NewStart)
+	assert.Equal(t, at(11, 15), adjustments[0].NewEnd)
+
+	adjustments = PlanSlotHousekeeping(slots, store.HousekeepingSplit, 25, at(8, 0))
+	require.Len(t, adjustments, 1)
+	assert.True(t, adjustments[0].Cancel)
+}
+
+func TestPlanSlotHousekeeping_IsIdempotent(t *testing.T) {
+	slots := []external.CalendarSlot{
+		{ID: "free-1", Start: at(10, 45), End: at(11, 0), Type: models.SlotTypeFreeTime},
+		{ID: "booking-1", Start: at(11, 0), End: at(11, 30), Type: models.SlotTypeBooking},
+		{ID: "free-2", Start: at(11, 30), End: at(11, 45), Type: models.SlotTypeFreeTime},
+	}
+
+	assert.Empty(t, PlanSlotHousekeeping(slots, store.HousekeepingTrim, 15, at(8, 0)))
+}
+
+func TestPlanSlotHousekeeping_SkipsStartedSlots(t *testing.T) {
+	adjustments := PlanSlotHousekeeping(partiallyBookedEvent(), store.HousekeepingTrim, 15, at(10, 5))
+	require.Len(t, adjustments, 1)
+	assert.Equal(t, "free-2", adjustments[0].SlotID)
+}
+
+func TestExtractEventSlots(t *testing.T) {
+	data := &requests.CalendarGetEvents_Data{
+		CalendarEventS21: requests.CalendarGetEvents_Data_CalendarEventS21{
+			GetMyCalendarEvents: []requests.CalendarGetEvents_Data_GetMyCalendarEvent{
+				{
+					ID: "event-1",
+					EventSlots: []requests.CalendarGetEvents_Data_EventSlot{
+						{ID: "free-1", Type: models.SlotTypeFreeTime, Start: at(10, 0), End: at(11, 0)},
+						{ID: "booking-1", Type: models.SlotTypeBooking, Start: at(11, 0), End: at(11, 30)},
+					},
+				},
+				{ID: "event-2"},
+			},
+		},
+	}
+
+	events := ExtractEventSlots(data)
+	require.Len(t, events, 1)
+	require.Len(t, events[0], 2)
+	assert.Equal(t, "free-1", events[0][0].ID)
+	assert.Equal(t, models.SlotTypeBooking, events[0][1].Type)
+}
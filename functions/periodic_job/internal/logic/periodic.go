@@ -3,14 +3,25 @@ package logic
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/external"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/telegram"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/timeutil"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
 	"github.com/arseniisemenow/s21auto-client-go/requests"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/availability"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/policy"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/settings"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
 )
 
 // ExtractProjectNameFromNotification extracts project name from a notification
@@ -105,82 +116,274 @@ func ChangeCalendarSlot(ctx context.Context, reviewerLogin, slotID string, newSt
 	return client.ChangeEventSlot(ctx, slotID, newStart, newEnd)
 }
 
-// SendNonWhitelistCancelNotification sends a notification about non-whitelist cancellation
-func SendNonWhitelistCancelNotification(ctx context.Context, user interface{}, req interface{}) error {
-	// Type assert to get the actual types
-	u, ok := user.(*models.User)
-	if !ok {
-		return fmt.Errorf("invalid user type")
+// FormatNonWhitelistCancelMessage creates the Telegram message about a non-whitelist cancellation
+func FormatNonWhitelistCancelMessage(req *models.ReviewRequest, loc *time.Location, p *i18n.Printer) render.HTML {
+	return p.T("notify.non_whitelist",
+		projectNameOf(req, p),
+		p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), loc))
+}
+
+// FormatUnaskedCancelMessage creates the Telegram message about a booking outside the whitelist
+// cancelled because the question about it was held until after the review starts
+func FormatUnaskedCancelMessage(req *models.ReviewRequest, loc *time.Location, p *i18n.Printer) render.HTML {
+	return p.T("notify.unasked",
+		projectNameOf(req, p),
+		p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), loc))
+}
+
+// FormatBlacklistCancelMessage creates the Telegram message about a blacklisted review cancellation
+func FormatBlacklistCancelMessage(req *models.ReviewRequest, loc *time.Location, p *i18n.Printer) render.HTML {
+	return p.T("notify.blacklisted",
+		projectNameOf(req, p),
+		p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), loc))
+}
+
+// FormatPolicyDeclineMessage creates the Telegram message about a review declined by a decision policy
+func FormatPolicyDeclineMessage(req *models.ReviewRequest, policyName string, loc *time.Location, p *i18n.Printer) render.HTML {
+	return p.T("notify.policy_declined",
+		projectNameOf(req, p),
+		p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), loc),
+		policyName)
+}
+
+// FormatLimitDeclineMessage creates the Telegram message about a review declined for breaking a limit
+func FormatLimitDeclineMessage(req *models.ReviewRequest, limit *policy.Limit, loc *time.Location, p *i18n.Printer) render.HTML {
+	return p.T("notify.limit_declined",
+		projectNameOf(req, p),
+		p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), loc),
+		DescribeLimit(limit, loc, p))
+}
+
+// FormatLimitAskMessage creates the Telegram message about a review sent for approval for breaking a limit
+func FormatLimitAskMessage(req *models.ReviewRequest, limit *policy.Limit, loc *time.Location, p *i18n.Printer) render.HTML {
+	return p.T("notify.limit_ask",
+		projectNameOf(req, p),
+		p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), loc),
+		DescribeLimit(limit, loc, p))
+}
+
+// FormatPeerDeclineMessage creates the Telegram message about a review of a blocked student declined right away
+func FormatPeerDeclineMessage(req *models.ReviewRequest, peer *store.Peer, loc *time.Location, p *i18n.Printer) render.HTML {
+	return p.T("notify.peer_declined",
+		projectNameOf(req, p),
+		p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), loc),
+		peer.Login)
+}
+
+// DescribeLimit explains which limit a booking breaks
+func DescribeLimit(limit *policy.Limit, loc *time.Location, p *i18n.Printer) render.HTML {
+	if limit.Key == settings.MinGap {
+		return p.T("limit.min_gap", p.N("unit.minutes", limit.Value), p.FormatShort(limit.Conflict, loc))
 	}
-	r, ok := req.(*models.ReviewRequest)
-	if !ok {
-		return fmt.Errorf("invalid review request type")
+	if limit.Key == policy.WorkingHours {
+		return p.T("limit.working_hours", limit.Hours)
 	}
+	return p.T("limit."+limit.Key, p.N("unit.reviews", limit.Value))
+}
 
-	projectName := "Unknown Project"
-	if r.ProjectName != nil {
-		projectName = *r.ProjectName
+// FormatEntryExpiredMessage creates the Telegram message about a removed temporary entry
+func FormatEntryExpiredMessage(entry *models.WhitelistEntry, p *i18n.Printer) render.HTML {
+	key := "notify.whitelist_expired"
+	if whitelist.IsBlacklisted(entry.EntryType) {
+		key = "notify.blacklist_expired"
 	}
+	return p.T(key, whitelist.Describe(p, entry))
+}
 
-	bot, err := telegram.NewBotClientFromEnv()
-	if err != nil {
-		return fmt.Errorf("failed to create telegram client: %w", err)
+// FormatProjectsSyncedMessage creates the Telegram message about completed projects added to the whitelist
+func FormatProjectsSyncedMessage(projects []string, p *i18n.Printer) render.HTML {
+	return p.T("notify.projects_synced", strings.Join(projects, ", "))
+}
+
+// FormatWhitelistTimeoutMessage creates the Telegram message about a review that timed out
+func FormatWhitelistTimeoutMessage(req *models.ReviewRequest, loc *time.Location, p *i18n.Printer) render.HTML {
+	return p.T("notify.whitelist_timeout",
+		projectNameOf(req, p),
+		p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), loc))
+}
+
+// FormatTimeoutApprovedMessage creates the Telegram message about a review approved on timeout
+func FormatTimeoutApprovedMessage(req *models.ReviewRequest, loc *time.Location, p *i18n.Printer) render.HTML {
+	return p.T("notify.timeout_approved",
+		projectNameOf(req, p),
+		p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), loc))
+}
+
+// TimeoutChecksWhitelist reports whether the outcome of the timeout action depends on the whitelist
+func TimeoutChecksWhitelist(action string) bool {
+	return action == settings.TimeoutApproveWhitelisted
+}
+
+// TimeoutOutcome returns the status a review nobody answered in time moves to under the
+// timeout action: APPROVED, or AUTO_CANCELLED as before the action existed
+func TimeoutOutcome(action string, inWhitelist bool) string {
+	switch {
+	case action == settings.TimeoutApprove:
+		return models.StatusApproved
+	case action == settings.TimeoutApproveWhitelisted && inWhitelist:
+		return models.StatusApproved
+	default:
+		return models.StatusAutoCancelled
 	}
+}
 
-	message := fmt.Sprintf("❌ *Review Auto-Cancelled*\n\n"+
-		"Project: %s\n"+
-		"Time: %s\n\n"+
-		"This project is not in your whitelist and was automatically cancelled.",
+// FormatReviewRequestMessage creates the Telegram message for review request, with times in loc.
+// The reviewees are shown when known
+func FormatReviewRequestMessage(projectName string, reviewees []string, reviewStartTime, deadline time.Time, loc *time.Location, p *i18n.Printer) render.HTML {
+	if len(reviewees) > 0 {
+		return p.T("notify.review_request_by",
+			projectName,
+			strings.Join(reviewees, ", "),
+			p.FormatShort(reviewStartTime, loc),
+			p.FormatShort(deadline, loc))
+	}
+	return p.T("notify.review_request",
 		projectName,
-		timeutil.FormatShort(timeutil.FromUnixSeconds(r.ReviewStartTime)))
+		p.FormatShort(reviewStartTime, loc),
+		p.FormatShort(deadline, loc))
+}
 
-	bot.SendPlainMessage(u.TelegramChatID, message)
-	return nil
+// ReviewKeyboard creates the approve/decline buttons of a review request message
+func ReviewKeyboard(reviewRequestID string, p *i18n.Printer) tba.InlineKeyboardMarkup {
+	return tba.NewInlineKeyboardMarkup(tba.NewInlineKeyboardRow(
+		tba.NewInlineKeyboardButtonData(render.Plain(p.T("review.approve_button")), telegram.FormatCallbackData("APPROVE", reviewRequestID)),
+		tba.NewInlineKeyboardButtonData(render.Plain(p.T("review.decline_button")), telegram.FormatCallbackData("DECLINE", reviewRequestID)),
+	))
 }
 
-// SendWhitelistTimeoutNotification sends a notification about whitelist timeout
-func SendWhitelistTimeoutNotification(ctx context.Context, user interface{}, req interface{}) error {
-	// Type assert to get the actual types
-	u, ok := user.(*models.User)
-	if !ok {
-		return fmt.Errorf("invalid user type")
+// EffectiveSettings returns the user's settings with the overrides of the review's project
+// and family applied, most specific first. Reviews of an unknown project use the base settings
+func EffectiveSettings(base *models.UserSettings, overrides []*settings.Override, req *models.ReviewRequest) *models.UserSettings {
+	if len(overrides) == 0 || req.ProjectName == nil {
+		return base
 	}
-	r, ok := req.(*models.ReviewRequest)
-	if !ok {
-		return fmt.Errorf("invalid review request type")
+	familyLabel := ""
+	if req.FamilyLabel != nil {
+		familyLabel = *req.FamilyLabel
 	}
+	values := settings.Resolve(settings.CoreValues(base), overrides, *req.ProjectName, familyLabel)
+	return settings.ApplyCore(base, values)
+}
 
-	projectName := "Unknown Project"
-	if r.ProjectName != nil {
-		projectName = *r.ProjectName
+// EffectivePreferences returns the user's preferences with the overrides of the review's
+// project and family applied to the overridable settings outside the base user_settings
+// columns. Reviews of an unknown project use the user's own preferences
+func EffectivePreferences(prefs *store.UserPreferences, overrides []*settings.Override, req *models.ReviewRequest) *store.UserPreferences {
+	if len(overrides) == 0 || req.ProjectName == nil {
+		return prefs
 	}
+	familyLabel := ""
+	if req.FamilyLabel != nil {
+		familyLabel = *req.FamilyLabel
+	}
+	values := settings.Resolve(settings.Values{settings.TimeoutAction: prefs.TimeoutAction}, overrides, *req.ProjectName, familyLabel)
+	result := *prefs
+	result.TimeoutAction = values[settings.TimeoutAction]
+	return &result
+}
 
-	bot, err := telegram.NewBotClientFromEnv()
+// NewBooking returns what the decision policies see of a review. accepted holds the user's
+// approved and whitelisted reviews; the review itself is left out of them
+func NewBooking(req *models.ReviewRequest, entries []*models.WhitelistEntry, presets map[*models.WhitelistEntry]string, accepted []*models.ReviewRequest, loc *time.Location, now time.Time) *policy.Booking {
+	b := &policy.Booking{
+		Start:    timeutil.FromUnixSeconds(req.ReviewStartTime),
+		Entries:  entries,
+		Presets:  presets,
+		Location: loc,
+		Now:      now,
+	}
+	if req.ProjectName != nil {
+		b.ProjectName = *req.ProjectName
+	}
+	if req.FamilyLabel != nil {
+		b.FamilyLabel = *req.FamilyLabel
+	}
+	for _, a := range accepted {
+		if a.ID != req.ID {
+			b.Accepted = append(b.Accepted, timeutil.FromUnixSeconds(a.ReviewStartTime))
+		}
+	}
+	return b
+}
+
+// BookingLimits returns the user's limits on accepted reviews
+func BookingLimits(prefs *store.UserPreferences) policy.BookingLimits {
+	decision := policy.Ask
+	if prefs.LimitAction == settings.LimitActionDecline {
+		decision = policy.Decline
+	}
+	return policy.BookingLimits{
+		PerDay:        int(prefs.MaxReviewsPerDay),
+		PerWeek:       int(prefs.MaxReviewsPerWeek),
+		MinGapMinutes: int(prefs.MinReviewGapMinutes),
+		Decision:      decision,
+	}
+}
+
+// BookingWorkingHours returns the user's working hours and what happens to kept bookings
+// outside them. Working hours are checked when saved, so ones that fail to parse are ignored
+func BookingWorkingHours(prefs *store.UserPreferences) (availability.WorkingHours, policy.Decision) {
+	hours, err := availability.ParseWorkingHours(prefs.WorkingHours)
 	if err != nil {
-		return fmt.Errorf("failed to create telegram client: %w", err)
+		hours = nil
+	}
+	if prefs.OutsideHoursAction == settings.OutsideHoursDecline {
+		return hours, policy.Decline
 	}
+	return hours, policy.Ask
+}
 
-	message := fmt.Sprintf("⏰ *Review Timeout*\n\n"+
-		"Project: %s\n"+
-		"Time: %s\n\n"+
-		"You did not respond in time and this review was automatically cancelled.",
-		projectName,
-		timeutil.FormatShort(timeutil.FromUnixSeconds(r.ReviewStartTime)))
+// BlockedPeerDecision returns what happens to bookings of the user's blocked students
+func BlockedPeerDecision(prefs *store.UserPreferences) policy.Decision {
+	if prefs.BlockedPeerAction == settings.BlockedPeerDecline {
+		return policy.Decline
+	}
+	return policy.Ask
+}
 
-	bot.SendPlainMessage(u.TelegramChatID, message)
-	return nil
+// AsksForNonWhitelisted reports whether bookings no policy kept are sent for approval
+// instead of being cancelled after the cancel delay
+func AsksForNonWhitelisted(prefs *store.UserPreferences) bool {
+	return prefs.NonWhitelistAction == settings.NonWhitelistAsk
 }
 
-// FormatReviewRequestMessage creates the Telegram message for review request
-func FormatReviewRequestMessage(projectName string, reviewStartTime, deadline time.Time) string {
-	return fmt.Sprintf("*Review Request*\n\n"+
-		"Project: %s\n"+
-		"Time: %s\n\n"+
-		"Please respond by %s.\n\n"+
-		"Use the buttons below to approve or decline.",
-		projectName,
-		timeutil.FormatShort(reviewStartTime),
-		timeutil.FormatShort(deadline))
+// PlanNonWhitelistDeadline returns when an unanswered booking outside the whitelist is cancelled:
+// the given minutes from now, but never after the review starts. Quiet hours do not count, so a
+// deadline inside them is moved past their end by the time that was left when they began
+func PlanNonWhitelistDeadline(reviewStartTime time.Time, minutes int, quietStart, quietEnd int32, loc *time.Location, now time.Time) time.Time {
+	deadline := now.Add(time.Duration(minutes) * time.Minute)
+	if InQuietHours(deadline, quietStart, quietEnd, loc) {
+		began := quietHoursStart(deadline, quietStart, loc)
+		if began.Before(now) {
+			began = now
+		}
+		deadline = QuietHoursEnd(deadline, quietStart, quietEnd, loc).Add(deadline.Sub(began))
+	}
+	if deadline.After(reviewStartTime) {
+		return reviewStartTime
+	}
+	return deadline
+}
+
+// FormatNonWhitelistAskMessage creates the Telegram message asking about a booking outside the whitelist,
+// counting down to the deadline
+func FormatNonWhitelistAskMessage(req *models.ReviewRequest, deadline, now time.Time, loc *time.Location, p *i18n.Printer) render.HTML {
+	minutes := int(deadline.Sub(now).Round(time.Minute) / time.Minute)
+	if minutes < 0 {
+		minutes = 0
+	}
+	return p.T("notify.non_whitelist_ask",
+		projectNameOf(req, p),
+		p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), loc),
+		p.N("unit.minutes", minutes),
+		p.FormatShort(deadline, loc))
+}
+
+func projectNameOf(req *models.ReviewRequest, p *i18n.Printer) render.HTML {
+	if req.ProjectName != nil {
+		return render.Escape(*req.ProjectName)
+	}
+	return p.T("review.unknown_project")
 }
 
 // NewTelegramClient creates a new Telegram bot client
@@ -213,3 +416,36 @@ func GetCalendarEvents(ctx context.Context, reviewerLogin string, from, to time.
 func ExtractBookings(data *requests.CalendarGetEvents_Data) []external.CalendarBooking {
 	return external.ExtractBookings(data)
 }
+
+// ExtractReviewees returns the lowercased logins of the students to review, keyed by booking ID.
+// Bookings without verifiable students, e.g. ones the calendar hides them for, are left out
+func ExtractReviewees(data *requests.CalendarGetEvents_Data) map[string][]string {
+	reviewees := make(map[string][]string)
+	for _, event := range data.CalendarEventS21.GetMyCalendarEvents {
+		for _, b := range event.Bookings {
+			bookingMap, ok := b.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id, _ := bookingMap["id"].(string)
+			info, _ := bookingMap["verifiableInfo"].(map[string]interface{})
+			students, _ := info["verifiableStudents"].([]interface{})
+			for _, s := range students {
+				student, _ := s.(map[string]interface{})
+				if login, ok := student["login"].(string); ok && login != "" && id != "" {
+					reviewees[id] = append(reviewees[id], strings.ToLower(login))
+				}
+			}
+		}
+	}
+	return reviewees
+}
+
+// BookingWindow returns the calendar window checked for new bookings.
+// It starts lookbackHours before now, so bookings made for slots that have just
+// started are still picked up, and ends lookaheadHours after now.
+func BookingWindow(now time.Time, lookbackHours, lookaheadHours int) (time.Time, time.Time) {
+	from := now.Add(-time.Duration(lookbackHours) * time.Hour)
+	to := now.Add(time.Duration(lookaheadHours) * time.Hour)
+	return from, to
+}
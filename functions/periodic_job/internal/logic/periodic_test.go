@@ -13,6 +13,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/availability"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/policy"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/settings"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
 )
 
 // MockLockboxClient is a mock for Lockbox operations
@@ -189,77 +196,6 @@ func TestChangeCalendarSlot_Success(t *testing.T) {
 	assert.True(t, newEnd.After(newStart))
 }
 
-// TestSendNonWhitelistCancelNotification_InvalidUserType tests notification with invalid user type
-func TestSendNonWhitelistCancelNotification_InvalidUserType(t *testing.T) {
-	ctx := context.Background()
-	invalidUser := "not a user pointer"
-	req := getTestReviewRequest()
-
-	err := SendNonWhitelistCancelNotification(ctx, invalidUser, req)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid user type")
-}
-
-// TestSendNonWhitelistCancelNotification_InvalidRequestType tests notification with invalid request type
-func TestSendNonWhitelistCancelNotification_InvalidRequestType(t *testing.T) {
-	ctx := context.Background()
-	user := getTestUser()
-	invalidReq := "not a review request pointer"
-
-	err := SendNonWhitelistCancelNotification(ctx, user, invalidReq)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid review request type")
-}
-
-// TestSendNonWhitelistCancelNotification_WithoutProjectName tests notification without project name
-func TestSendNonWhitelistCancelNotification_WithoutProjectName(t *testing.T) {
-	ctx := context.Background()
-	user := getTestUser()
-	req := getTestReviewRequest()
-	req.ProjectName = nil // No project name
-
-	// This will fail to create Telegram client in test environment
-	// but we can test the logic before that point
-	err := SendNonWhitelistCancelNotification(ctx, user, req)
-	// Expected to fail at Telegram client creation in test env
-	assert.Error(t, err)
-}
-
-// TestSendWhitelistTimeoutNotification_InvalidUserType tests whitelist timeout notification with invalid user type
-func TestSendWhitelistTimeoutNotification_InvalidUserType(t *testing.T) {
-	ctx := context.Background()
-	invalidUser := "not a user pointer"
-	req := getTestReviewRequest()
-
-	err := SendWhitelistTimeoutNotification(ctx, invalidUser, req)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid user type")
-}
-
-// TestSendWhitelistTimeoutNotification_InvalidRequestType tests whitelist timeout notification with invalid request type
-func TestSendWhitelistTimeoutNotification_InvalidRequestType(t *testing.T) {
-	ctx := context.Background()
-	user := getTestUser()
-	invalidReq := "not a review request pointer"
-
-	err := SendWhitelistTimeoutNotification(ctx, user, invalidReq)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid review request type")
-}
-
-// TestSendWhitelistTimeoutNotification_WithoutProjectName tests notification without project name
-func TestSendWhitelistTimeoutNotification_WithoutProjectName(t *testing.T) {
-	ctx := context.Background()
-	user := getTestUser()
-	req := getTestReviewRequest()
-	req.ProjectName = nil // No project name
-
-	// This will fail to create Telegram client in test environment
-	err := SendWhitelistTimeoutNotification(ctx, user, req)
-	// Expected to fail at Telegram client creation in test env
-	assert.Error(t, err)
-}
-
 // TestFormatReviewRequestMessage tests message formatting
 func TestFormatReviewRequestMessage(t *testing.T) {
 	tests := []struct {
@@ -294,7 +230,7 @@ func TestFormatReviewRequestMessage(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := FormatReviewRequestMessage(tt.projectName, tt.reviewStartTime, tt.deadline)
+			result := FormatReviewRequestMessage(tt.projectName, nil, tt.reviewStartTime, tt.deadline, time.UTC, i18n.New(i18n.English))
 
 			for _, substr := range tt.wantContains {
 				assert.Contains(t, result, substr, "Message should contain: %s", substr)
@@ -974,7 +910,7 @@ func TestEdgeCases(t *testing.T) {
 		reviewTime := getTestTime()
 		deadline := reviewTime.Add(30 * time.Minute)
 
-		message := FormatReviewRequestMessage(projectName, reviewTime, deadline)
+		message := FormatReviewRequestMessage(projectName, nil, reviewTime, deadline, time.UTC, i18n.New(i18n.English))
 		assert.Contains(t, message, "Review Request")
 		assert.Contains(t, message, "Project: ")
 	})
@@ -984,7 +920,7 @@ func TestEdgeCases(t *testing.T) {
 		reviewTime := getTestTime()
 		deadline := reviewTime.Add(1 * time.Minute)
 
-		message := FormatReviewRequestMessage(projectName, reviewTime, deadline)
+		message := FormatReviewRequestMessage(projectName, nil, reviewTime, deadline, time.UTC, i18n.New(i18n.English))
 		assert.Contains(t, message, "Please respond by")
 	})
 
@@ -993,7 +929,7 @@ func TestEdgeCases(t *testing.T) {
 		reviewTime := getTestTime()
 		deadline := reviewTime.Add(30 * time.Minute)
 
-		message := FormatReviewRequestMessage(projectName, reviewTime, deadline)
+		message := FormatReviewRequestMessage(projectName, nil, reviewTime, deadline, time.UTC, i18n.New(i18n.English))
 		assert.Contains(t, message, projectName)
 	})
 
@@ -1002,7 +938,7 @@ func TestEdgeCases(t *testing.T) {
 		reviewTime := time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC)
 		deadline := time.Date(2026, 1, 10, 23, 40, 0, 0, time.UTC)
 
-		message := FormatReviewRequestMessage(projectName, reviewTime, deadline)
+		message := FormatReviewRequestMessage(projectName, nil, reviewTime, deadline, time.UTC, i18n.New(i18n.English))
 		assert.Contains(t, message, "Jan 11 00:00 UTC")
 		assert.Contains(t, message, "Jan 10 23:40 UTC")
 	})
@@ -1012,7 +948,7 @@ func TestEdgeCases(t *testing.T) {
 		reviewTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
 		deadline := time.Date(2025, 12, 31, 23, 40, 0, 0, time.UTC)
 
-		message := FormatReviewRequestMessage(projectName, reviewTime, deadline)
+		message := FormatReviewRequestMessage(projectName, nil, reviewTime, deadline, time.UTC, i18n.New(i18n.English))
 		assert.Contains(t, message, "Jan 1 00:00 UTC")
 		assert.Contains(t, message, "Dec 31 23:40 UTC")
 	})
@@ -1114,39 +1050,6 @@ func TestUserSettingsConfigurations(t *testing.T) {
 	})
 }
 
-// TestErrorHandling tests error conditions and error messages
-func TestErrorHandling(t *testing.T) {
-	t.Run("InvalidUserType", func(t *testing.T) {
-		ctx := context.Background()
-		invalidUser := 12345 // Wrong type
-		req := getTestReviewRequest()
-
-		err := SendNonWhitelistCancelNotification(ctx, invalidUser, req)
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid user type")
-	})
-
-	t.Run("InvalidRequestType", func(t *testing.T) {
-		ctx := context.Background()
-		user := getTestUser()
-		invalidReq := 12345 // Wrong type
-
-		err := SendWhitelistTimeoutNotification(ctx, user, invalidReq)
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid review request type")
-	})
-
-	t.Run("BothTypesInvalid", func(t *testing.T) {
-		ctx := context.Background()
-		invalidUser := "string"
-		invalidReq := 12345
-
-		err := SendNonWhitelistCancelNotification(ctx, invalidUser, invalidReq)
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid user type")
-	})
-}
-
 // TestConcurrentOperations tests thread-safety considerations
 func TestConcurrentOperations(t *testing.T) {
 	t.Run("MultipleReviewRequests", func(t *testing.T) {
@@ -1176,7 +1079,7 @@ func BenchmarkFormatReviewRequestMessage(b *testing.B) {
 	deadline := reviewTime.Add(30 * time.Minute)
 
 	for i := 0; i < b.N; i++ {
-		_ = FormatReviewRequestMessage(projectName, reviewTime, deadline)
+		_ = FormatReviewRequestMessage(projectName, nil, reviewTime, deadline, time.UTC, i18n.New(i18n.English))
 	}
 }
 
@@ -1228,7 +1131,7 @@ func TestFunctionInteractions(t *testing.T) {
 		assert.True(t, deadline.Before(reviewTime))
 
 		// Format message
-		message := FormatReviewRequestMessage(projectName, reviewTime, deadline)
+		message := FormatReviewRequestMessage(projectName, nil, reviewTime, deadline, time.UTC, i18n.New(i18n.English))
 		assert.NotEmpty(t, message)
 
 		// Create review request
@@ -1387,3 +1290,330 @@ func TestMockBehavior(t *testing.T) {
 		mockClient.AssertExpectations(t)
 	})
 }
+
+func TestBookingWindow(t *testing.T) {
+	now := time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		lookback  int
+		lookahead int
+		from      time.Time
+		to        time.Time
+	}{
+		{"Defaults", 2, 24, now.Add(-2 * time.Hour), now.Add(24 * time.Hour)},
+		{"NoLookback", 0, 24, now, now.Add(24 * time.Hour)},
+		{"TwoWeeks", 6, 336, now.Add(-6 * time.Hour), now.Add(14 * 24 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := BookingWindow(now, tt.lookback, tt.lookahead)
+			assert.Equal(t, tt.from, from)
+			assert.Equal(t, tt.to, to)
+		})
+	}
+}
+
+// TestFormatMessagesInUserTimezone tests that notification times use the user's zone
+func TestFormatMessagesInUserTimezone(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	reviewTime := time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)
+	deadline := reviewTime.Add(-20 * time.Minute)
+
+	message := FormatReviewRequestMessage("go-concurrency", nil, reviewTime, deadline, moscow, i18n.New(i18n.English))
+	assert.Contains(t, message, "Time: Jan 15 17:30 MSK")
+	assert.Contains(t, message, "Please respond by Jan 15 17:10 MSK.")
+
+	projectName := "go-concurrency"
+	req := &models.ReviewRequest{ProjectName: &projectName, ReviewStartTime: reviewTime.Unix()}
+	assert.Contains(t, FormatNonWhitelistCancelMessage(req, moscow, i18n.New(i18n.English)), "Time: Jan 15 17:30 MSK")
+	assert.Contains(t, FormatWhitelistTimeoutMessage(req, moscow, i18n.New(i18n.English)), "Time: Jan 15 17:30 MSK")
+	assert.Contains(t, FormatWhitelistTimeoutMessage(req, time.UTC, i18n.New(i18n.English)), "Time: Jan 15 14:30 UTC")
+}
+
+// TestFormatMessagesInUserLanguage tests that notifications are rendered in the user's language
+func TestFormatMessagesInUserLanguage(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	ru := i18n.New(i18n.Russian)
+
+	reviewTime := time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)
+	message := FormatReviewRequestMessage("go-concurrency", nil, reviewTime, reviewTime.Add(-20*time.Minute), moscow, ru)
+	assert.Contains(t, message, "<b>Запрос на ревью</b>")
+	assert.Contains(t, message, "Время: 15 янв 17:30 MSK")
+	assert.Contains(t, message, "Ответьте до 15 янв 17:10 MSK.")
+
+	req := &models.ReviewRequest{ReviewStartTime: reviewTime.Unix()}
+	assert.Contains(t, FormatNonWhitelistCancelMessage(req, moscow, ru), "Проект: Неизвестный проект")
+	assert.Contains(t, FormatBlacklistCancelMessage(req, moscow, ru), "Время: 15 янв 17:30 MSK")
+	assert.Contains(t, FormatWhitelistTimeoutMessage(req, moscow, ru), "<b>Время ответа истекло</b>")
+}
+
+// TestFormatMessagesEscapeProjectName tests that project names cannot inject markup
+func TestFormatMessagesEscapeProjectName(t *testing.T) {
+	en := i18n.New(i18n.English)
+	name := "<b>C & *I*</b>"
+	reviewTime := time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)
+
+	message := FormatReviewRequestMessage(name, nil, reviewTime, reviewTime, time.UTC, en)
+	assert.Contains(t, message, "&lt;b&gt;C &amp; *I*&lt;/b&gt;")
+
+	req := &models.ReviewRequest{ProjectName: &name, ReviewStartTime: reviewTime.Unix()}
+	assert.Contains(t, FormatNonWhitelistCancelMessage(req, time.UTC, en), "&lt;b&gt;C &amp; *I*&lt;/b&gt;")
+	assert.Contains(t, FormatBlacklistCancelMessage(req, time.UTC, en), "&lt;b&gt;C &amp; *I*&lt;/b&gt;")
+	assert.Contains(t, FormatPolicyDeclineMessage(req, "deny_list", time.UTC, en), "&lt;b&gt;C &amp; *I*&lt;/b&gt;")
+	assert.Contains(t, FormatWhitelistTimeoutMessage(req, time.UTC, en), "&lt;b&gt;C &amp; *I*&lt;/b&gt;")
+}
+
+// TestFormatLimitMessages tests the notifications about bookings that break a limit
+func TestFormatLimitMessages(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	en := i18n.New(i18n.English)
+
+	projectName := "go-concurrency"
+	reviewTime := time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)
+	req := &models.ReviewRequest{ProjectName: &projectName, ReviewStartTime: reviewTime.Unix()}
+
+	daily := &policy.Limit{Key: settings.DailyCap, Value: 3}
+	message := FormatLimitDeclineMessage(req, daily, moscow, en)
+	assert.Contains(t, message, "Time: Jan 15 17:30 MSK")
+	assert.Contains(t, message, "Your daily cap of 3 reviews is reached on that day.")
+
+	weekly := &policy.Limit{Key: settings.WeeklyCap, Value: 1}
+	assert.Contains(t, FormatLimitAskMessage(req, weekly, moscow, en), "Your weekly cap of 1 review is reached in that week.")
+
+	gap := &policy.Limit{Key: settings.MinGap, Value: 30, Conflict: reviewTime.Add(-15 * time.Minute)}
+	assert.Contains(t, FormatLimitAskMessage(req, gap, moscow, en), "It leaves a break of less than 30 minutes before or after your review at Jan 15 17:15 MSK.")
+	assert.Contains(t, DescribeLimit(gap, moscow, i18n.New(i18n.Russian)), "30 минут")
+
+	hours := &policy.Limit{Key: policy.WorkingHours, Hours: "Mon-Fri 10:00-22:00"}
+	assert.Contains(t, FormatLimitDeclineMessage(req, hours, moscow, en), "It starts outside your working hours (Mon-Fri 10:00-22:00).")
+}
+
+// TestBookingLimits tests that the limits of the user's preferences reach the decision policies
+func TestBookingLimits(t *testing.T) {
+	prefs := store.DefaultUserPreferences("reviewer")
+	limits := BookingLimits(prefs)
+	assert.True(t, limits.Off())
+	assert.Equal(t, policy.Ask, limits.Decision)
+
+	prefs.MaxReviewsPerDay, prefs.MaxReviewsPerWeek, prefs.MinReviewGapMinutes = 2, 8, 45
+	prefs.LimitAction = settings.LimitActionDecline
+	assert.Equal(t, policy.BookingLimits{PerDay: 2, PerWeek: 8, MinGapMinutes: 45, Decision: policy.Decline}, BookingLimits(prefs))
+}
+
+// TestBookingWorkingHours tests that the working hours of the user's preferences reach the decision policies
+func TestBookingWorkingHours(t *testing.T) {
+	prefs := store.DefaultUserPreferences("reviewer")
+	hours, decision := BookingWorkingHours(prefs)
+	assert.Empty(t, hours)
+	assert.Equal(t, policy.Ask, decision)
+
+	prefs.WorkingHours = "Sat 12:00-18:00"
+	prefs.OutsideHoursAction = settings.OutsideHoursDecline
+	hours, decision = BookingWorkingHours(prefs)
+	assert.Equal(t, availability.WorkingHours{time.Saturday: {StartMinute: 720, EndMinute: 1080}}, hours)
+	assert.Equal(t, policy.Decline, decision)
+
+	prefs.WorkingHours = "Funday 12:00-18:00"
+	hours, _ = BookingWorkingHours(prefs)
+	assert.Empty(t, hours, "invalid working hours are ignored")
+}
+
+// TestFormatEntryExpiredMessage tests the notification about a removed temporary entry
+func TestFormatEntryExpiredMessage(t *testing.T) {
+	en := i18n.New(i18n.English)
+
+	entry := &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "C & I"}
+	message := FormatEntryExpiredMessage(entry, en)
+	assert.Contains(t, message, "<b>Whitelist Entry Expired</b>")
+	assert.Contains(t, message, "family C &amp; I was removed from your whitelist")
+
+	entry = &models.WhitelistEntry{EntryType: whitelist.BlacklistType(whitelist.EntryTypeGlob), Name: "CPP*"}
+	assert.Contains(t, FormatEntryExpiredMessage(entry, i18n.New(i18n.Russian)), "glob-шаблон CPP* удалена из чёрного списка")
+}
+
+// TestFormatProjectsSyncedMessage tests the notification about synced completed projects
+func TestFormatProjectsSyncedMessage(t *testing.T) {
+	message := FormatProjectsSyncedMessage([]string{"C2_SimpleBashUtils", "C3_s21_string+"}, i18n.New(i18n.English))
+	assert.Contains(t, message, "<b>Completed Projects Whitelisted</b>")
+	assert.Contains(t, message, "C2_SimpleBashUtils, C3_s21_string+")
+}
+
+// TestReviewKeyboard tests the approve/decline buttons of a review request
+func TestReviewKeyboard(t *testing.T) {
+	keyboard := ReviewKeyboard("req-1", i18n.New(i18n.Russian))
+
+	require.Len(t, keyboard.InlineKeyboard, 1)
+	row := keyboard.InlineKeyboard[0]
+	require.Len(t, row, 2)
+	assert.Equal(t, "✅ Подтвердить", row[0].Text)
+	assert.Equal(t, "APPROVE:req-1", *row[0].CallbackData)
+	assert.Equal(t, "❌ Отклонить", row[1].Text)
+	assert.Equal(t, "DECLINE:req-1", *row[1].CallbackData)
+}
+
+// TestEffectiveSettings tests that family and project overrides apply to a review
+func TestEffectiveSettings(t *testing.T) {
+	base := settings.DefaultUserSettings("alice")
+	overrides := []*settings.Override{
+		{EntryType: models.EntryTypeFamily, Name: "C - I", Key: settings.DeadlineShift, Value: "45"},
+		{EntryType: models.EntryTypeProject, Name: "C2_SimpleBashUtils", Key: settings.CancelDelay, Value: "10"},
+	}
+	project, family := "C2_SimpleBashUtils", "C - I"
+
+	result := EffectiveSettings(base, overrides, &models.ReviewRequest{ProjectName: &project, FamilyLabel: &family})
+	assert.Equal(t, int32(45), result.ResponseDeadlineShiftMinutes)
+	assert.Equal(t, int32(10), result.NonWhitelistCancelDelayMinutes)
+	assert.Equal(t, base.SlotShiftThresholdMinutes, result.SlotShiftThresholdMinutes)
+
+	assert.Same(t, base, EffectiveSettings(base, overrides, &models.ReviewRequest{}), "unknown project")
+	assert.Same(t, base, EffectiveSettings(base, nil, &models.ReviewRequest{ProjectName: &project}), "no overrides")
+}
+
+func TestEffectivePreferences(t *testing.T) {
+	prefs := store.DefaultUserPreferences("alice")
+	overrides := []*settings.Override{
+		{EntryType: models.EntryTypeFamily, Name: "C - I", Key: settings.TimeoutAction, Value: settings.TimeoutApproveWhitelisted},
+		{EntryType: models.EntryTypeProject, Name: "C2_SimpleBashUtils", Key: settings.TimeoutAction, Value: settings.TimeoutApprove},
+	}
+	project, other, family := "C2_SimpleBashUtils", "C3_s21_string+", "C - I"
+
+	result := EffectivePreferences(prefs, overrides, &models.ReviewRequest{ProjectName: &project, FamilyLabel: &family})
+	assert.Equal(t, settings.TimeoutApprove, result.TimeoutAction)
+	result = EffectivePreferences(prefs, overrides, &models.ReviewRequest{ProjectName: &other, FamilyLabel: &family})
+	assert.Equal(t, settings.TimeoutApproveWhitelisted, result.TimeoutAction)
+	assert.Equal(t, settings.TimeoutCancel, prefs.TimeoutAction, "the user's preferences are not changed")
+
+	assert.Same(t, prefs, EffectivePreferences(prefs, overrides, &models.ReviewRequest{}), "unknown project")
+	assert.Same(t, prefs, EffectivePreferences(prefs, nil, &models.ReviewRequest{ProjectName: &project}), "no overrides")
+}
+
+func TestTimeoutOutcome(t *testing.T) {
+	tests := []struct {
+		action      string
+		inWhitelist bool
+		expected    string
+	}{
+		{settings.TimeoutCancel, true, models.StatusAutoCancelled},
+		{settings.TimeoutApprove, false, models.StatusApproved},
+		{settings.TimeoutApproveWhitelisted, true, models.StatusApproved},
+		{settings.TimeoutApproveWhitelisted, false, models.StatusAutoCancelled},
+		{"", true, models.StatusAutoCancelled},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, TimeoutOutcome(tt.action, tt.inWhitelist), "%s whitelisted=%t", tt.action, tt.inWhitelist)
+	}
+	assert.True(t, TimeoutChecksWhitelist(settings.TimeoutApproveWhitelisted))
+	assert.False(t, TimeoutChecksWhitelist(settings.TimeoutApprove))
+
+	projectName := "go-concurrency"
+	req := &models.ReviewRequest{ProjectName: &projectName, ReviewStartTime: time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC).Unix()}
+	message := FormatTimeoutApprovedMessage(req, time.UTC, i18n.New(i18n.English))
+	assert.Contains(t, message, "Time: Jan 15 14:30 UTC")
+	assert.Contains(t, message, "this review was automatically approved")
+}
+
+func TestNewBooking(t *testing.T) {
+	project, family := "C2_SimpleBashUtils", "C - I"
+	req := &models.ReviewRequest{ID: "r1", ProjectName: &project, FamilyLabel: &family, ReviewStartTime: 1700000000}
+	accepted := []*models.ReviewRequest{
+		{ID: "r1", ReviewStartTime: 1700000000},
+		{ID: "r2", ReviewStartTime: 1700003600},
+	}
+	now := time.Unix(1699990000, 0)
+
+	b := NewBooking(req, nil, nil, accepted, time.UTC, now)
+	assert.Equal(t, project, b.ProjectName)
+	assert.Equal(t, family, b.FamilyLabel)
+	assert.Equal(t, timeutil.FromUnixSeconds(1700000000), b.Start)
+	assert.Equal(t, []time.Time{timeutil.FromUnixSeconds(1700003600)}, b.Accepted, "the review itself is left out")
+	assert.Equal(t, now, b.Now)
+
+	b = NewBooking(&models.ReviewRequest{ID: "r3"}, nil, nil, nil, time.UTC, now)
+	assert.Empty(t, b.ProjectName)
+	assert.Empty(t, b.FamilyLabel)
+	assert.Empty(t, b.Accepted)
+}
+
+// TestExtractReviewees tests that the students to review are read from calendar bookings
+func TestExtractReviewees(t *testing.T) {
+	student := func(login string) map[string]interface{} {
+		return map[string]interface{}{"login": login, "isTeamLead": false}
+	}
+	data := &requests.CalendarGetEvents_Data{
+		CalendarEventS21: requests.CalendarGetEvents_Data_CalendarEventS21{
+			GetMyCalendarEvents: []requests.CalendarGetEvents_Data_GetMyCalendarEvent{{
+				Bookings: []interface{}{
+					map[string]interface{}{
+						"id":             "solo",
+						"verifiableInfo": map[string]interface{}{"verifiableStudents": []interface{}{student("Friendly")}},
+					},
+					map[string]interface{}{
+						"id": "team",
+						"verifiableInfo": map[string]interface{}{
+							"verifiableStudents": []interface{}{student("groupmate"), student("careless")},
+							"team":               map[string]interface{}{"name": "the team"},
+						},
+					},
+					map[string]interface{}{"id": "hidden", "verifiableInfo": nil},
+					map[string]interface{}{"id": "no-login", "verifiableInfo": map[string]interface{}{"verifiableStudents": []interface{}{student("")}}},
+					"not a booking",
+				},
+			}},
+		},
+	}
+
+	assert.Equal(t, map[string][]string{
+		"solo": {"friendly"},
+		"team": {"groupmate", "careless"},
+	}, ExtractReviewees(data))
+}
+
+// TestFormatPeerMessages tests that reviewees are shown in approval requests and peer declines
+func TestFormatPeerMessages(t *testing.T) {
+	en := i18n.New(i18n.English)
+	reviewTime := time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)
+
+	message := FormatReviewRequestMessage("go-concurrency", []string{"groupmate", "careless"}, reviewTime, reviewTime.Add(-20*time.Minute), time.UTC, en)
+	assert.Contains(t, message, "Project: go-concurrency\nReviewee: groupmate, careless\nTime: Jan 15 14:30 UTC")
+	assert.NotContains(t, FormatReviewRequestMessage("go-concurrency", nil, reviewTime, reviewTime, time.UTC, en), "Reviewee")
+
+	projectName := "go-concurrency"
+	req := &models.ReviewRequest{ProjectName: &projectName, ReviewStartTime: reviewTime.Unix()}
+	peer := &store.Peer{Login: "careless", Kind: store.PeerBlocked}
+	assert.Contains(t, FormatPeerDeclineMessage(req, peer, time.UTC, en), "You blocked careless, so the booking was cancelled right away.")
+
+	prefs := store.DefaultUserPreferences("reviewer")
+	assert.Equal(t, policy.Ask, BlockedPeerDecision(prefs))
+	prefs.BlockedPeerAction = settings.BlockedPeerDecline
+	assert.Equal(t, policy.Decline, BlockedPeerDecision(prefs))
+}
+
+// TestNonWhitelistAsk tests the approval requests sent for bookings outside the whitelist
+func TestNonWhitelistAsk(t *testing.T) {
+	prefs := store.DefaultUserPreferences("reviewer")
+	assert.False(t, AsksForNonWhitelisted(prefs))
+	prefs.NonWhitelistAction = settings.NonWhitelistAsk
+	assert.True(t, AsksForNonWhitelisted(prefs))
+
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	reviewTime := time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)
+	assert.Equal(t, now.Add(30*time.Minute), PlanNonWhitelistDeadline(reviewTime, 30, 0, 0, time.UTC, now))
+	assert.Equal(t, reviewTime, PlanNonWhitelistDeadline(reviewTime, 180, 0, 0, time.UTC, now), "never after the review starts")
+
+	projectName := "go-concurrency"
+	req := &models.ReviewRequest{ProjectName: &projectName, ReviewStartTime: reviewTime.Unix()}
+	message := FormatNonWhitelistAskMessage(req, now.Add(30*time.Minute), now, time.UTC, i18n.New(i18n.English))
+	assert.Contains(t, message, "Project: go-concurrency\nTime: Jan 15 14:30 UTC")
+	assert.Contains(t, message, "unless you approve it within 30 minutes, by Jan 15 12:30 UTC")
+
+	message = FormatUnaskedCancelMessage(req, time.UTC, i18n.New(i18n.English))
+	assert.Contains(t, message, "Project: go-concurrency\nTime: Jan 15 14:30 UTC")
+	assert.Contains(t, message, "You were not asked about it while paused or in quiet hours")
+}
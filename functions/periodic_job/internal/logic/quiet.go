@@ -0,0 +1,103 @@
+package logic
+
+import (
+	"time"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/timeutil"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+// InQuietHours reports whether t falls into the daily quiet hours [startMinute, endMinute)
+// on the wall clock of loc. Quiet hours may wrap around midnight, e.g. 23:00-07:00.
+// Equal bounds mean no quiet hours.
+func InQuietHours(t time.Time, startMinute, endMinute int32, loc *time.Location) bool {
+	if startMinute == endMinute {
+		return false
+	}
+
+	t = t.In(loc)
+	minute := int32(t.Hour()*60 + t.Minute())
+	if startMinute < endMinute {
+		return minute >= startMinute && minute < endMinute
+	}
+	return minute >= startMinute || minute < endMinute
+}
+
+// AdjustDeadlineForQuietHours moves a decision deadline that falls into quiet hours
+// to the moment the quiet hours begin, so the user is asked before going to sleep
+// rather than timed out while asleep
+func AdjustDeadlineForQuietHours(deadline time.Time, startMinute, endMinute int32, loc *time.Location) time.Time {
+	if !InQuietHours(deadline, startMinute, endMinute, loc) {
+		return deadline
+	}
+
+	return quietHoursStart(deadline, startMinute, loc)
+}
+
+// quietHoursStart returns when the quiet hours t falls into began
+func quietHoursStart(t time.Time, startMinute int32, loc *time.Location) time.Time {
+	local := t.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, int(startMinute), 0, 0, loc)
+	if start.After(t) {
+		// Past midnight in quiet hours that started the evening before
+		start = time.Date(local.Year(), local.Month(), local.Day()-1, 0, int(startMinute), 0, 0, loc)
+	}
+	return start.In(t.Location())
+}
+
+// QuietHoursEnd returns when the quiet hours t falls into end, t itself outside quiet hours
+func QuietHoursEnd(t time.Time, startMinute, endMinute int32, loc *time.Location) time.Time {
+	if !InQuietHours(t, startMinute, endMinute, loc) {
+		return t
+	}
+
+	local := t.In(loc)
+	end := time.Date(local.Year(), local.Month(), local.Day(), 0, int(endMinute), 0, 0, loc)
+	if !end.After(t) {
+		end = time.Date(local.Year(), local.Month(), local.Day()+1, 0, int(endMinute), 0, 0, loc)
+	}
+	return end.In(t.Location())
+}
+
+// AskHeldUntil returns when a question held back while paused or during quiet hours can be
+// sent: once both the pause and the quiet hours are over. ok is false while paused until /resume
+func AskHeldUntil(prefs *store.UserPreferences, now time.Time) (sendAt time.Time, ok bool) {
+	sendAt = now
+	if prefs.IsPaused(now) {
+		if prefs.PausedUntil == nil {
+			return time.Time{}, false
+		}
+		sendAt = time.Unix(*prefs.PausedUntil, 0).In(now.Location())
+	}
+	return QuietHoursEnd(sendAt, prefs.QuietHoursStartMinute, prefs.QuietHoursEndMinute, prefs.Location()), true
+}
+
+// PlanDecisionDeadline returns when the user has to decide on a review starting at reviewStart.
+// Deadlines inside quiet hours are moved to the start of the quiet hours. If that moment has
+// already passed, e.g. because the booking arrived during the night, the original deadline is kept.
+func PlanDecisionDeadline(reviewStart time.Time, shiftMinutes int, quietStart, quietEnd int32, loc *time.Location, now time.Time) time.Time {
+	deadline := timeutil.CalculateDecisionDeadline(reviewStart, shiftMinutes)
+	adjusted := AdjustDeadlineForQuietHours(deadline, quietStart, quietEnd, loc)
+	if adjusted.After(now) {
+		return adjusted
+	}
+	return deadline
+}
+
+// PausedOutcome returns the status a new review moves to under the user's pause policy.
+// Whitelisted reviews are kept by moving them to WHITELISTED as usual. An empty status
+// means the review is queued until the user resumes.
+func PausedOutcome(policy string, inWhitelist bool) string {
+	switch policy {
+	case store.PausePolicyDecline:
+		return models.StatusAutoCancelled
+	case store.PausePolicyWhitelistedOnly:
+		if inWhitelist {
+			return models.StatusWhitelisted
+		}
+		return models.StatusAutoCancelledNotWhitelisted
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,204 @@
+package logic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+func TestInQuietHours(t *testing.T) {
+	tests := []struct {
+		name     string
+		t        time.Time
+		start    int32
+		end      int32
+		expected bool
+	}{
+		{"Disabled", at(3, 0), 0, 0, false},
+		{"SameDayInside", at(14, 0), 13 * 60, 15 * 60, true},
+		{"SameDayStart", at(13, 0), 13 * 60, 15 * 60, true},
+		{"SameDayEnd", at(15, 0), 13 * 60, 15 * 60, false},
+		{"WrappingEvening", at(23, 30), 23 * 60, 7 * 60, true},
+		{"WrappingMorning", at(6, 59), 23 * 60, 7 * 60, true},
+		{"WrappingEnd", at(7, 0), 23 * 60, 7 * 60, false},
+		{"WrappingDay", at(12, 0), 23 * 60, 7 * 60, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, InQuietHours(tt.t, tt.start, tt.end, time.UTC))
+		})
+	}
+}
+
+func TestAdjustDeadlineForQuietHours(t *testing.T) {
+	quietStart, quietEnd := int32(23*60), int32(7*60)
+
+	t.Run("outside quiet hours", func(t *testing.T) {
+		deadline := at(19, 0)
+		assert.Equal(t, deadline, AdjustDeadlineForQuietHours(deadline, quietStart, quietEnd, time.UTC))
+	})
+
+	t.Run("late evening", func(t *testing.T) {
+		assert.Equal(t, at(23, 0), AdjustDeadlineForQuietHours(at(23, 45), quietStart, quietEnd, time.UTC))
+	})
+
+	t.Run("early morning moves to the evening before", func(t *testing.T) {
+		deadline := at(5, 30).AddDate(0, 0, 1)
+		assert.Equal(t, at(23, 0), AdjustDeadlineForQuietHours(deadline, quietStart, quietEnd, time.UTC))
+	})
+
+	t.Run("same day quiet hours", func(t *testing.T) {
+		assert.Equal(t, at(13, 0), AdjustDeadlineForQuietHours(at(14, 10), 13*60, 15*60, time.UTC))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Equal(t, at(3, 0), AdjustDeadlineForQuietHours(at(3, 0), 0, 0, time.UTC))
+	})
+}
+
+func TestPlanDecisionDeadline(t *testing.T) {
+	quietStart, quietEnd := int32(23*60), int32(7*60)
+
+	t.Run("outside quiet hours", func(t *testing.T) {
+		deadline := PlanDecisionDeadline(at(19, 0), 20, quietStart, quietEnd, time.UTC, at(12, 0))
+		assert.Equal(t, at(18, 40), deadline)
+	})
+
+	t.Run("moved before quiet hours", func(t *testing.T) {
+		reviewStart := at(7, 15).AddDate(0, 0, 1)
+		deadline := PlanDecisionDeadline(reviewStart, 30, quietStart, quietEnd, time.UTC, at(12, 0))
+		assert.Equal(t, at(23, 0), deadline)
+	})
+
+	t.Run("quiet hours already started", func(t *testing.T) {
+		reviewStart := at(7, 15).AddDate(0, 0, 1)
+		now := at(23, 30)
+		deadline := PlanDecisionDeadline(reviewStart, 30, quietStart, quietEnd, time.UTC, now)
+		assert.Equal(t, at(6, 45).AddDate(0, 0, 1), deadline)
+	})
+}
+
+func TestQuietHoursEnd(t *testing.T) {
+	quietStart, quietEnd := int32(23*60), int32(7*60)
+
+	assert.Equal(t, at(7, 0).AddDate(0, 0, 1), QuietHoursEnd(at(23, 30), quietStart, quietEnd, time.UTC))
+	assert.Equal(t, at(7, 0), QuietHoursEnd(at(5, 0), quietStart, quietEnd, time.UTC))
+	assert.Equal(t, at(12, 0), QuietHoursEnd(at(12, 0), quietStart, quietEnd, time.UTC), "outside quiet hours")
+	assert.Equal(t, at(3, 0), QuietHoursEnd(at(3, 0), 0, 0, time.UTC), "disabled")
+}
+
+func TestAskHeldUntil(t *testing.T) {
+	prefs := store.DefaultUserPreferences("reviewer")
+	prefs.Timezone = "UTC"
+	prefs.QuietHoursStartMinute, prefs.QuietHoursEndMinute = 23*60, 7*60
+
+	t.Run("not held", func(t *testing.T) {
+		sendAt, ok := AskHeldUntil(prefs, at(12, 0))
+		assert.True(t, ok)
+		assert.Equal(t, at(12, 0), sendAt)
+	})
+
+	t.Run("quiet hours", func(t *testing.T) {
+		sendAt, ok := AskHeldUntil(prefs, at(23, 30))
+		assert.True(t, ok)
+		assert.Equal(t, at(7, 0).AddDate(0, 0, 1), sendAt)
+	})
+
+	t.Run("pause ending in quiet hours", func(t *testing.T) {
+		paused := *prefs
+		until := at(23, 45).Unix()
+		paused.Paused, paused.PausedUntil = true, &until
+		sendAt, ok := AskHeldUntil(&paused, at(12, 0))
+		assert.True(t, ok)
+		assert.Equal(t, at(7, 0).AddDate(0, 0, 1), sendAt)
+	})
+
+	t.Run("paused until resumed", func(t *testing.T) {
+		paused := *prefs
+		paused.Paused = true
+		_, ok := AskHeldUntil(&paused, at(12, 0))
+		assert.False(t, ok)
+	})
+}
+
+func TestPlanNonWhitelistDeadline(t *testing.T) {
+	quietStart, quietEnd := int32(23*60), int32(7*60)
+	reviewStart := at(12, 0).AddDate(0, 0, 1)
+
+	t.Run("outside quiet hours", func(t *testing.T) {
+		deadline := PlanNonWhitelistDeadline(reviewStart, 30, quietStart, quietEnd, time.UTC, at(20, 0))
+		assert.Equal(t, at(20, 30), deadline)
+	})
+
+	t.Run("ends inside quiet hours", func(t *testing.T) {
+		// Asked at 22:50 with 30 minutes to answer: 10 minutes are left before the quiet hours
+		// and 20 after them
+		deadline := PlanNonWhitelistDeadline(reviewStart, 30, quietStart, quietEnd, time.UTC, at(22, 50))
+		assert.Equal(t, at(7, 20).AddDate(0, 0, 1), deadline)
+	})
+
+	t.Run("review starts inside quiet hours", func(t *testing.T) {
+		reviewStart := at(6, 0).AddDate(0, 0, 1)
+		deadline := PlanNonWhitelistDeadline(reviewStart, 30, quietStart, quietEnd, time.UTC, at(22, 50))
+		assert.Equal(t, reviewStart, deadline, "never after the review starts")
+	})
+}
+
+func TestQuietHoursInUserTimezone(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	quietStart, quietEnd := int32(23*60), int32(7*60)
+
+	t.Run("wall clock of the user", func(t *testing.T) {
+		// 21:30 UTC is 00:30 in Moscow
+		assert.True(t, InQuietHours(at(21, 30), quietStart, quietEnd, moscow))
+		assert.False(t, InQuietHours(at(21, 30), quietStart, quietEnd, time.UTC))
+		assert.Equal(t, at(20, 0), AdjustDeadlineForQuietHours(at(21, 30), quietStart, quietEnd, moscow))
+	})
+
+	t.Run("DST", func(t *testing.T) {
+		// Berlin is UTC+1 until Mar 29 2026 02:00 and UTC+2 afterwards
+		winter := time.Date(2026, 3, 28, 22, 30, 0, 0, time.UTC) // 23:30 CET
+		summer := time.Date(2026, 3, 29, 21, 30, 0, 0, time.UTC) // 23:30 CEST
+		assert.True(t, InQuietHours(winter, quietStart, quietEnd, berlin))
+		assert.True(t, InQuietHours(summer, quietStart, quietEnd, berlin))
+		assert.False(t, InQuietHours(time.Date(2026, 3, 29, 5, 30, 0, 0, time.UTC), quietStart, quietEnd, berlin)) // 07:30 CEST
+
+		// A deadline at 01:00 CEST on Mar 30 moves to 23:00 CEST on Mar 29
+		deadline := time.Date(2026, 3, 29, 23, 0, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2026, 3, 29, 21, 0, 0, 0, time.UTC),
+			AdjustDeadlineForQuietHours(deadline, quietStart, quietEnd, berlin).UTC())
+
+		// A deadline at 01:00 CEST on Mar 29 moves to 23:00 CET on Mar 28
+		deadline = time.Date(2026, 3, 28, 23, 0, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2026, 3, 28, 22, 0, 0, 0, time.UTC),
+			AdjustDeadlineForQuietHours(deadline, quietStart, quietEnd, berlin).UTC())
+	})
+}
+
+func TestPausedOutcome(t *testing.T) {
+	tests := []struct {
+		policy      string
+		inWhitelist bool
+		expected    string
+	}{
+		{store.PausePolicyDecline, true, models.StatusAutoCancelled},
+		{store.PausePolicyDecline, false, models.StatusAutoCancelled},
+		{store.PausePolicyWhitelistedOnly, true, models.StatusWhitelisted},
+		{store.PausePolicyWhitelistedOnly, false, models.StatusAutoCancelledNotWhitelisted},
+		{store.PausePolicyQueue, true, ""},
+		{store.PausePolicyQueue, false, ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, PausedOutcome(tt.policy, tt.inWhitelist), "%s whitelisted=%t", tt.policy, tt.inWhitelist)
+	}
+}
@@ -0,0 +1,60 @@
+package logic
+
+import (
+	"time"
+
+	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/projects"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
+)
+
+// SkipSuggested returns the skip function for whitelist.Suggest: families and projects the
+// user dismissed are never suggested again, others not within repeatAfter of the last time
+func SkipSuggested(states []*store.SuggestionState, now time.Time, repeatAfter time.Duration) func(entryType, name string) bool {
+	skipped := make(map[string]bool, len(states))
+	for _, state := range states {
+		if state.Dismissed || now.Sub(time.Unix(state.SuggestedAt, 0)) < repeatAfter {
+			skipped[state.EntryType+":"+state.Name] = true
+		}
+	}
+	return func(entryType, name string) bool {
+		return skipped[entryType+":"+name]
+	}
+}
+
+// FormatSuggestionMessage creates the Telegram message proposing an entry learned from decisions
+func FormatSuggestionMessage(s *whitelist.Suggestion, p *i18n.Printer) render.HTML {
+	total := s.Approved + s.Declined
+	if s.Blacklist() {
+		return p.T("suggest.blacklist", s.Declined, total, whitelist.Describe(p, s.Entry))
+	}
+	return p.T("suggest.whitelist", s.Approved, total, whitelist.Describe(p, s.Entry))
+}
+
+// SuggestionKeyboard creates the buttons of a suggestion about the family or project at pos
+// in catalog. They are handled like the whitelist browser's, so the suggested list comes first
+func SuggestionKeyboard(s *whitelist.Suggestion, catalog *projects.Catalog, pos projects.Position, p *i18n.Printer) tba.InlineKeyboardMarkup {
+	add, block, dismiss := whitelist.ActionAddProject, whitelist.ActionBlacklistProject, whitelist.ActionDismissProject
+	if pos.Project < 0 {
+		add, block, dismiss = whitelist.ActionAddFamily, whitelist.ActionBlacklistFamily, whitelist.ActionDismissFamily
+	}
+	family, project := catalog.IDs(pos)
+	data := func(action string) string {
+		return whitelist.FormatCallbackData(whitelist.Callback{Action: action, Family: family, Project: project})
+	}
+
+	addButton := tba.NewInlineKeyboardButtonData(render.Plain(p.T("suggest.add_whitelist")), data(add))
+	blockButton := tba.NewInlineKeyboardButtonData(render.Plain(p.T("suggest.add_blacklist")), data(block))
+	row := tba.NewInlineKeyboardRow(addButton, blockButton)
+	if s.Blacklist() {
+		row = tba.NewInlineKeyboardRow(blockButton, addButton)
+	}
+
+	return tba.NewInlineKeyboardMarkup(row, tba.NewInlineKeyboardRow(
+		tba.NewInlineKeyboardButtonData(render.Plain(p.T("suggest.dismiss")), data(dismiss)),
+	))
+}
@@ -0,0 +1,97 @@
+package logic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/projects"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
+)
+
+func TestSkipSuggested(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	skip := SkipSuggested([]*store.SuggestionState{
+		{EntryType: models.EntryTypeFamily, Name: "C - I", SuggestedAt: now.Add(-30 * 24 * time.Hour).Unix()},
+		{EntryType: models.EntryTypeFamily, Name: "Go", SuggestedAt: now.Add(-time.Hour).Unix()},
+		{EntryType: models.EntryTypeProject, Name: "DO1_Linux", SuggestedAt: now.Add(-30 * 24 * time.Hour).Unix(), Dismissed: true},
+	}, now, 14*24*time.Hour)
+
+	assert.False(t, skip(models.EntryTypeFamily, "C - I"), "suggested long ago")
+	assert.True(t, skip(models.EntryTypeFamily, "Go"), "suggested recently")
+	assert.True(t, skip(models.EntryTypeProject, "DO1_Linux"), "dismissed")
+	assert.False(t, skip(models.EntryTypeProject, "Go"), "types are told apart")
+}
+
+func TestFormatSuggestionMessage(t *testing.T) {
+	en := i18n.New(i18n.English)
+
+	s := &whitelist.Suggestion{Entry: &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "C & I"}, Approved: 4, Declined: 1}
+	message := FormatSuggestionMessage(s, en)
+	assert.Contains(t, message, "<b>Whitelist Suggestion</b>")
+	assert.Contains(t, message, "approved 4 of 5")
+	assert.Contains(t, message, "family C &amp; I")
+
+	s = &whitelist.Suggestion{Entry: &models.WhitelistEntry{EntryType: whitelist.BlacklistType(models.EntryTypeProject), Name: "DO1_Linux"}, Declined: 3}
+	message = FormatSuggestionMessage(s, en)
+	assert.Contains(t, message, "<b>Blacklist Suggestion</b>")
+	assert.Contains(t, message, "declined 3 of 3")
+}
+
+func TestSuggestionKeyboard(t *testing.T) {
+	en := i18n.New(i18n.English)
+	catalog := projects.New([]*models.ProjectFamily{
+		{FamilyLabel: "DevOps", ProjectName: "DO1_Linux"},
+		{FamilyLabel: "Go", ProjectName: "go-concurrency"},
+	})
+	callback := func(action, family, project string) string {
+		return whitelist.FormatCallbackData(whitelist.Callback{Action: action, Family: projects.ID(family), Project: project})
+	}
+
+	t.Run("Family", func(t *testing.T) {
+		s := &whitelist.Suggestion{Entry: &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "Go"}}
+		keyboard := SuggestionKeyboard(s, catalog, projects.Position{Family: 1, Project: -1}, en)
+
+		require.Len(t, keyboard.InlineKeyboard, 2)
+		row := keyboard.InlineKeyboard[0]
+		require.Len(t, row, 2)
+		assert.Equal(t, callback(whitelist.ActionAddFamily, "Go", ""), *row[0].CallbackData)
+		assert.Equal(t, callback(whitelist.ActionBlacklistFamily, "Go", ""), *row[1].CallbackData)
+		assert.Equal(t, callback(whitelist.ActionDismissFamily, "Go", ""), *keyboard.InlineKeyboard[1][0].CallbackData)
+	})
+
+	t.Run("BlacklistProjectFirst", func(t *testing.T) {
+		s := &whitelist.Suggestion{Entry: &models.WhitelistEntry{EntryType: whitelist.BlacklistType(models.EntryTypeProject), Name: "DO1_Linux"}}
+		keyboard := SuggestionKeyboard(s, catalog, projects.Position{Family: 0, Project: 0}, en)
+
+		project := projects.ID("DO1_Linux")
+		row := keyboard.InlineKeyboard[0]
+		assert.Equal(t, callback(whitelist.ActionBlacklistProject, "DevOps", project), *row[0].CallbackData)
+		assert.Equal(t, callback(whitelist.ActionAddProject, "DevOps", project), *row[1].CallbackData)
+		assert.Equal(t, callback(whitelist.ActionDismissProject, "DevOps", project), *keyboard.InlineKeyboard[1][0].CallbackData)
+	})
+	t.Run("ButtonsSurviveNewProjects", func(t *testing.T) {
+		s := &whitelist.Suggestion{Entry: &models.WhitelistEntry{EntryType: whitelist.BlacklistType(models.EntryTypeProject), Name: "DO1_Linux"}}
+		keyboard := SuggestionKeyboard(s, catalog, projects.Position{Family: 0, Project: 0}, en)
+
+		// Syncing adds projects sorted before the suggested one before the user answers
+		grown := projects.New([]*models.ProjectFamily{
+			{FamilyLabel: "DevOps", ProjectName: "DO0_Intro"},
+			{FamilyLabel: "Algorithms", ProjectName: "A1_Maze"},
+			{FamilyLabel: "DevOps", ProjectName: "DO1_Linux"},
+			{FamilyLabel: "Go", ProjectName: "go-concurrency"},
+		})
+		for _, button := range append(keyboard.InlineKeyboard[0], keyboard.InlineKeyboard[1]...) {
+			data, ok := whitelist.ParseCallbackData(*button.CallbackData)
+			require.True(t, ok)
+			pos, ok := grown.Lookup(data.Family, data.Project)
+			require.True(t, ok)
+			assert.Equal(t, "DO1_Linux", grown.Name(pos), button.Text)
+		}
+	})
+}
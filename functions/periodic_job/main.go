@@ -10,11 +10,30 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/external"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/telegram"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/timeutil"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
 	"github.com/arseniisemenow/review-slot-guard-bot/functions/periodic_job/internal/logic"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/availability"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/policy"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/projects"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/s21"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/timezone"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
+)
+
+// completedSyncInterval is how often the completed projects are read for the whitelist sync
+const completedSyncInterval = 6 * time.Hour
+
+// Whitelist suggestions learn from the decisions of the last suggestionWindow, and a
+// suggestion that was neither taken nor dismissed is repeated after suggestionRepeatAfter
+const (
+	suggestionWindow      = 90 * 24 * time.Hour
+	suggestionRepeatAfter = 14 * 24 * time.Hour
 )
 
 // init initializes the database schema
@@ -24,6 +43,9 @@ func init() {
 		log.Printf("WARNING: Failed to initialize database schema: %v", err)
 		// Don't panic - tables may already exist
 	}
+	if err := store.InitSchema(ctx); err != nil {
+		log.Printf("WARNING: Failed to initialize store schema: %v", err)
+	}
 }
 
 // main function for local testing
@@ -77,6 +99,40 @@ func processUser(ctx context.Context, user *models.User, logger *log.Logger) err
 		return fmt.Errorf("failed to get user settings: %w", err)
 	}
 
+	prefs, err := store.GetUserPreferences(ctx, user.ReviewerLogin)
+	if err != nil {
+		logger.Printf("Failed to get preferences for user %s, using defaults: %v", user.ReviewerLogin, err)
+		prefs = store.DefaultUserPreferences(user.ReviewerLogin)
+	}
+
+	// Default the timezone to the user's campus the first time we see them
+	if prefs.Timezone == "" {
+		prefs.Timezone = detectTimezone(ctx, user, logger)
+	}
+
+	// End a pause whose end date has passed
+	if prefs.Paused && !prefs.IsPaused(time.Now()) {
+		if err := store.SetPause(ctx, user.ReviewerLogin, false, nil); err != nil {
+			logger.Printf("Failed to end pause for user %s: %v", user.ReviewerLogin, err)
+		} else {
+			prefs.Paused = false
+			prefs.PausedUntil = nil
+			notifyUser(ctx, user, prefs, prefs.Printer("").T("pause.ended"), logger)
+			logger.Printf("Pause ended for user %s", user.ReviewerLogin)
+		}
+	}
+
+	// Remove temporary whitelist entries that have expired
+	removeExpiredEntries(ctx, user, prefs, logger)
+
+	// Keep the managed whitelist entries in line with the completed projects
+	syncCompletedProjects(ctx, user, prefs, logger)
+
+	// Deliver messages held back during quiet hours
+	if !logic.InQuietHours(time.Now(), prefs.QuietHoursStartMinute, prefs.QuietHoursEndMinute, prefs.Location()) {
+		deliverHeldMessages(ctx, user, logger)
+	}
+
 	// 2. Get existing review requests in intermediate states
 	intermediateRequests, err := ydb.GetReviewRequestsByUserAndStatus(ctx, user.ReviewerLogin, []string{
 		models.StatusUnknownProjectReview,
@@ -92,43 +148,66 @@ func processUser(ctx context.Context, user *models.User, logger *log.Logger) err
 
 	logger.Printf("User %s has %d intermediate review requests", user.ReviewerLogin, len(intermediateRequests))
 
+	// Settings overridden for a family or project apply to its reviews only
+	overrides, err := store.GetSettingOverrides(ctx, user.ReviewerLogin)
+	if err != nil {
+		logger.Printf("Failed to get setting overrides for user %s, using global settings: %v", user.ReviewerLogin, err)
+	}
+
 	// 3. Process each review request through the state machine
 	for _, req := range intermediateRequests {
-		if err := processReviewRequest(ctx, req, user, settings, logger); err != nil {
+		reqSettings := logic.EffectiveSettings(settings, overrides, req)
+		reqPrefs := logic.EffectivePreferences(prefs, overrides, req)
+		if err := processReviewRequest(ctx, req, user, reqSettings, reqPrefs, logger); err != nil {
 			logger.Printf("Error processing review request %s: %v", req.ID, err)
 		}
 	}
 
 	// 4. Check for new bookings from calendar
-	if err := checkNewBookings(ctx, user, settings, logger); err != nil {
+	if err := checkNewBookings(ctx, user, settings, prefs, logger); err != nil {
 		logger.Printf("Error checking new bookings for user %s: %v", user.ReviewerLogin, err)
 	}
 
+	// 5. Tidy partially booked slots if the user opted in
+	if prefs.SlotHousekeepingMode != store.HousekeepingOff {
+		if err := tidyCalendarSlots(ctx, user, prefs, logger); err != nil {
+			logger.Printf("Error tidying calendar slots for user %s: %v", user.ReviewerLogin, err)
+		}
+	}
+
+	// 6. Open slots from the user's availability templates
+	if err := openAvailabilitySlots(ctx, user, prefs, logger); err != nil {
+		logger.Printf("Error opening availability slots for user %s: %v", user.ReviewerLogin, err)
+	}
+
+	// 7. Suggest whitelist entries learned from the user's decisions
+	suggestEntries(ctx, user, prefs, logger)
+
 	return nil
 }
 
 // processReviewRequest processes a single review request through the state machine
-func processReviewRequest(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, logger *log.Logger) error {
+func processReviewRequest(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, prefs *store.UserPreferences, logger *log.Logger) error {
 	logger.Printf("Processing review request %s (status: %s)", req.ID, req.Status)
 
 	switch req.Status {
 	case models.StatusUnknownProjectReview:
-		return processUnknownProjectReview(ctx, req, user, settings, logger)
+		return processUnknownProjectReview(ctx, req, user, settings, prefs, logger)
 
 	case models.StatusKnownProjectReview:
-		return processKnownProjectReview(ctx, req, user, settings, logger)
+		return processKnownProjectReview(ctx, req, user, settings, prefs, logger)
 
 	case models.StatusWhitelisted:
-		return processWhitelisted(ctx, req, user, settings, logger)
+		return processWhitelisted(ctx, req, user, settings, prefs, logger)
 
 	case models.StatusNotWhitelisted:
-		return processNotWhitelisted(ctx, req, user, settings, logger)
+		return processNotWhitelisted(ctx, req, user, settings, prefs, logger)
 
 	case models.StatusNeedToApprove:
-		return processNeedToApprove(ctx, req, user, settings, logger)
+		return processNeedToApprove(ctx, req, user, settings, prefs, logger)
 
 	case models.StatusWaitingForApprove:
-		return processWaitingForApprove(ctx, req, user, settings, logger)
+		return processWaitingForApprove(ctx, req, user, settings, prefs, logger)
 
 	default:
 		return fmt.Errorf("unexpected status: %s", req.Status)
@@ -136,7 +215,7 @@ func processReviewRequest(ctx context.Context, req *models.ReviewRequest, user *
 }
 
 // processUnknownProjectReview: Resolve project from notification
-func processUnknownProjectReview(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, logger *log.Logger) error {
+func processUnknownProjectReview(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, prefs *store.UserPreferences, logger *log.Logger) error {
 	// Step 3a: Extract project name from notification
 	notificationID := ""
 	if req.NotificationID != nil {
@@ -170,47 +249,79 @@ func processUnknownProjectReview(ctx context.Context, req *models.ReviewRequest,
 	}
 
 	logger.Printf("Review request %s: UNKNOWN_PROJECT_REVIEW -> KNOWN_PROJECT_REVIEW", req.ID)
+
+	// Bookings a policy declines, such as blacklisted projects, are cancelled in the same run.
+	// On errors the request stays KNOWN_PROJECT_REVIEW and the chain runs again there
+	req.ProjectName = &projectName
+	req.FamilyLabel = &familyLabel
+	result, err := decide(ctx, req, user, prefs, logger)
+	if err != nil {
+		logger.Printf("Failed to run decision policies for review request %s: %v", req.ID, err)
+		return nil
+	}
+	if result.Decision == policy.Decline && result.Policy != policy.Default {
+		return cancelDeclined(ctx, req, user, settings, prefs, result, models.StatusKnownProjectReview, logger)
+	}
 	return nil
 }
 
-// processKnownProjectReview: Check whitelist and time proximity
-func processKnownProjectReview(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, logger *log.Logger) error {
-	projectName := ""
-	if req.ProjectName != nil {
-		projectName = *req.ProjectName
+// processKnownProjectReview: Run the decision policies and check time proximity
+func processKnownProjectReview(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, prefs *store.UserPreferences, logger *log.Logger) error {
+	// Step 4: Ask the user's decision policies, by default the blacklist, working hours and limits first
+	result, err := decide(ctx, req, user, prefs, logger)
+	if err != nil {
+		return err
 	}
-
-	familyLabel := ""
-	if req.FamilyLabel != nil {
-		familyLabel = *req.FamilyLabel
+	logger.Printf("Review request %s: policy %s decided %s", req.ID, result.Policy, result.Decision)
+	if result.Match != nil {
+		logger.Printf("Review request %s: matches %s entry %s %q", req.ID, result.Policy, result.Match.Entry.EntryType, result.Match.Entry.Name)
+	}
+	if result.Rule != nil {
+		logger.Printf("Review request %s: matches rule %q (%s)", req.ID, result.Rule.Expression, result.Rule.Action)
+	}
+	if result.Limit != nil {
+		logger.Printf("Review request %s: breaks limit %s", req.ID, result.Limit.Key)
 	}
 
-	// Step 4: Check if in whitelist
-	inWhitelist, err := ydb.IsInWhitelist(ctx, user.ReviewerLogin, projectName, familyLabel)
-	if err != nil {
-		return fmt.Errorf("failed to check whitelist: %w", err)
+	if result.Decision == policy.Decline && result.Policy != policy.Default {
+		return cancelDeclined(ctx, req, user, settings, prefs, result, req.Status, logger)
 	}
+	recordDecisionPolicy(ctx, req, result, logger)
 
 	reviewStartTime := timeutil.FromUnixSeconds(req.ReviewStartTime)
 
+	// While paused, new reviews follow the pause policy instead of asking the user
+	if prefs.IsPaused(time.Now()) {
+		return applyPausePolicy(ctx, req, user, prefs, result.Decision == policy.Approve, logger)
+	}
+
 	// Step 5: Check if review is within decision threshold
-	deadline := timeutil.CalculateDecisionDeadline(reviewStartTime, int(settings.ResponseDeadlineShiftMinutes))
+	deadline := logic.PlanDecisionDeadline(reviewStartTime, int(settings.ResponseDeadlineShiftMinutes),
+		prefs.QuietHoursStartMinute, prefs.QuietHoursEndMinute, prefs.Location(), time.Now())
 	minutesUntilDeadline := timeutil.MinutesUntil(deadline)
 
 	// Check if we need to ask user for decision NOW
 	needToAskNow := minutesUntilDeadline <= 0 || timeutil.ShouldShiftSlot(reviewStartTime, int(settings.SlotShiftThresholdMinutes))
 
-	if needToAskNow {
+	if needToAskNow || result.Decision == policy.Ask {
 		// Step 5b: Transition to NEED_TO_APPROVE
 		err = ydb.UpdateReviewRequestStatus(ctx, req.ID, models.StatusNeedToApprove, nil)
 		if err != nil {
 			return fmt.Errorf("failed to update status: %w", err)
 		}
-		logger.Printf("Review request %s: KNOWN_PROJECT_REVIEW -> NEED_TO_APPROVE (deadline approaching)", req.ID)
+		reason := "deadline approaching"
+		if !needToAskNow {
+			reason = "asked by policy " + result.Policy
+		}
+		// The approval request follows on the next run; say first why the booking was not kept
+		if result.Limit != nil {
+			notifyUser(ctx, user, prefs, logic.FormatLimitAskMessage(req, result.Limit, prefs.Location(), prefs.Printer("")), logger)
+		}
+		logger.Printf("Review request %s: KNOWN_PROJECT_REVIEW -> NEED_TO_APPROVE (%s)", req.ID, reason)
 		return nil
 	}
 
-	if inWhitelist {
+	if result.Decision == policy.Approve {
 		// Step 5a: Transition to WHITELISTED
 		err = ydb.UpdateReviewRequestStatus(ctx, req.ID, models.StatusWhitelisted, nil)
 		if err != nil {
@@ -230,8 +341,58 @@ func processKnownProjectReview(ctx context.Context, req *models.ReviewRequest, u
 	return nil
 }
 
+// decide runs the user's decision policy chain on a review of a known project
+func decide(ctx context.Context, req *models.ReviewRequest, user *models.User, prefs *store.UserPreferences, logger *log.Logger) (policy.Result, error) {
+	now := time.Now()
+	entries, presets, err := whitelist.Effective(ctx, user.ReviewerLogin, now)
+	if err != nil {
+		return policy.Result{}, fmt.Errorf("failed to check whitelist: %w", err)
+	}
+	accepted, err := ydb.GetReviewRequestsByUserAndStatus(ctx, user.ReviewerLogin, []string{models.StatusApproved, models.StatusWhitelisted})
+	if err != nil {
+		return policy.Result{}, fmt.Errorf("failed to get accepted reviews: %w", err)
+	}
+
+	decisionRules, err := store.GetDecisionRules(ctx, user.ReviewerLogin)
+	if err != nil {
+		return policy.Result{}, fmt.Errorf("failed to get decision rules: %w", err)
+	}
+	peers, err := store.GetPeers(ctx, user.ReviewerLogin)
+	if err != nil {
+		return policy.Result{}, fmt.Errorf("failed to get peers: %w", err)
+	}
+
+	// Reviewees only refine the decision, so a booking without them is decided as unknown
+	reviewees, err := store.GetReviewees(ctx, req.ID)
+	if err != nil {
+		logger.Printf("Failed to get reviewees of review request %s: %v", req.ID, err)
+	}
+
+	names, err := policy.ParseChain(prefs.DecisionPolicies)
+	if err != nil {
+		logger.Printf("Invalid decision policies %q of user %s, using the default chain: %v", prefs.DecisionPolicies, user.ReviewerLogin, err)
+		names = policy.DefaultChain
+	}
+	booking := logic.NewBooking(req, entries, presets, accepted, prefs.Location(), now)
+	booking.Rules = decisionRules
+	booking.Limits = logic.BookingLimits(prefs)
+	booking.WorkingHours, booking.OutsideHours = logic.BookingWorkingHours(prefs)
+	booking.Reviewees = reviewees
+	booking.Peers = peers
+	booking.BlockedPeers = logic.BlockedPeerDecision(prefs)
+	return policy.NewChain(names).Decide(booking), nil
+}
+
+// recordDecisionPolicy stores the policy that decided on a review. The decision itself
+// does not depend on it, so failures are only logged
+func recordDecisionPolicy(ctx context.Context, req *models.ReviewRequest, result policy.Result, logger *log.Logger) {
+	if err := store.SetDecisionPolicy(ctx, req.ID, result.Policy); err != nil {
+		logger.Printf("Failed to record decision policy of review request %s: %v", req.ID, err)
+	}
+}
+
 // processWhitelisted: Check if slot needs shifting
-func processWhitelisted(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, logger *log.Logger) error {
+func processWhitelisted(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, prefs *store.UserPreferences, logger *log.Logger) error {
 	reviewStartTime := timeutil.FromUnixSeconds(req.ReviewStartTime)
 
 	// Step 6: Check if slot should be shifted
@@ -282,52 +443,177 @@ func processWhitelisted(ctx context.Context, req *models.ReviewRequest, user *mo
 	return nil
 }
 
-// processNotWhitelisted: Check cancel timeout
-func processNotWhitelisted(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, logger *log.Logger) error {
+// processNotWhitelisted: Check cancel timeout, or ask about the booking first
+func processNotWhitelisted(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, prefs *store.UserPreferences, logger *log.Logger) error {
 	if req.NonWhitelistCancelAt == nil {
 		return fmt.Errorf("non_whitelist_cancel_at is nil for NOT_WHITELISTED review")
 	}
 
+	// Ask instead of cancelling while there is time left to answer before the review starts
+	unasked := false
+	if logic.AsksForNonWhitelisted(prefs) {
+		now := time.Now()
+		deadline := logic.PlanNonWhitelistDeadline(timeutil.FromUnixSeconds(req.ReviewStartTime), int(prefs.NonWhitelistDeadlineMinutes),
+			prefs.QuietHoursStartMinute, prefs.QuietHoursEndMinute, prefs.Location(), now)
+		if deadline.After(now) {
+			return askNonWhitelisted(ctx, req, user, settings, prefs, deadline, logger)
+		}
+
+		// The review started while the question was held, e.g. paused until /resume
+		held, err := store.NonWhitelistAskHeld(ctx, req.ID)
+		if err != nil {
+			logger.Printf("Failed to check held question of review request %s: %v", req.ID, err)
+		}
+		unasked = held
+	}
+
 	cancelTime := timeutil.FromUnixSeconds(*req.NonWhitelistCancelAt)
 
 	// Check if cancel time has passed
 	if time.Now().After(cancelTime) {
-		// Send notification if enabled
-		if settings.NotifyNonWhitelistCancel {
-			if err := logic.SendNonWhitelistCancelNotification(ctx, user, req); err != nil {
-				logger.Printf("Failed to send cancel notification: %v", err)
-			}
+		return cancelNotWhitelisted(ctx, req, user, settings, prefs, models.StatusNotWhitelisted, unasked, logger)
+	}
+
+	return nil
+}
+
+// askNonWhitelisted sends the approval request for a booking outside the whitelist and waits
+// for the answer until deadline, like for any other review
+func askNonWhitelisted(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, prefs *store.UserPreferences, deadline time.Time, logger *log.Logger) error {
+	// Hold the question while paused or during quiet hours, it is sent once they are over
+	now := time.Now()
+	if prefs.IsPaused(now) || logic.InQuietHours(now, prefs.QuietHoursStartMinute, prefs.QuietHoursEndMinute, prefs.Location()) {
+		if err := store.HoldNonWhitelistAsk(ctx, req.ID, now.Unix()); err != nil {
+			logger.Printf("Failed to record held question of review request %s: %v", req.ID, err)
 		}
 
-		// Cancel the slot
-		if err := logic.CancelCalendarSlot(ctx, user.ReviewerLogin, req.CalendarSlotID); err != nil {
-			logger.Printf("Failed to cancel slot %s: %v", req.CalendarSlotID, err)
+		// A review starting before the question can be sent is cancelled now rather than once it started
+		sendAt, ok := logic.AskHeldUntil(prefs, now)
+		if ok && !timeutil.FromUnixSeconds(req.ReviewStartTime).After(sendAt) {
+			return cancelNotWhitelisted(ctx, req, user, settings, prefs, models.StatusNotWhitelisted, true, logger)
 		}
 
-		// Transition to AUTO_CANCELLED_NOT_WHITELISTED
-		now := time.Now().Unix()
-		err := ydb.UpdateReviewRequestStatus(ctx, req.ID, models.StatusAutoCancelledNotWhitelisted, &now)
-		if err != nil {
-			return fmt.Errorf("failed to update status: %w", err)
+		logger.Printf("Review request %s: holding non-whitelist approval request (paused or quiet hours)", req.ID)
+		return nil
+	}
+
+	p := prefs.Printer("")
+	message := logic.FormatNonWhitelistAskMessage(req, deadline, now, prefs.Location(), p)
+
+	// Send message with buttons
+	telegramClient, err := telegram.NewBotClientFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to create Telegram client: %w", err)
+	}
+
+	keyboard := logic.ReviewKeyboard(req.ID, p)
+	messageID, err := render.Send(telegramClient.GetBot(), user.TelegramChatID, message, &keyboard)
+	if err != nil {
+		return fmt.Errorf("failed to send Telegram message: %w", err)
+	}
+
+	// The cancel time stays set, so an unanswered question cancels the booking as not whitelisted
+	err = ydb.UpdateReviewRequestToWaitingForApprove(ctx, req.ID, deadline.Unix(), fmt.Sprintf("%d", messageID))
+	if err != nil {
+		return fmt.Errorf("failed to update review request: %w", err)
+	}
+
+	logger.Printf("Review request %s: NOT_WHITELISTED -> WAITING_FOR_APPROVE", req.ID)
+	return nil
+}
+
+// cancelNotWhitelisted cancels a booking outside the whitelist. unasked marks bookings the user
+// was to be asked about but the question was held until it was too late
+func cancelNotWhitelisted(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, prefs *store.UserPreferences, fromStatus string, unasked bool, logger *log.Logger) error {
+	// Send notification if enabled, held back during quiet hours
+	if settings.NotifyNonWhitelistCancel {
+		message := logic.FormatNonWhitelistCancelMessage(req, prefs.Location(), prefs.Printer(""))
+		if unasked {
+			message = logic.FormatUnaskedCancelMessage(req, prefs.Location(), prefs.Printer(""))
 		}
-		logger.Printf("Review request %s: NOT_WHITELISTED -> AUTO_CANCELLED_NOT_WHITELISTED", req.ID)
+		notifyUser(ctx, user, prefs, message, logger)
+	}
+
+	// Cancel the slot
+	if err := logic.CancelCalendarSlot(ctx, user.ReviewerLogin, req.CalendarSlotID); err != nil {
+		logger.Printf("Failed to cancel slot %s: %v", req.CalendarSlotID, err)
 	}
 
+	// Transition to AUTO_CANCELLED_NOT_WHITELISTED
+	now := time.Now().Unix()
+	err := ydb.UpdateReviewRequestStatus(ctx, req.ID, models.StatusAutoCancelledNotWhitelisted, &now)
+	if err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	logger.Printf("Review request %s: %s -> AUTO_CANCELLED_NOT_WHITELISTED", req.ID, fromStatus)
+	return nil
+}
+
+// cancelDeclined cancels a booking declined by a decision policy right away,
+// without waiting for the non-whitelist cancel delay
+func cancelDeclined(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, prefs *store.UserPreferences, result policy.Result, fromStatus string, logger *log.Logger) error {
+	logger.Printf("Review request %s: declined by policy %s", req.ID, result.Policy)
+
+	// Blacklisted bookings keep their own status and message
+	status := models.StatusAutoCancelled
+	text := logic.FormatPolicyDeclineMessage(req, result.Policy, prefs.Location(), prefs.Printer(""))
+	switch {
+	case result.Policy == policy.DenyList:
+		status = whitelist.StatusAutoCancelledBlacklisted
+		text = logic.FormatBlacklistCancelMessage(req, prefs.Location(), prefs.Printer(""))
+	case result.Limit != nil:
+		text = logic.FormatLimitDeclineMessage(req, result.Limit, prefs.Location(), prefs.Printer(""))
+	case result.Peer != nil:
+		text = logic.FormatPeerDeclineMessage(req, result.Peer, prefs.Location(), prefs.Printer(""))
+	}
+
+	// Send notification if enabled, held back during quiet hours
+	if settings.NotifyNonWhitelistCancel {
+		notifyUser(ctx, user, prefs, text, logger)
+	}
+
+	// Cancel the slot
+	if err := logic.CancelCalendarSlot(ctx, user.ReviewerLogin, req.CalendarSlotID); err != nil {
+		logger.Printf("Failed to cancel slot %s: %v", req.CalendarSlotID, err)
+	}
+
+	// Transition to the cancelled status
+	now := time.Now().Unix()
+	err := ydb.UpdateReviewRequestStatus(ctx, req.ID, status, &now)
+	if err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	recordDecisionPolicy(ctx, req, result, logger)
+	logger.Printf("Review request %s: %s -> %s", req.ID, fromStatus, status)
 	return nil
 }
 
 // processNeedToApprove: Send Telegram message with buttons
-func processNeedToApprove(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, logger *log.Logger) error {
-	projectName := "Unknown Project"
+func processNeedToApprove(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, prefs *store.UserPreferences, logger *log.Logger) error {
+	p := prefs.Printer("")
+	projectName := render.Plain(p.T("review.unknown_project"))
 	if req.ProjectName != nil {
 		projectName = *req.ProjectName
 	}
 
+	// Hold the question while paused or during quiet hours
+	now := time.Now()
+	if prefs.IsPaused(now) || logic.InQuietHours(now, prefs.QuietHoursStartMinute, prefs.QuietHoursEndMinute, prefs.Location()) {
+		logger.Printf("Review request %s: holding approval request (paused or quiet hours)", req.ID)
+		return nil
+	}
+
 	reviewStartTime := timeutil.FromUnixSeconds(req.ReviewStartTime)
-	deadline := timeutil.CalculateDecisionDeadline(reviewStartTime, int(settings.ResponseDeadlineShiftMinutes))
+	deadline := logic.PlanDecisionDeadline(reviewStartTime, int(settings.ResponseDeadlineShiftMinutes),
+		prefs.QuietHoursStartMinute, prefs.QuietHoursEndMinute, prefs.Location(), now)
+
+	reviewees, err := store.GetReviewees(ctx, req.ID)
+	if err != nil {
+		logger.Printf("Failed to get reviewees of review request %s: %v", req.ID, err)
+	}
 
 	// Create Telegram message
-	message := logic.FormatReviewRequestMessage(projectName, reviewStartTime, deadline)
+	message := logic.FormatReviewRequestMessage(projectName, reviewees, reviewStartTime, deadline, prefs.Location(), p)
 
 	// Send message with buttons
 	telegramClient, err := telegram.NewBotClientFromEnv()
@@ -335,10 +621,8 @@ func processNeedToApprove(ctx context.Context, req *models.ReviewRequest, user *
 		return fmt.Errorf("failed to create Telegram client: %w", err)
 	}
 
-	approveData := fmt.Sprintf("APPROVE:%s", req.ID)
-	declineData := fmt.Sprintf("DECLINE:%s", req.ID)
-
-	messageID, err := telegramClient.SendTwoButtonKeyboard(user.TelegramChatID, message, approveData, declineData)
+	keyboard := logic.ReviewKeyboard(req.ID, p)
+	messageID, err := render.Send(telegramClient.GetBot(), user.TelegramChatID, message, &keyboard)
 	if err != nil {
 		return fmt.Errorf("failed to send Telegram message: %w", err)
 	}
@@ -354,20 +638,43 @@ func processNeedToApprove(ctx context.Context, req *models.ReviewRequest, user *
 }
 
 // processWaitingForApprove: Check if deadline has passed
-func processWaitingForApprove(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, logger *log.Logger) error {
+func processWaitingForApprove(ctx context.Context, req *models.ReviewRequest, user *models.User, settings *models.UserSettings, prefs *store.UserPreferences, logger *log.Logger) error {
 	if req.DecisionDeadline == nil {
 		return fmt.Errorf("decision_deadline is nil for WAITING_FOR_APPROVE review")
 	}
 
 	deadline := timeutil.FromUnixSeconds(*req.DecisionDeadline)
 
+	// Never time out while the user is asleep
+	if logic.InQuietHours(time.Now(), prefs.QuietHoursStartMinute, prefs.QuietHoursEndMinute, prefs.Location()) {
+		return nil
+	}
+
 	// Check if deadline has passed
 	if time.Now().After(deadline) {
+		// Bookings outside the whitelist were only asked about, silence cancels them
+		if req.NonWhitelistCancelAt != nil {
+			return cancelNotWhitelisted(ctx, req, user, settings, prefs, models.StatusWaitingForApprove, false, logger)
+		}
+
+		// Silence may mean yes, depending on the timeout action of the review's project
+		status := logic.TimeoutOutcome(prefs.TimeoutAction, timeoutWhitelisted(ctx, req, user, prefs, logger))
+		if status == models.StatusApproved {
+			if settings.NotifyWhitelistTimeout {
+				notifyUser(ctx, user, prefs, logic.FormatTimeoutApprovedMessage(req, prefs.Location(), prefs.Printer("")), logger)
+			}
+
+			now := time.Now().Unix()
+			if err := ydb.UpdateReviewRequestStatus(ctx, req.ID, models.StatusApproved, &now); err != nil {
+				return fmt.Errorf("failed to update status: %w", err)
+			}
+			logger.Printf("Review request %s: WAITING_FOR_APPROVE -> APPROVED (deadline passed, %s)", req.ID, prefs.TimeoutAction)
+			return nil
+		}
+
 		// Send timeout notification if enabled
 		if settings.NotifyWhitelistTimeout {
-			if err := logic.SendWhitelistTimeoutNotification(ctx, user, req); err != nil {
-				logger.Printf("Failed to send timeout notification: %v", err)
-			}
+			notifyUser(ctx, user, prefs, logic.FormatWhitelistTimeoutMessage(req, prefs.Location(), prefs.Printer("")), logger)
 		}
 
 		// Cancel the slot
@@ -387,19 +694,264 @@ func processWaitingForApprove(ctx context.Context, req *models.ReviewRequest, us
 	return nil
 }
 
+// timeoutWhitelisted reports whether a review is whitelisted when the timeout action asks.
+// Failed lookups count as not whitelisted, so the review is cancelled as before
+func timeoutWhitelisted(ctx context.Context, req *models.ReviewRequest, user *models.User, prefs *store.UserPreferences, logger *log.Logger) bool {
+	if !logic.TimeoutChecksWhitelist(prefs.TimeoutAction) || req.ProjectName == nil {
+		return false
+	}
+	familyLabel := ""
+	if req.FamilyLabel != nil {
+		familyLabel = *req.FamilyLabel
+	}
+	match, err := whitelist.Lookup(ctx, user.ReviewerLogin, *req.ProjectName, familyLabel)
+	if err != nil {
+		logger.Printf("Failed to check whitelist of review request %s on timeout: %v", req.ID, err)
+		return false
+	}
+	return match != nil && !match.Blacklisted
+}
+
+// applyPausePolicy moves a review of a paused user according to the pause policy
+func applyPausePolicy(ctx context.Context, req *models.ReviewRequest, user *models.User, prefs *store.UserPreferences, inWhitelist bool, logger *log.Logger) error {
+	status := logic.PausedOutcome(prefs.PausePolicy, inWhitelist)
+	if status == "" {
+		logger.Printf("Review request %s: queued while user %s is paused", req.ID, user.ReviewerLogin)
+		return nil
+	}
+
+	if status == models.StatusWhitelisted {
+		if err := ydb.UpdateReviewRequestStatus(ctx, req.ID, status, nil); err != nil {
+			return fmt.Errorf("failed to update status: %w", err)
+		}
+		logger.Printf("Review request %s: KNOWN_PROJECT_REVIEW -> WHITELISTED (paused, %s)", req.ID, prefs.PausePolicy)
+		return nil
+	}
+
+	if err := logic.CancelCalendarSlot(ctx, user.ReviewerLogin, req.CalendarSlotID); err != nil {
+		logger.Printf("Failed to cancel slot %s: %v", req.CalendarSlotID, err)
+	}
+
+	now := time.Now().Unix()
+	if err := ydb.UpdateReviewRequestStatus(ctx, req.ID, status, &now); err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	logger.Printf("Review request %s: KNOWN_PROJECT_REVIEW -> %s (paused, %s)", req.ID, status, prefs.PausePolicy)
+	return nil
+}
+
+// detectTimezone stores the zone of the user's campus as their timezone.
+// Lookup failures fall back to timezone.DefaultZone without storing it, so detection is retried
+func detectTimezone(ctx context.Context, user *models.User, logger *log.Logger) string {
+	campus, err := s21.GetCampusName(ctx, user.ReviewerLogin)
+	if err != nil {
+		logger.Printf("Failed to get campus for user %s: %v", user.ReviewerLogin, err)
+		return timezone.DefaultZone
+	}
+
+	zone := timezone.ForCampus(campus)
+	if err := store.UpdateTextSetting(ctx, user.ReviewerLogin, "timezone", zone); err != nil {
+		logger.Printf("Failed to store timezone for user %s: %v", user.ReviewerLogin, err)
+	}
+	logger.Printf("User %s: timezone set to %s (campus %s)", user.ReviewerLogin, zone, campus)
+	return zone
+}
+
+// notifyUser sends a Telegram message, or holds it until quiet hours are over
+func notifyUser(ctx context.Context, user *models.User, prefs *store.UserPreferences, text render.HTML, logger *log.Logger) {
+	if logic.InQuietHours(time.Now(), prefs.QuietHoursStartMinute, prefs.QuietHoursEndMinute, prefs.Location()) {
+		msg := &store.HeldMessage{
+			ReviewerLogin: user.ReviewerLogin,
+			ID:            uuid.New().String(),
+			Text:          string(text),
+			CreatedAt:     time.Now().Unix(),
+		}
+		if err := store.HoldMessage(ctx, msg); err != nil {
+			logger.Printf("Failed to hold message for user %s: %v", user.ReviewerLogin, err)
+		}
+		return
+	}
+
+	bot, err := telegram.NewBotClientFromEnv()
+	if err != nil {
+		logger.Printf("Failed to create Telegram client: %v", err)
+		return
+	}
+	if _, err := render.Send(bot.GetBot(), user.TelegramChatID, text, nil); err != nil {
+		logger.Printf("Failed to send message to user %s: %v", user.ReviewerLogin, err)
+	}
+}
+
+// removeExpiredEntries deletes the user's expired temporary entries and tells the user about each
+func removeExpiredEntries(ctx context.Context, user *models.User, prefs *store.UserPreferences, logger *log.Logger) {
+	expired, err := whitelist.RemoveExpired(ctx, user.ReviewerLogin, time.Now())
+	if err != nil {
+		logger.Printf("Failed to remove expired whitelist entries for user %s: %v", user.ReviewerLogin, err)
+	}
+
+	for _, entry := range expired {
+		notifyUser(ctx, user, prefs, logic.FormatEntryExpiredMessage(entry, prefs.Printer("")), logger)
+		logger.Printf("Whitelist entry %s %q of user %s expired", entry.EntryType, entry.Name, user.ReviewerLogin)
+	}
+}
+
+// syncCompletedProjects whitelists the user's newly completed projects, at most once per
+// completedSyncInterval, and removes the managed entries once the user opts out
+func syncCompletedProjects(ctx context.Context, user *models.User, prefs *store.UserPreferences, logger *log.Logger) {
+	now := time.Now()
+
+	if !prefs.SyncCompletedProjects {
+		if prefs.CompletedSyncedAt == nil {
+			return
+		}
+		if err := whitelist.RemoveManaged(ctx, user.ReviewerLogin); err != nil {
+			logger.Printf("Failed to remove managed whitelist entries for user %s: %v", user.ReviewerLogin, err)
+			return
+		}
+		if err := store.SetCompletedSyncedAt(ctx, user.ReviewerLogin, nil); err != nil {
+			logger.Printf("Failed to reset completed project sync for user %s: %v", user.ReviewerLogin, err)
+		}
+		logger.Printf("Removed managed whitelist entries of user %s", user.ReviewerLogin)
+		return
+	}
+
+	if prefs.CompletedSyncedAt != nil && now.Sub(time.Unix(*prefs.CompletedSyncedAt, 0)) < completedSyncInterval {
+		return
+	}
+
+	completed, err := s21.GetCompletedProjects(ctx, user.ReviewerLogin)
+	if err != nil {
+		logger.Printf("Failed to get completed projects for user %s: %v", user.ReviewerLogin, err)
+		return
+	}
+
+	added, err := whitelist.Sync(ctx, user.ReviewerLogin, completed)
+	if err != nil {
+		logger.Printf("Failed to sync completed projects for user %s: %v", user.ReviewerLogin, err)
+	}
+	if len(added) > 0 {
+		notifyUser(ctx, user, prefs, logic.FormatProjectsSyncedMessage(added, prefs.Printer("")), logger)
+		logger.Printf("Whitelisted %d completed projects of user %s", len(added), user.ReviewerLogin)
+	}
+	if err != nil {
+		return
+	}
+
+	syncedAt := now.Unix()
+	if err := store.SetCompletedSyncedAt(ctx, user.ReviewerLogin, &syncedAt); err != nil {
+		logger.Printf("Failed to store completed project sync time for user %s: %v", user.ReviewerLogin, err)
+	}
+}
+
+// suggestEntries sends at most one suggestion per run to whitelist or blacklist a family or
+// project the user keeps approving or declining by hand. Suggestions are not held back
+// during quiet hours, they simply wait for the next run
+func suggestEntries(ctx context.Context, user *models.User, prefs *store.UserPreferences, logger *log.Logger) {
+	now := time.Now()
+	if logic.InQuietHours(now, prefs.QuietHoursStartMinute, prefs.QuietHoursEndMinute, prefs.Location()) {
+		return
+	}
+
+	decisions, err := store.GetDecisions(ctx, user.ReviewerLogin, now.Add(-suggestionWindow).Unix())
+	if err != nil {
+		logger.Printf("Failed to get decisions of user %s: %v", user.ReviewerLogin, err)
+		return
+	}
+	if len(decisions) < whitelist.SuggestMinDecisions {
+		return
+	}
+
+	entries, _, err := whitelist.Effective(ctx, user.ReviewerLogin, now)
+	if err != nil {
+		logger.Printf("Failed to get whitelist of user %s: %v", user.ReviewerLogin, err)
+		return
+	}
+	states, err := store.GetSuggestionStates(ctx, user.ReviewerLogin)
+	if err != nil {
+		logger.Printf("Failed to get whitelist suggestions of user %s: %v", user.ReviewerLogin, err)
+		return
+	}
+
+	suggestions := whitelist.Suggest(decisions, entries, logic.SkipSuggested(states, now, suggestionRepeatAfter))
+	if len(suggestions) == 0 {
+		return
+	}
+
+	// The buttons refer to the catalog, so only families and projects found there are suggested
+	catalog, err := projects.Load(ctx)
+	if err != nil {
+		logger.Printf("Failed to load project families: %v", err)
+		return
+	}
+	for _, s := range suggestions {
+		entryType := whitelist.BaseType(s.Entry.EntryType)
+		pos, ok := catalog.Find(entryType, s.Entry.Name)
+		if !ok {
+			continue
+		}
+
+		bot, err := telegram.NewBotClientFromEnv()
+		if err != nil {
+			logger.Printf("Failed to create Telegram client: %v", err)
+			return
+		}
+		p := prefs.Printer("")
+		keyboard := logic.SuggestionKeyboard(s, catalog, pos, p)
+		if _, err := render.Send(bot.GetBot(), user.TelegramChatID, logic.FormatSuggestionMessage(s, p), &keyboard); err != nil {
+			logger.Printf("Failed to send whitelist suggestion to user %s: %v", user.ReviewerLogin, err)
+			return
+		}
+		if err := store.MarkSuggested(ctx, user.ReviewerLogin, entryType, s.Entry.Name, now.Unix()); err != nil {
+			logger.Printf("Failed to store whitelist suggestion of user %s: %v", user.ReviewerLogin, err)
+		}
+		logger.Printf("Suggested %s %q to user %s", s.Entry.EntryType, s.Entry.Name, user.ReviewerLogin)
+		return
+	}
+}
+
+// deliverHeldMessages sends messages held back during quiet hours
+func deliverHeldMessages(ctx context.Context, user *models.User, logger *log.Logger) {
+	messages, err := store.GetHeldMessages(ctx, user.ReviewerLogin)
+	if err != nil {
+		logger.Printf("Failed to get held messages for user %s: %v", user.ReviewerLogin, err)
+		return
+	}
+	if len(messages) == 0 {
+		return
+	}
+
+	bot, err := telegram.NewBotClientFromEnv()
+	if err != nil {
+		logger.Printf("Failed to create Telegram client: %v", err)
+		return
+	}
+
+	for _, msg := range messages {
+		if _, err := render.Send(bot.GetBot(), user.TelegramChatID, render.HTML(msg.Text), nil); err != nil {
+			logger.Printf("Failed to deliver held message %s: %v", msg.ID, err)
+			return
+		}
+		if err := store.DeleteHeldMessage(ctx, user.ReviewerLogin, msg.ID); err != nil {
+			logger.Printf("Failed to delete held message %s: %v", msg.ID, err)
+		}
+	}
+
+	logger.Printf("Delivered %d held messages to user %s", len(messages), user.ReviewerLogin)
+}
+
 // checkNewBookings looks for new bookings in the calendar and creates review requests
-func checkNewBookings(ctx context.Context, user *models.User, settings *models.UserSettings, logger *log.Logger) error {
-	// Step 1: Fetch calendar events
-	from := time.Now().Add(-2 * time.Hour)
-	to := time.Now().Add(24 * time.Hour)
+func checkNewBookings(ctx context.Context, user *models.User, settings *models.UserSettings, prefs *store.UserPreferences, logger *log.Logger) error {
+	// Step 1: Fetch calendar events within the user's polling horizon
+	from, to := logic.BookingWindow(time.Now(), int(prefs.BookingLookbackHours), int(prefs.BookingLookaheadHours))
 
 	events, err := logic.GetCalendarEvents(ctx, user.ReviewerLogin, from, to)
 	if err != nil {
 		return fmt.Errorf("failed to get calendar events: %w", err)
 	}
 
-	// Step 2: Extract bookings
+	// Step 2: Extract bookings and the students booked for them
 	bookings := logic.ExtractBookings(events)
+	reviewees := logic.ExtractReviewees(events)
 
 	// Step 3: Check for new bookings
 	for _, booking := range bookings {
@@ -430,8 +982,135 @@ func checkNewBookings(ctx context.Context, user *models.User, settings *models.U
 			logger.Printf("Failed to create review request for slot %s: %v", booking.EventSlotID, err)
 			continue
 		}
+		if logins := reviewees[booking.ID]; len(logins) > 0 {
+			if err := store.SetReviewees(ctx, reviewID, logins); err != nil {
+				logger.Printf("Failed to store reviewees of review request %s: %v", reviewID, err)
+			}
+		}
+
+		// Far-off bookings move through the state machine now; their decision deadline is
+		// planned from the review start once the project is known
+		logger.Printf("Created new review request %s for slot %s (review %s)", reviewID, booking.EventSlotID,
+			timeutil.FormatShort(booking.Start))
+	}
+
+	return nil
+}
+
+// tidyCalendarSlots trims or splits partially booked slots according to the user's housekeeping mode
+func tidyCalendarSlots(ctx context.Context, user *models.User, prefs *store.UserPreferences, logger *log.Logger) error {
+	now := time.Now()
+	events, err := logic.GetCalendarEvents(ctx, user.ReviewerLogin, now, now.Add(logic.HousekeepingHorizon))
+	if err != nil {
+		return fmt.Errorf("failed to get calendar events: %w", err)
+	}
+
+	for _, slots := range logic.ExtractEventSlots(events) {
+		adjustments := logic.PlanSlotHousekeeping(slots, prefs.SlotHousekeepingMode, int(prefs.SlotHousekeepingBufferMinutes), now)
+
+		for _, adj := range adjustments {
+			change := &store.SlotChange{
+				ID:            uuid.New().String(),
+				ReviewerLogin: user.ReviewerLogin,
+				SlotID:        adj.SlotID,
+				Reason:        "HOUSEKEEPING_" + prefs.SlotHousekeepingMode,
+				OldStart:      adj.OldStart.Unix(),
+				OldEnd:        adj.OldEnd.Unix(),
+				CreatedAt:     time.Now().Unix(),
+			}
+
+			if adj.Cancel {
+				if err := logic.CancelCalendarSlot(ctx, user.ReviewerLogin, adj.SlotID); err != nil {
+					logger.Printf("Failed to cancel slot %s: %v", adj.SlotID, err)
+					continue
+				}
+				change.Action = store.SlotActionCancel
+				logger.Printf("Slot %s cancelled by housekeeping (%s - %s)", adj.SlotID,
+					timeutil.FormatShort(adj.OldStart), timeutil.FormatShort(adj.OldEnd))
+			} else {
+				if err := logic.ChangeCalendarSlot(ctx, user.ReviewerLogin, adj.SlotID, adj.NewStart, adj.NewEnd); err != nil {
+					logger.Printf("Failed to change slot %s: %v", adj.SlotID, err)
+					continue
+				}
+				newStart, newEnd := adj.NewStart.Unix(), adj.NewEnd.Unix()
+				change.Action = store.SlotActionChange
+				change.NewStart = &newStart
+				change.NewEnd = &newEnd
+				logger.Printf("Slot %s changed by housekeeping from %s - %s to %s - %s", adj.SlotID,
+					timeutil.FormatShort(adj.OldStart), timeutil.FormatShort(adj.OldEnd),
+					timeutil.FormatShort(adj.NewStart), timeutil.FormatShort(adj.NewEnd))
+			}
+
+			if err := store.RecordSlotChange(ctx, change); err != nil {
+				logger.Printf("Failed to record change for slot %s: %v", adj.SlotID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// openAvailabilitySlots creates calendar slots for upcoming occurrences of the user's availability templates
+func openAvailabilitySlots(ctx context.Context, user *models.User, prefs *store.UserPreferences, logger *log.Logger) error {
+	templates, err := store.GetAvailabilityTemplates(ctx, user.ReviewerLogin)
+	if err != nil {
+		return fmt.Errorf("failed to get availability templates: %w", err)
+	}
+	if len(templates) == 0 {
+		return nil
+	}
+
+	dates, err := store.GetHolidays(ctx, user.ReviewerLogin)
+	if err != nil {
+		return fmt.Errorf("failed to get holidays: %w", err)
+	}
+	holidays := make(map[string]bool, len(dates))
+	for _, date := range dates {
+		holidays[date] = true
+	}
+
+	now := time.Now()
+	occurrences := availability.Occurrences(templates, holidays, now, int(prefs.AvailabilityDaysAhead), prefs.Location())
+	if len(occurrences) == 0 {
+		return nil
+	}
+
+	opened, err := store.GetGeneratedOccurrenceKeys(ctx, user.ReviewerLogin, now.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to get generated slots: %w", err)
+	}
+
+	events, err := logic.GetCalendarEvents(ctx, user.ReviewerLogin, now, now.AddDate(0, 0, int(prefs.AvailabilityDaysAhead)+1))
+	if err != nil {
+		return fmt.Errorf("failed to get calendar events: %w", err)
+	}
+
+	var existing []external.CalendarSlot
+	for _, slots := range logic.ExtractEventSlots(events) {
+		existing = append(existing, slots...)
+	}
+
+	for _, occ := range logic.PlanAvailabilitySlots(occurrences, opened, existing) {
+		eventID, err := s21.AddEventSlot(ctx, user.ReviewerLogin, occ.Start, occ.End)
+		if err != nil {
+			logger.Printf("Failed to open slot %s for user %s: %v", occ.Key(), user.ReviewerLogin, err)
+			continue
+		}
+
+		slot := &store.GeneratedSlot{
+			ReviewerLogin: user.ReviewerLogin,
+			OccurrenceKey: occ.Key(),
+			EventID:       eventID,
+			SlotStart:     occ.Start.Unix(),
+			SlotEnd:       occ.End.Unix(),
+			CreatedAt:     time.Now().Unix(),
+		}
+		if err := store.RecordGeneratedSlot(ctx, slot); err != nil {
+			logger.Printf("Failed to record generated slot %s: %v", occ.Key(), err)
+		}
 
-		logger.Printf("Created new review request %s for slot %s", reviewID, booking.EventSlotID)
+		logger.Printf("Opened slot %s - %s for user %s from availability template",
+			timeutil.FormatShort(occ.Start), timeutil.FormatShort(occ.End), user.ReviewerLogin)
 	}
 
 	return nil
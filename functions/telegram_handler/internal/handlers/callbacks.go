@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -14,16 +15,21 @@ import (
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/telegram"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/timeutil"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/availability"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
 )
 
 // HandleApprove handles the APPROVE button click
 func HandleApprove(ctx context.Context, user *models.User, req *models.ReviewRequest, callback *tba.CallbackQuery, logger *log.Logger) error {
 	logger.Printf("User %s approved review %s", user.ReviewerLogin, req.ID)
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, callback.From, logger)
 
 	// Get user tokens (for future API calls if needed)
 	_, err := ydb.GetUserTokens(ctx, user.ReviewerLogin)
 	if err != nil {
-		return sendCallbackError(callback, fmt.Sprintf("Failed to get tokens: %v", err))
+		return sendCallbackError(callback, p.T("common.tokens_failed", err))
 	}
 
 	// The review is already whitelisted or was explicitly approved
@@ -31,22 +37,22 @@ func HandleApprove(ctx context.Context, user *models.User, req *models.ReviewReq
 	now := time.Now().Unix()
 	err = ydb.UpdateReviewRequestStatus(ctx, req.ID, models.StatusApproved, &now)
 	if err != nil {
-		return sendCallbackError(callback, fmt.Sprintf("Failed to update status: %v", err))
+		return sendCallbackError(callback, p.T("common.status_failed", err))
 	}
 
 	// Update Telegram message
 	bot, _ := telegram.NewBotClientFromEnv()
-	messageText := fmt.Sprintf("✅ *Review Approved*\n\nProject: %s\nTime: %s",
-		getProjectName(req),
-		timeutil.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime)))
+	messageText := p.T("review.approved",
+		getProjectName(p, req),
+		p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), prefs.Location()))
 
 	if req.TelegramMessageID != nil {
 		msgID, _ := strconv.Atoi(*req.TelegramMessageID)
-		bot.EditMessage(user.TelegramChatID, msgID, messageText)
+		render.Edit(bot.GetBot(), user.TelegramChatID, msgID, messageText, nil)
 	}
 
 	// Answer callback
-	bot.AnswerCallbackQuery(callback.ID, "Review approved!")
+	bot.AnswerCallbackQuery(callback.ID, render.Plain(p.T("review.approved_answer")))
 
 	return nil
 }
@@ -54,11 +60,13 @@ func HandleApprove(ctx context.Context, user *models.User, req *models.ReviewReq
 // HandleDecline handles the DECLINE button click
 func HandleDecline(ctx context.Context, user *models.User, req *models.ReviewRequest, callback *tba.CallbackQuery, logger *log.Logger) error {
 	logger.Printf("User %s declined review %s", user.ReviewerLogin, req.ID)
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, callback.From, logger)
 
 	// Cancel the slot via s21 API
 	tokens, err := ydb.GetUserTokens(ctx, user.ReviewerLogin)
 	if err != nil {
-		return sendCallbackError(callback, fmt.Sprintf("Failed to get tokens: %v", err))
+		return sendCallbackError(callback, p.T("common.tokens_failed", err))
 	}
 
 	client := external.NewS21Client(tokens.AccessToken, tokens.RefreshToken)
@@ -72,37 +80,148 @@ func HandleDecline(ctx context.Context, user *models.User, req *models.ReviewReq
 	now := time.Now().Unix()
 	err = ydb.UpdateReviewRequestStatus(ctx, req.ID, models.StatusCancelled, &now)
 	if err != nil {
-		return sendCallbackError(callback, fmt.Sprintf("Failed to update status: %v", err))
+		return sendCallbackError(callback, p.T("common.status_failed", err))
 	}
 
 	// Update Telegram message
 	bot, _ := telegram.NewBotClientFromEnv()
-	messageText := fmt.Sprintf("❌ *Review Cancelled*\n\nProject: %s\nTime: %s",
-		getProjectName(req),
-		timeutil.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime)))
+	messageText := p.T("review.cancelled",
+		getProjectName(p, req),
+		p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), prefs.Location()))
 
 	if req.TelegramMessageID != nil {
 		msgID, _ := strconv.Atoi(*req.TelegramMessageID)
-		bot.EditMessage(user.TelegramChatID, msgID, messageText)
+		render.Edit(bot.GetBot(), user.TelegramChatID, msgID, messageText, nil)
 	}
 
 	// Answer callback
-	bot.AnswerCallbackQuery(callback.ID, "Review cancelled")
+	bot.AnswerCallbackQuery(callback.ID, render.Plain(p.T("review.cancelled_answer")))
+
+	return nil
+}
+
+// Slot button actions
+const (
+	SlotActionClose  = "SLOT_CLOSE"
+	SlotActionExtend = "SLOT_EXTEND"
+)
+
+// Slot listing limits and the extension step used by the extend button
+const (
+	DefaultSlotsDays = 3
+	MaxSlotsDays     = 7
+	SlotExtendStep   = 30 * time.Minute
+)
+
+// FormatSlotCallbackData creates callback data for a slot button, e.g. "SLOT_CLOSE:3:<slot id>".
+// The number of listed days is kept so the listing can be refreshed after the action
+func FormatSlotCallbackData(action string, days int, slotID string) string {
+	return fmt.Sprintf("%s:%d:%s", action, days, slotID)
+}
+
+// ParseSlotCallbackData parses callback data created by FormatSlotCallbackData
+func ParseSlotCallbackData(data string) (action string, days int, slotID string, ok bool) {
+	parts := strings.SplitN(data, ":", 3)
+	if len(parts) != 3 {
+		return "", 0, "", false
+	}
+
+	if parts[0] != SlotActionClose && parts[0] != SlotActionExtend {
+		return "", 0, "", false
+	}
+
+	days, err := strconv.Atoi(parts[1])
+	if err != nil || days < 1 || days > MaxSlotsDays || parts[2] == "" {
+		return "", 0, "", false
+	}
+
+	return parts[0], days, parts[2], true
+}
+
+// HandleSlotCallback handles the close and extend buttons of the /slots listing
+func HandleSlotCallback(ctx context.Context, user *models.User, action string, days int, slotID string, callback *tba.CallbackQuery, logger *log.Logger) error {
+	logger.Printf("User %s pressed %s for slot %s", user.ReviewerLogin, action, slotID)
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, callback.From, logger)
+
+	slots, _, err := fetchCalendarSlots(ctx, user.ReviewerLogin, days)
+	if err != nil {
+		return sendCallbackError(callback, p.T("slots.calendar_failed", err))
+	}
+
+	var slot *external.CalendarSlot
+	for i := range slots {
+		if slots[i].ID == slotID {
+			slot = &slots[i]
+			break
+		}
+	}
+	if slot == nil || slot.Type != models.SlotTypeFreeTime {
+		return sendCallbackError(callback, p.T("slots.not_free"))
+	}
+
+	tokens, err := ydb.GetUserTokens(ctx, user.ReviewerLogin)
+	if err != nil {
+		return sendCallbackError(callback, p.T("common.tokens_failed", err))
+	}
+	client := external.NewS21Client(tokens.AccessToken, tokens.RefreshToken)
+	loc := prefs.Location()
+
+	var answer render.HTML
+	switch action {
+	case SlotActionClose:
+		if err := client.CancelSlot(ctx, slot.ID); err != nil {
+			return sendCallbackError(callback, p.T("slots.close_failed", err))
+		}
+		answer = p.T("slots.closed")
+
+	case SlotActionExtend:
+		newEnd := slot.End.Add(SlotExtendStep)
+		// The extension may reach past the listed window, so check the neighbours there too
+		neighbours, _, err := fetchCalendarSlotsBetween(ctx, user.ReviewerLogin, slot.Start, newEnd)
+		if err != nil {
+			return sendCallbackError(callback, p.T("slots.calendar_failed", err))
+		}
+		if conflict := availability.FindConflict(slot.End, newEnd, neighbours, slot.ID); conflict != nil {
+			return sendCallbackError(callback, p.T("slots.extend_conflict", conflict.Start.In(loc).Format("15:04")))
+		}
+		if err := client.ChangeEventSlot(ctx, slot.ID, slot.Start, newEnd); err != nil {
+			return sendCallbackError(callback, p.T("slots.extend_failed", err))
+		}
+		answer = p.T("slots.extended", newEnd.In(loc).Format("15:04"))
+
+	default:
+		return sendCallbackError(callback, p.T("common.unknown_action"))
+	}
+
+	// Refresh the listing
+	if callback.Message != nil {
+		if slots, bookings, err := fetchCalendarSlots(ctx, user.ReviewerLogin, days); err == nil {
+			text, rows := formatSlots(p, slots, bookings, days, loc)
+			if err := editKeyboardMessage(callback.Message.Chat.ID, callback.Message.MessageID, text, rows); err != nil {
+				logger.Printf("Failed to refresh slots message: %v", err)
+			}
+		}
+	}
+
+	bot, _ := telegram.NewBotClientFromEnv()
+	bot.AnswerCallbackQuery(callback.ID, render.Plain(answer))
 
 	return nil
 }
 
 // sendCallbackError sends an error response via callback
-func sendCallbackError(callback *tba.CallbackQuery, message string) error {
+func sendCallbackError(callback *tba.CallbackQuery, message render.HTML) error {
+	text := render.Plain(message)
 	bot, _ := telegram.NewBotClientFromEnv()
-	bot.AnswerCallbackQuery(callback.ID, message)
-	return fmt.Errorf("callback error: %s", message)
+	bot.AnswerCallbackQuery(callback.ID, text)
+	return fmt.Errorf("callback error: %s", text)
 }
 
 // getProjectName extracts project name from review request
-func getProjectName(req *models.ReviewRequest) string {
+func getProjectName(p *i18n.Printer, req *models.ReviewRequest) string {
 	if req.ProjectName != nil {
 		return *req.ProjectName
 	}
-	return "Unknown Project"
+	return render.Plain(p.T("review.unknown_project"))
 }
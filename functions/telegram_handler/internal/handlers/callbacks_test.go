@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
 )
 
@@ -117,7 +118,7 @@ func TestHandleApprove_MessageFormatting(t *testing.T) {
 
 	// Test message formatting logic
 	messageText := fmt.Sprintf("✅ *Review Approved*\n\nProject: %s\nTime: %s",
-		getProjectName(req),
+		getProjectName(testPrinter, req),
 		time.Now().Format("2006-01-02 15:04"))
 
 	assert.Contains(t, messageText, "Review Approved", "Should contain approval message")
@@ -138,7 +139,7 @@ func TestHandleApprove_UnknownProject(t *testing.T) {
 	}
 
 	// Test getProjectName with nil project name
-	projectName := getProjectName(req)
+	projectName := getProjectName(testPrinter, req)
 	assert.Equal(t, "Unknown Project", projectName, "Should return Unknown Project for nil")
 }
 
@@ -156,7 +157,7 @@ func TestHandleApprove_WithProjectName(t *testing.T) {
 	}
 
 	// Test getProjectName with valid project name
-	projectNameResult := getProjectName(req)
+	projectNameResult := getProjectName(testPrinter, req)
 	assert.Equal(t, "go-concurrency", projectNameResult, "Should return the actual project name")
 }
 
@@ -187,7 +188,7 @@ func TestHandleDecline_MessageFormatting(t *testing.T) {
 
 	// Test message formatting logic
 	messageText := fmt.Sprintf("❌ *Review Cancelled*\n\nProject: %s\nTime: %s",
-		getProjectName(req),
+		getProjectName(testPrinter, req),
 		time.Now().Format("2006-01-02 15:04"))
 
 	assert.Contains(t, messageText, "Review Cancelled", "Should contain cancellation message")
@@ -207,7 +208,7 @@ func TestHandleDecline_WithNilProjectName(t *testing.T) {
 		Status:          models.StatusWaitingForApprove,
 	}
 
-	projectName := getProjectName(req)
+	projectName := getProjectName(testPrinter, req)
 	assert.Equal(t, "Unknown Project", projectName, "Should return Unknown Project for nil")
 }
 
@@ -215,7 +216,7 @@ func TestHandleDecline_WithNilProjectName(t *testing.T) {
 func TestSendCallbackError_MessageConstruction(t *testing.T) {
 	tests := []struct {
 		name         string
-		errorMessage string
+		errorMessage render.HTML
 		expected     string
 	}{
 		{
@@ -309,7 +310,7 @@ func TestGetProjectName(t *testing.T) {
 				Status:          models.StatusWaitingForApprove,
 			}
 
-			result := getProjectName(req)
+			result := getProjectName(testPrinter, req)
 			assert.Equal(t, tt.expected, result, tt.description)
 		})
 	}
@@ -682,7 +683,7 @@ func BenchmarkGetProjectName(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		getProjectName(req)
+		getProjectName(testPrinter, req)
 	}
 }
 
@@ -748,7 +749,7 @@ func TestCallbackEdgeCases(t *testing.T) {
 				Status:          models.StatusWaitingForApprove,
 			}
 
-			result := getProjectName(req)
+			result := getProjectName(testPrinter, req)
 			assert.Equal(t, name, result, "Should handle Unicode project names")
 		}
 	})
@@ -920,3 +921,37 @@ func TestDecisionDeadlineHandling(t *testing.T) {
 func int64Ptr(i int64) *int64 {
 	return &i
 }
+
+// Test slot button callback data round trip and rejection of foreign data
+func TestSlotCallbackData(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		data := FormatSlotCallbackData(SlotActionExtend, 3, "8f4d6a8e-3a1c-4c3e-9a52-1b2c3d4e5f60")
+		assert.LessOrEqual(t, len(data), 64, "Telegram limits callback data to 64 bytes")
+
+		action, days, slotID, ok := ParseSlotCallbackData(data)
+		assert.True(t, ok)
+		assert.Equal(t, SlotActionExtend, action)
+		assert.Equal(t, 3, days)
+		assert.Equal(t, "8f4d6a8e-3a1c-4c3e-9a52-1b2c3d4e5f60", slotID)
+	})
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"Approve", "APPROVE:review-123"},
+		{"Decline", "DECLINE:review-123"},
+		{"UnknownAction", "SLOT_MOVE:3:slot-1"},
+		{"MissingSlot", "SLOT_CLOSE:3:"},
+		{"BadDays", "SLOT_CLOSE:abc:slot-1"},
+		{"TooManyDays", "SLOT_CLOSE:30:slot-1"},
+		{"Empty", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, ok := ParseSlotCallbackData(tt.data)
+			assert.False(t, ok)
+		})
+	}
+}
@@ -4,17 +4,29 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
 
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/external"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/telegram"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/timeutil"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/availability"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/policy"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/projects"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/s21"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/settings"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/timezone"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
 )
 
 // HandleStart handles the /start command - initiates authentication flow
@@ -24,97 +36,135 @@ func HandleStart(ctx context.Context, message *tba.Message, logger *log.Logger)
 	// Check if user already exists
 	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
 	if err == nil && user != nil {
-		sendMessage(chatID, fmt.Sprintf("Welcome back, %s! You are already authenticated.", user.ReviewerLogin))
+		p := userPrinter(ctx, loadPreferences(ctx, user.ReviewerLogin, logger), message.From, logger)
+		sendMessage(chatID, p.T("start.welcome_back", user.ReviewerLogin))
 		return nil
 	}
 
 	// Request login:password
-	sendMessage(chatID, "Please authenticate by sending your School 21 credentials in the format:\n\n`login:password`\n\nYour credentials will be stored securely in YDB.")
+	sendMessage(chatID, clientPrinter(message.From).T("start.prompt"))
 	return nil
 }
 
-// HandleSettings handles the /settings command - shows current settings
+// HandleSettings handles the /settings command - shows the settings menu
 func HandleSettings(ctx context.Context, message *tba.Message, logger *log.Logger) error {
 	chatID := message.From.ID
 
 	// Get user
 	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
 	if err != nil {
-		sendMessage(chatID, "User not found. Please use /start to authenticate.")
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
 		return nil
 	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
 
 	// Get settings
-	settings, err := ydb.GetUserSettings(ctx, user.ReviewerLogin)
+	values, err := loadSettingValues(ctx, user.ReviewerLogin, prefs)
 	if err != nil {
-		sendMessage(chatID, "Failed to retrieve settings.")
+		sendMessage(chatID, p.T("settings.failed"))
 		return nil
 	}
 
-	// Format settings message
-	msg := fmt.Sprintf("*Your Settings*\n\n"+
-		"📅 Response Deadline Shift: %d minutes\n"+
-		"⏱️ Non-Whitelist Cancel Delay: %d minutes\n"+
-		"🔔 Notify Whitelist Timeout: %s\n"+
-		"🔔 Notify Non-Whitelist Cancel: %s\n"+
-		"🔄 Slot Shift Threshold: %d minutes\n"+
-		"⬇️ Slot Shift Duration: %d minutes\n"+
-		"🧹 Cleanup Duration: %d minutes",
-		settings.ResponseDeadlineShiftMinutes,
-		settings.NonWhitelistCancelDelayMinutes,
-		boolToYesNo(settings.NotifyWhitelistTimeout),
-		boolToYesNo(settings.NotifyNonWhitelistCancel),
-		settings.SlotShiftThresholdMinutes,
-		settings.SlotShiftDurationMinutes,
-		settings.CleanupDurationsMinutes)
-
-	sendMessage(chatID, msg)
+	text, rows := formatSettingsMenu(p, values, prefs, loadOverrides(ctx, user.ReviewerLogin, logger))
+	if _, err := sendKeyboardMessage(chatID, text, rows); err != nil {
+		logger.Printf("Failed to send settings menu: %v", err)
+	}
 	return nil
 }
 
-// HandleWhitelist handles the /whitelist command - shows current whitelist
+// HandleWhitelist handles the /whitelist command - shows current whitelist and blacklist
 func HandleWhitelist(ctx context.Context, message *tba.Message, logger *log.Logger) error {
 	chatID := message.From.ID
 
 	// Get user
 	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
 	if err != nil {
-		sendMessage(chatID, "User not found. Please use /start to authenticate.")
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
 		return nil
 	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
 
-	// Get whitelist
-	entries, err := ydb.GetUserWhitelist(ctx, user.ReviewerLogin)
+	// Get whitelist, without expired entries the periodic job has not removed yet
+	entries, expiries, err := whitelist.Entries(ctx, user.ReviewerLogin, time.Now())
 	if err != nil {
-		sendMessage(chatID, "Failed to retrieve whitelist.")
+		sendMessage(chatID, p.T("whitelist.failed"))
 		return nil
 	}
 
-	if len(entries) == 0 {
-		sendMessage(chatID, "Your whitelist is empty.\n\nUse /whitelist_add to add projects or families.")
+	subscriptions, err := store.GetSubscriptions(ctx, user.ReviewerLogin)
+	if err != nil {
+		logger.Printf("Failed to get preset subscriptions for %s: %v", user.ReviewerLogin, err)
+	}
+
+	managed, err := store.GetManagedWhitelist(ctx, user.ReviewerLogin)
+	if err != nil {
+		logger.Printf("Failed to get managed whitelist entries for %s: %v", user.ReviewerLogin, err)
+	}
+
+	if len(entries) == 0 && len(subscriptions) == 0 {
+		sendMessage(chatID, p.T("whitelist.empty"))
 		return nil
 	}
 
 	// Format whitelist
 	var families []string
 	var projects []string
+	var patterns []string
+	var blacklist []string
+	var synced []string
 
 	for _, entry := range entries {
-		if entry.EntryType == models.EntryTypeFamily {
-			families = append(families, entry.Name)
-		} else {
-			projects = append(projects, entry.Name)
+		item := entry.Name
+		switch {
+		case whitelist.IsBlacklisted(entry.EntryType):
+			item += " (" + strings.ToLower(whitelist.BaseType(entry.EntryType)) + ")"
+		case whitelist.IsPattern(entry.EntryType):
+			item += " (" + strings.ToLower(entry.EntryType) + ")"
+		}
+		if expiresAt, ok := expiries.Of(entry); ok {
+			item += ", " + render.Plain(p.T("whitelist.until", p.FormatShort(expiresAt, prefs.Location())))
+		}
+
+		switch {
+		case whitelist.IsBlacklisted(entry.EntryType):
+			blacklist = append(blacklist, item)
+		case entry.EntryType == models.EntryTypeFamily:
+			families = append(families, item)
+		case whitelist.IsPattern(entry.EntryType):
+			patterns = append(patterns, item)
+		case containsString(managed, entry.Name):
+			synced = append(synced, item)
+		default:
+			projects = append(projects, item)
 		}
 	}
 
-	msg := "*Your Whitelist*\n\n"
+	msg := p.T("whitelist.title") + "\n\n"
 
 	if len(families) > 0 {
-		msg += "📁 Families:\n" + formatList(families)
+		msg += p.T("whitelist.families") + "\n" + formatList(families)
 	}
 
 	if len(projects) > 0 {
-		msg += "📦 Projects:\n" + formatList(projects)
+		msg += p.T("whitelist.projects") + "\n" + formatList(projects)
+	}
+
+	if len(patterns) > 0 {
+		msg += p.T("whitelist.patterns") + "\n" + formatList(patterns)
+	}
+
+	if len(synced) > 0 {
+		msg += p.T("whitelist.synced") + "\n" + formatList(synced)
+	}
+
+	if len(blacklist) > 0 {
+		msg += p.T("blacklist.title") + "\n" + formatList(blacklist)
+	}
+
+	if len(subscriptions) > 0 {
+		msg += p.T("whitelist.presets") + "\n" + formatList(subscriptions)
 	}
 
 	sendMessage(chatID, msg)
@@ -123,29 +173,79 @@ func HandleWhitelist(ctx context.Context, message *tba.Message, logger *log.Logg
 
 // HandleWhitelistAdd handles the /whitelist_add command
 func HandleWhitelistAdd(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleListAdd(ctx, message, false, logger)
+}
+
+// HandleBlacklistAdd handles the /blacklist_add command
+func HandleBlacklistAdd(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleListAdd(ctx, message, true, logger)
+}
+
+// handleListAdd adds an entry to the whitelist or the blacklist
+func handleListAdd(ctx context.Context, message *tba.Message, blacklisted bool, logger *log.Logger) error {
 	chatID := message.From.ID
 
 	// Get user
 	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
 	if err != nil {
-		sendMessage(chatID, "User not found. Please use /start to authenticate.")
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
 		return nil
 	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
 
-	// Parse arguments
-	args := strings.SplitN(message.CommandArguments(), " ", 2)
+	usageKey := "whitelist.add_usage"
+	if blacklisted {
+		usageKey = "blacklist.add_usage"
+	}
+
+	// Without arguments, browse the known families and projects
+	if strings.TrimSpace(message.CommandArguments()) == "" && !blacklisted {
+		return sendWhitelistBrowser(ctx, chatID, user.ReviewerLogin, p, logger)
+	}
+
+	// Parse arguments, with an optional "until <date>" or "for <duration>" at the end
+	rest, expiresAt, err := whitelist.SplitExpiry(message.CommandArguments(), time.Now(), prefs.Location())
+	if err != nil {
+		sendMessage(chatID, p.Err(err))
+		return nil
+	}
+	args := strings.SplitN(rest, " ", 2)
 	if len(args) < 2 {
-		sendMessage(chatID, "Usage: /whitelist_add <family|project> <name>\n\nExample:\n/whitelist_add family \"C - I\"\n/whitelist_add project \"go-concurrency\"")
+		sendMessage(chatID, p.T(usageKey))
 		return nil
 	}
 
 	entryType := strings.ToUpper(args[0])
-	name := args[1]
+	name := strings.Trim(strings.TrimSpace(args[1]), `"`)
 
-	if !models.IsValidEntryType(entryType) {
-		sendMessage(chatID, "Invalid entry type. Use 'family' or 'project'.")
+	if !whitelist.IsValidEntryType(entryType) {
+		sendMessage(chatID, p.T("whitelist.invalid_type"))
 		return nil
 	}
+	if blacklisted {
+		entryType = whitelist.BlacklistType(entryType)
+	}
+
+	if whitelist.IsPattern(entryType) {
+		return addWhitelistPattern(ctx, chatID, user.ReviewerLogin, entryType, name, expiresAt, p, prefs.Location(), logger)
+	}
+
+	// Check the name against project_families, unless it has not been loaded yet
+	catalog, err := projects.Load(ctx)
+	if err != nil {
+		logger.Printf("Failed to load project families, adding %s unchecked: %v", name, err)
+	} else if !catalog.Empty() {
+		pos, ok := catalog.Find(whitelist.BaseType(entryType), name)
+		if !ok {
+			text, rows := formatWhitelistSuggestions(p, catalog, entryType, name, expiresAt)
+			if _, err := sendKeyboardMessage(chatID, text, rows); err != nil {
+				logger.Printf("Failed to send whitelist suggestions: %v", err)
+			}
+			return nil
+		}
+		name = catalog.Name(pos)
+	}
 
 	// Add to whitelist
 	entry := &models.WhitelistEntry{
@@ -154,115 +254,608 @@ func HandleWhitelistAdd(ctx context.Context, message *tba.Message, logger *log.L
 		Name:          name,
 	}
 
-	err = ydb.AddToWhitelist(ctx, entry)
+	err = whitelist.Add(ctx, entry, expiresAt)
 	if err != nil {
-		sendMessage(chatID, fmt.Sprintf("Failed to add to whitelist: %v", err))
+		sendMessage(chatID, p.T(listKey(entryType, "add_failed"), err))
 		return nil
 	}
 
-	sendMessage(chatID, fmt.Sprintf("✅ Added %s to your whitelist.", name))
+	sendMessage(chatID, formatEntryAdded(p, entry, expiresAt, prefs.Location()))
 	return nil
 }
 
 // HandleWhitelistRemove handles the /whitelist_remove command
 func HandleWhitelistRemove(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleListRemove(ctx, message, false, logger)
+}
+
+// HandleBlacklistRemove handles the /blacklist_remove command
+func HandleBlacklistRemove(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleListRemove(ctx, message, true, logger)
+}
+
+// handleListRemove removes entries from the whitelist or the blacklist, keeping
+// entries of the same name in the other list
+func handleListRemove(ctx context.Context, message *tba.Message, blacklisted bool, logger *log.Logger) error {
 	chatID := message.From.ID
 
 	// Get user
 	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
 	if err != nil {
-		sendMessage(chatID, "User not found. Please use /start to authenticate.")
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
 		return nil
 	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	list := "whitelist."
+	if blacklisted {
+		list = "blacklist."
+	}
 
-	name := strings.TrimSpace(message.CommandArguments())
+	name := strings.Trim(strings.TrimSpace(message.CommandArguments()), `"`)
 	if name == "" {
-		sendMessage(chatID, "Usage: /whitelist_remove <name>\n\nExample: /whitelist_remove \"C - I\"")
+		sendMessage(chatID, p.T(list+"remove_usage"))
+		return nil
+	}
+
+	removed, err := whitelist.Remove(ctx, user.ReviewerLogin, name, blacklisted)
+	if err != nil {
+		sendMessage(chatID, p.T(list+"remove_failed", err))
+		return nil
+	}
+	if !removed {
+		sendMessage(chatID, p.T(list+"not_found", name))
+		return nil
+	}
+
+	sendMessage(chatID, p.T(list+"removed", name))
+	return nil
+}
+
+// HandleWhitelistTest handles the /whitelist_test command - explains which entry accepts a project
+func HandleWhitelistTest(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	projectName := strings.Trim(strings.TrimSpace(message.CommandArguments()), `"`)
+	if projectName == "" {
+		sendMessage(chatID, p.T("whitelist.test_usage"))
 		return nil
 	}
 
-	err = ydb.RemoveFromWhitelist(ctx, user.ReviewerLogin, name)
+	// The family is needed for family entries, so look the project up like the periodic job does
+	familyLabel := ""
+	catalog, err := projects.Load(ctx)
 	if err != nil {
-		sendMessage(chatID, fmt.Sprintf("Failed to remove from whitelist: %v", err))
+		logger.Printf("Failed to load project families: %v", err)
+	} else if pos, ok := catalog.Find(models.EntryTypeProject, projectName); ok {
+		projectName = catalog.Name(pos)
+		familyLabel = catalog.Family(pos.Family).Label
+	}
+
+	entries, presets, err := whitelist.Effective(ctx, user.ReviewerLogin, time.Now())
+	if err != nil {
+		sendMessage(chatID, p.T("whitelist.failed"))
 		return nil
 	}
 
-	sendMessage(chatID, fmt.Sprintf("✅ Removed %s from your whitelist.", name))
+	match := whitelist.FindWithPresets(entries, presets, projectName, familyLabel)
+	sendMessage(chatID, formatWhitelistTest(p, projectName, familyLabel, match))
 	return nil
 }
 
 // HandleSetDeadlineShift handles the /set_deadline_shift command
 func HandleSetDeadlineShift(ctx context.Context, message *tba.Message, logger *log.Logger) error {
-	return handleNumericSetting(ctx, message, "response_deadline_shift_minutes", 20, 60, 1)
+	return handleSetting(ctx, message, settings.DeadlineShift, logger)
 }
 
 // HandleSetCancelDelay handles the /set_cancel_delay command
 func HandleSetCancelDelay(ctx context.Context, message *tba.Message, logger *log.Logger) error {
-	return handleNumericSetting(ctx, message, "non_whitelist_cancel_delay_minutes", 5, 10, 1)
+	return handleSetting(ctx, message, settings.CancelDelay, logger)
 }
 
 // HandleSetSlotShiftThreshold handles the /set_slot_shift_threshold command
 func HandleSetSlotShiftThreshold(ctx context.Context, message *tba.Message, logger *log.Logger) error {
-	return handleNumericSetting(ctx, message, "slot_shift_threshold_minutes", 20, 60, 5)
+	return handleSetting(ctx, message, settings.SlotShiftThreshold, logger)
 }
 
 // HandleSetSlotShiftDuration handles the /set_slot_shift_duration command
 func HandleSetSlotShiftDuration(ctx context.Context, message *tba.Message, logger *log.Logger) error {
-	return handleNumericSetting(ctx, message, "slot_shift_duration_minutes", 15, 60, 15)
+	return handleSetting(ctx, message, settings.SlotShiftDuration, logger)
 }
 
 // HandleSetCleanupDuration handles the /set_cleanup_duration command
 func HandleSetCleanupDuration(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.CleanupDuration, logger)
+}
+
+// HandleSetNotifyWhitelistTimeout handles the /set_notify_whitelist_timeout command
+func HandleSetNotifyWhitelistTimeout(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.NotifyWhitelistTimeout, logger)
+}
+
+// HandleSetNotifyNonWhitelistCancel handles the /set_notify_non_whitelist_cancel command
+func HandleSetNotifyNonWhitelistCancel(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.NotifyNonWhitelistCancel, logger)
+}
+
+// HandleSetSlotHousekeeping handles the /set_slot_housekeeping command
+func HandleSetSlotHousekeeping(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.SlotHousekeeping, logger)
+}
+
+// HandleSetHousekeepingBuffer handles the /set_housekeeping_buffer command
+func HandleSetHousekeepingBuffer(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.HousekeepingBuffer, logger)
+}
+
+// HandlePause handles the /pause command - pauses the approval flow
+func HandlePause(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	loc := prefs.Location()
+	until, err := parsePauseUntil(message.CommandArguments(), time.Now(), loc)
+	if err != nil {
+		sendMessage(chatID, p.T("pause.usage", p.Err(err)))
+		return nil
+	}
+
+	err = store.SetPause(ctx, user.ReviewerLogin, true, until)
+	if err != nil {
+		sendMessage(chatID, p.T("pause.failed", err))
+		return nil
+	}
+
+	sendMessage(chatID, p.T("pause.paused", formatPauseEnd(p, until, loc), describePausePolicy(p, prefs.PausePolicy)))
+	return nil
+}
+
+// HandleResume handles the /resume command - resumes the approval flow
+func HandleResume(ctx context.Context, message *tba.Message, logger *log.Logger) error {
 	chatID := message.From.ID
 
 	// Get user
 	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
 	if err != nil {
-		sendMessage(chatID, "User not found. Please use /start to authenticate.")
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
 		return nil
 	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	err = store.SetPause(ctx, user.ReviewerLogin, false, nil)
+	if err != nil {
+		sendMessage(chatID, p.T("resume.failed", err))
+		return nil
+	}
+
+	sendMessage(chatID, p.T("resume.resumed"))
+	return nil
+}
+
+// HandleSetPausePolicy handles the /set_pause_policy command
+func HandleSetPausePolicy(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.PausePolicy, logger)
+}
+
+// HandleSetDailyCap handles the /set_daily_cap command
+func HandleSetDailyCap(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.DailyCap, logger)
+}
+
+// HandleSetWeeklyCap handles the /set_weekly_cap command
+func HandleSetWeeklyCap(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.WeeklyCap, logger)
+}
+
+// HandleSetMinGap handles the /set_min_gap command
+func HandleSetMinGap(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.MinGap, logger)
+}
+
+// HandleSetLimitAction handles the /set_limit_action command
+func HandleSetLimitAction(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.LimitAction, logger)
+}
+
+// HandleSetOutsideHoursAction handles the /set_outside_hours_action command
+func HandleSetOutsideHoursAction(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.OutsideHoursAction, logger)
+}
+
+// HandleSetBlockedPeerAction handles the /set_blocked_peer_action command
+func HandleSetBlockedPeerAction(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.BlockedPeerAction, logger)
+}
+
+// HandleSetTimeoutAction handles the /set_timeout_action command
+func HandleSetTimeoutAction(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.TimeoutAction, logger)
+}
+
+// HandleSetNonWhitelistAction handles the /set_non_whitelist_action command
+func HandleSetNonWhitelistAction(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.NonWhitelistAction, logger)
+}
+
+// HandleSetNonWhitelistDeadline handles the /set_non_whitelist_deadline command
+func HandleSetNonWhitelistDeadline(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.NonWhitelistDeadline, logger)
+}
+
+// HandleSetQuietHours handles the /set_quiet_hours command
+func HandleSetQuietHours(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	start, end, err := parseQuietHours(message.CommandArguments())
+	if err != nil {
+		sendMessage(chatID, p.T("quiet.usage", p.Err(err)))
+		return nil
+	}
+
+	err = store.SetQuietHours(ctx, user.ReviewerLogin, start, end)
+	if err != nil {
+		sendMessage(chatID, p.T("common.update_failed", err))
+		return nil
+	}
+
+	sendMessage(chatID, p.T("quiet.updated", formatQuietHours(p, start, end, prefs.Location())))
+	return nil
+}
+
+// HandleSetWorkingHours handles the /set_working_hours command
+func HandleSetWorkingHours(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
 
 	arg := strings.TrimSpace(message.CommandArguments())
-	value, err := strconv.Atoi(arg)
+	if arg == "" {
+		sendMessage(chatID, p.T("working_hours.current", formatWorkingHours(p, prefs.WorkingHours, prefs.Location())))
+		return nil
+	}
+
+	hours, err := availability.ParseWorkingHours(arg)
+	if err != nil {
+		sendMessage(chatID, p.T("working_hours.usage", p.Err(err)))
+		return nil
+	}
+
+	value := availability.FormatWorkingHours(hours)
+	if err := store.UpdateTextSetting(ctx, user.ReviewerLogin, "working_hours", value); err != nil {
+		sendMessage(chatID, p.T("common.update_failed", err))
+		return nil
+	}
+
+	msg := p.T("working_hours.updated", formatWorkingHours(p, value, prefs.Location()))
+	if value != "" && !containsPolicy(userChain(prefs), policy.WorkingHours) {
+		msg += "\n\n" + p.T("setting.not_in_chain", policy.WorkingHours)
+	}
+	sendMessage(chatID, msg)
+	return nil
+}
+
+// HandleSetTimezone handles the /set_timezone command
+func HandleSetTimezone(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
 	if err != nil {
-		sendMessage(chatID, "Usage: /set_cleanup_duration <minutes>\n\nAllowed values: 15, 30, 45, 60")
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	arg := strings.TrimSpace(message.CommandArguments())
+	if arg == "" {
+		loc := prefs.Location()
+		sendMessage(chatID, p.T("timezone.current", loc, time.Now().In(loc).Format("15:04 MST")))
 		return nil
 	}
 
-	// Validate: must be one of 15, 30, 45, 60
-	validValues := []int{15, 30, 45, 60}
-	isValid := false
-	for _, v := range validValues {
-		if value == v {
-			isValid = true
-			break
+	var zone string
+	if strings.EqualFold(arg, "auto") {
+		campus, err := s21.GetCampusName(ctx, user.ReviewerLogin)
+		if err != nil {
+			logger.Printf("Failed to get campus for %s: %v", user.ReviewerLogin, err)
+			sendMessage(chatID, p.T("timezone.detect_failed"))
+			return nil
+		}
+		zone = timezone.ForCampus(campus)
+	} else {
+		zone, err = timezone.Parse(arg)
+		if err != nil {
+			sendMessage(chatID, p.T("timezone.usage", p.Err(err)))
+			return nil
 		}
 	}
 
-	if !isValid {
-		sendMessage(chatID, "Invalid value. Allowed values: 15, 30, 45, 60")
+	err = store.UpdateTextSetting(ctx, user.ReviewerLogin, "timezone", zone)
+	if err != nil {
+		sendMessage(chatID, p.T("common.update_failed", err))
 		return nil
 	}
 
-	// Update setting
-	err = ydb.UpdateUserSetting(ctx, user.ReviewerLogin, "cleanup_durations_minutes", value)
+	loc := timezone.Load(zone)
+	sendMessage(chatID, p.T("timezone.updated", zone, time.Now().In(loc).Format("15:04 MST")))
+	return nil
+}
+
+// HandleLanguage handles the /language command
+func HandleLanguage(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
 	if err != nil {
-		sendMessage(chatID, fmt.Sprintf("Failed to update setting: %v", err))
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	arg := message.CommandArguments()
+	if strings.TrimSpace(arg) == "" {
+		sendMessage(chatID, p.T("language.current", p.T("language.name")))
 		return nil
 	}
 
-	sendMessage(chatID, fmt.Sprintf("✅ Cleanup duration set to %d minutes", value))
+	lang, err := settings.Get(settings.Language).Parse(arg)
+	if err != nil {
+		sendMessage(chatID, p.Err(err))
+		return nil
+	}
+
+	err = store.UpdateSetting(ctx, user.ReviewerLogin, settings.Language, lang)
+	if err != nil {
+		sendMessage(chatID, p.T("common.update_failed", err))
+		return nil
+	}
+
+	p = i18n.New(lang)
+	sendMessage(chatID, p.T("language.updated", p.T("language.name")))
 	return nil
 }
 
-// HandleSetNotifyWhitelistTimeout handles the /set_notify_whitelist_timeout command
-func HandleSetNotifyWhitelistTimeout(ctx context.Context, message *tba.Message, logger *log.Logger) error {
-	return handleBooleanSetting(ctx, message, "notify_whitelist_timeout")
+// HandleAvailability handles the /availability command - manages weekly availability templates
+func HandleAvailability(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		return showAvailability(ctx, chatID, user.ReviewerLogin, p, prefs.Location())
+	}
+
+	rest := strings.Join(args[1:], " ")
+	switch strings.ToLower(args[0]) {
+	case "add":
+		window, err := availability.ParseWindow(rest)
+		if err != nil {
+			sendMessage(chatID, p.T("availability.invalid", p.Err(err), p.T("availability.usage")))
+			return nil
+		}
+
+		tmpl := &store.AvailabilityTemplate{
+			ReviewerLogin: user.ReviewerLogin,
+			ID:            uuid.New().String(),
+			WeekdayMask:   window.WeekdayMask,
+			StartMinute:   window.StartMinute,
+			EndMinute:     window.EndMinute,
+			CreatedAt:     time.Now().Unix(),
+		}
+		if err := store.AddAvailabilityTemplate(ctx, tmpl); err != nil {
+			sendMessage(chatID, p.T("availability.add_failed", err))
+			return nil
+		}
+
+		sendMessage(chatID, p.T("availability.added", availability.FormatWindow(window.WeekdayMask, window.StartMinute, window.EndMinute),
+			prefs.Location()))
+		return nil
+
+	case "remove":
+		index, err := strconv.Atoi(rest)
+		if err != nil {
+			sendMessage(chatID, p.T("availability.remove_usage"))
+			return nil
+		}
+
+		templates, err := store.GetAvailabilityTemplates(ctx, user.ReviewerLogin)
+		if err != nil {
+			sendMessage(chatID, p.T("availability.failed"))
+			return nil
+		}
+		if index < 1 || index > len(templates) {
+			sendMessage(chatID, p.T("availability.not_found", index))
+			return nil
+		}
+
+		tmpl := templates[index-1]
+		if err := store.RemoveAvailabilityTemplate(ctx, user.ReviewerLogin, tmpl.ID); err != nil {
+			sendMessage(chatID, p.T("availability.remove_failed", err))
+			return nil
+		}
+
+		sendMessage(chatID, p.T("availability.removed", availability.FormatWindow(tmpl.WeekdayMask, tmpl.StartMinute, tmpl.EndMinute),
+			prefs.Location()))
+		return nil
+
+	case "holiday":
+		if len(args) != 3 {
+			sendMessage(chatID, p.T("availability.holiday_usage"))
+			return nil
+		}
+
+		date := args[2]
+		if _, err := time.Parse(availability.DateLayout, date); err != nil {
+			sendMessage(chatID, p.T("common.invalid_date", "2026-01-07"))
+			return nil
+		}
+
+		switch strings.ToLower(args[1]) {
+		case "add":
+			err = store.AddHoliday(ctx, user.ReviewerLogin, date)
+		case "remove":
+			err = store.RemoveHoliday(ctx, user.ReviewerLogin, date)
+		default:
+			sendMessage(chatID, p.T("availability.holiday_usage"))
+			return nil
+		}
+		if err != nil {
+			sendMessage(chatID, p.T("availability.holiday_failed", err))
+			return nil
+		}
+
+		sendMessage(chatID, p.T("availability.holiday_updated", date))
+		return nil
+
+	default:
+		sendMessage(chatID, p.T("availability.usage"))
+		return nil
+	}
 }
 
-// HandleSetNotifyNonWhitelistCancel handles the /set_notify_non_whitelist_cancel command
-func HandleSetNotifyNonWhitelistCancel(ctx context.Context, message *tba.Message, logger *log.Logger) error {
-	return handleBooleanSetting(ctx, message, "notify_non_whitelist_cancel")
+// HandleSetAvailabilityDays handles the /set_availability_days command
+func HandleSetAvailabilityDays(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.AvailabilityDays, logger)
+}
+
+// HandleSetLookahead handles the /set_lookahead command
+func HandleSetLookahead(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.Lookahead, logger)
+}
+
+// HandleSetLookback handles the /set_lookback command
+func HandleSetLookback(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.Lookback, logger)
+}
+
+// HandleSetSyncCompleted handles the /set_sync_completed command
+func HandleSetSyncCompleted(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	return handleSetting(ctx, message, settings.SyncCompleted, logger)
+}
+
+// HandleSlots handles the /slots command - lists upcoming calendar slots
+func HandleSlots(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	days := DefaultSlotsDays
+	if arg := strings.TrimSpace(message.CommandArguments()); arg != "" {
+		days, err = strconv.Atoi(arg)
+		if err != nil || days < 1 || days > MaxSlotsDays {
+			sendMessage(chatID, p.T("slots.usage", MaxSlotsDays))
+			return nil
+		}
+	}
+
+	slots, bookings, err := fetchCalendarSlots(ctx, user.ReviewerLogin, days)
+	if err != nil {
+		logger.Printf("Failed to get calendar slots for %s: %v", user.ReviewerLogin, err)
+		sendMessage(chatID, p.T("slots.failed"))
+		return nil
+	}
+
+	text, rows := formatSlots(p, slots, bookings, days, prefs.Location())
+	if _, err := sendKeyboardMessage(chatID, text, rows); err != nil {
+		logger.Printf("Failed to send slots to %s: %v", user.ReviewerLogin, err)
+	}
+	return nil
+}
+
+// HandleOpenSlot handles the /openslot command - opens a new calendar slot
+func HandleOpenSlot(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	now := time.Now()
+	loc := prefs.Location()
+	start, end, err := availability.ParseSlot(message.CommandArguments(), now, loc)
+	if err != nil {
+		sendMessage(chatID, p.T("openslot.invalid", p.Err(err)))
+		return nil
+	}
+
+	existing, _, err := fetchCalendarSlotsBetween(ctx, user.ReviewerLogin, start.Add(-24*time.Hour), end.Add(24*time.Hour))
+	if err != nil {
+		logger.Printf("Failed to get calendar slots for %s: %v", user.ReviewerLogin, err)
+		sendMessage(chatID, p.T("slots.failed"))
+		return nil
+	}
+
+	if err := availability.ValidateNewSlot(start, end, existing, now); err != nil {
+		sendMessage(chatID, p.T("openslot.rejected", p.Err(err)))
+		return nil
+	}
+
+	if _, err := s21.AddEventSlot(ctx, user.ReviewerLogin, start, end); err != nil {
+		logger.Printf("Failed to open slot for %s: %v", user.ReviewerLogin, err)
+		sendMessage(chatID, p.T("openslot.failed", err))
+		return nil
+	}
+
+	sendMessage(chatID, p.T("openslot.opened", p.FormatShort(start, loc), end.In(loc).Format("15:04")))
+	return nil
 }
 
 // HandleStatus handles the /status command - shows user status
@@ -272,9 +865,11 @@ func HandleStatus(ctx context.Context, message *tba.Message, logger *log.Logger)
 	// Get user
 	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
 	if err != nil {
-		sendMessage(chatID, "User not found. Please use /start to authenticate.")
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
 		return nil
 	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
 
 	// Get recent review requests
 	requests, err := ydb.GetReviewRequestsByUserAndStatus(ctx, user.ReviewerLogin, []string{
@@ -282,22 +877,21 @@ func HandleStatus(ctx context.Context, message *tba.Message, logger *log.Logger)
 		models.StatusWhitelisted,
 	})
 	if err != nil {
-		sendMessage(chatID, "Failed to retrieve status.")
+		sendMessage(chatID, p.T("status.failed"))
 		return nil
 	}
 
-	msg := fmt.Sprintf("*Status*\n\nUser: %s\nActive Reviews: %d",
-		user.ReviewerLogin,
-		len(requests))
+	msg := p.T("status.summary", user.ReviewerLogin, len(requests))
 
 	if len(requests) > 0 {
-		msg += "\n\nRecent Reviews:"
+		loc := prefs.Location()
+		msg += "\n\n" + p.T("status.recent")
 		for _, req := range requests {
-			projectName := "Unknown"
+			projectName := p.T("status.unknown_project")
 			if req.ProjectName != nil {
-				projectName = *req.ProjectName
+				projectName = render.Escape(*req.ProjectName)
 			}
-			msg += fmt.Sprintf("\n- %s at %s", projectName, timeutil.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime)))
+			msg += "\n" + p.T("status.review", projectName, p.FormatShort(timeutil.FromUnixSeconds(req.ReviewStartTime), loc))
 		}
 	}
 
@@ -307,7 +901,7 @@ func HandleStatus(ctx context.Context, message *tba.Message, logger *log.Logger)
 
 // HandleUnknownCommand handles unrecognized commands
 func HandleUnknownCommand(ctx context.Context, message *tba.Message, logger *log.Logger) error {
-	sendMessage(message.Chat.ID, fmt.Sprintf("Unknown command: %s\n\nUse /help to see available commands.", message.Command()))
+	sendMessage(message.Chat.ID, clientPrinter(message.From).T("command.unknown", message.Command()))
 	return nil
 }
 
@@ -315,11 +909,12 @@ func HandleUnknownCommand(ctx context.Context, message *tba.Message, logger *log
 func HandleAuthenticate(ctx context.Context, message *tba.Message, logger *log.Logger) error {
 	chatID := message.From.ID
 	text := strings.TrimSpace(message.Text)
+	p := clientPrinter(message.From)
 
 	// Parse login:password format
 	parts := strings.SplitN(text, ":", 2)
 	if len(parts) != 2 {
-		sendMessage(chatID, "Invalid format. Please send your credentials in the format:\n\n`login:password`")
+		sendMessage(chatID, p.T("auth.invalid_format"))
 		return nil
 	}
 
@@ -329,7 +924,7 @@ func HandleAuthenticate(ctx context.Context, message *tba.Message, logger *log.L
 	// Check if user already exists
 	existingUser, err := ydb.GetUserByTelegramChatID(ctx, chatID)
 	if err == nil && existingUser != nil {
-		sendMessage(chatID, fmt.Sprintf("You are already authenticated as %s.\n\nUse /logout first if you want to re-authenticate.", existingUser.ReviewerLogin))
+		sendMessage(chatID, p.T("auth.already", existingUser.ReviewerLogin))
 		return nil
 	}
 
@@ -337,7 +932,7 @@ func HandleAuthenticate(ctx context.Context, message *tba.Message, logger *log.L
 	tokenResp, err := external.Authenticate(ctx, login, password)
 	if err != nil {
 		logger.Printf("Authentication failed for user %d: %v", chatID, err)
-		sendMessage(chatID, "Authentication failed. Please check your credentials and try again.")
+		sendMessage(chatID, p.T("auth.failed"))
 		return nil
 	}
 
@@ -350,7 +945,7 @@ func HandleAuthenticate(ctx context.Context, message *tba.Message, logger *log.L
 	err = ydb.StoreUserTokens(ctx, reviewerLogin, tokenResp.AccessToken, tokenResp.RefreshToken)
 	if err != nil {
 		logger.Printf("Failed to store tokens for %s: %v", reviewerLogin, err)
-		sendMessage(chatID, "Authentication succeeded, but failed to store tokens. Please contact support.")
+		sendMessage(chatID, p.T("auth.tokens_failed"))
 		return nil
 	}
 
@@ -368,18 +963,21 @@ func HandleAuthenticate(ctx context.Context, message *tba.Message, logger *log.L
 	err = ydb.UpsertUser(ctx, user)
 	if err != nil {
 		logger.Printf("Failed to create user record for %s: %v", reviewerLogin, err)
-		sendMessage(chatID, "Authentication succeeded, but failed to create user record. Please contact support.")
+		sendMessage(chatID, p.T("auth.user_failed"))
 		return nil
 	}
 
 	// Create default settings
-	err = ydb.CreateDefaultUserSettings(ctx, reviewerLogin)
+	err = ydb.UpsertUserSettings(ctx, settings.DefaultUserSettings(reviewerLogin))
 	if err != nil {
 		logger.Printf("Failed to create default settings for %s: %v", reviewerLogin, err)
 		// Non-fatal, continue anyway
 	}
 
-	sendMessage(chatID, fmt.Sprintf("✅ Successfully authenticated as %s!\n\nYou can now use the bot. Use /help to see available commands.", reviewerLogin))
+	// Remember the client language so notifications use it too
+	p = userPrinter(ctx, store.DefaultUserPreferences(reviewerLogin), message.From, logger)
+
+	sendMessage(chatID, p.T("auth.success", reviewerLogin))
 	return nil
 }
 
@@ -390,9 +988,10 @@ func HandleLogout(ctx context.Context, message *tba.Message, logger *log.Logger)
 	// Get user
 	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
 	if err != nil {
-		sendMessage(chatID, "You are not authenticated.")
+		sendMessage(chatID, clientPrinter(message.From).T("logout.not_authenticated"))
 		return nil
 	}
+	p := userPrinter(ctx, loadPreferences(ctx, user.ReviewerLogin, logger), message.From, logger)
 
 	// Delete tokens from YDB
 	err = ydb.DeleteUserTokens(ctx, user.ReviewerLogin)
@@ -406,7 +1005,7 @@ func HandleLogout(ctx context.Context, message *tba.Message, logger *log.Logger)
 		logger.Printf("Failed to update user status for %s: %v", user.ReviewerLogin, err)
 	}
 
-	sendMessage(chatID, "✅ Logged out successfully. You can authenticate again with /start.")
+	sendMessage(chatID, p.T("logout.success"))
 	return nil
 }
 
@@ -414,115 +1013,356 @@ func HandleLogout(ctx context.Context, message *tba.Message, logger *log.Logger)
 func HandleHelp(ctx context.Context, message *tba.Message, logger *log.Logger) error {
 	chatID := message.From.ID
 
-	helpText := `*Review Slot Guard Bot*
-
-This bot helps you manage your review slots for School 21.
-
-*Commands:*
-
-/start - Start authentication
-/logout - Log out from the bot
-/status - Show your current status and active reviews
-/settings - Display your current settings
-/whitelist - Show your whitelisted projects and families
-
-*Whitelist Management:*
-/whitelist_add <family|project> <name> - Add to whitelist
-/whitelist_remove <name> - Remove from whitelist
-
-*Settings:*
-/set_deadline_shift <minutes> - Response deadline shift (1-60)
-/set_cancel_delay <minutes> - Non-whitelist cancel delay (1-10)
-/set_slot_shift_threshold <minutes> - Slot shift threshold (5-60)
-/set_slot_shift_duration <minutes> - Slot shift duration (5-60)
-/set_cleanup_duration <minutes> - Cleanup duration (15, 30, 45, 60)
-/set_notify_whitelist_timeout <true|false> - Notify on whitelist timeout
-/set_notify_non_whitelist_cancel <true|false> - Notify on non-whitelist cancel`
+	p := clientPrinter(message.From)
+	if user, err := ydb.GetUserByTelegramChatID(ctx, chatID); err == nil {
+		p = userPrinter(ctx, loadPreferences(ctx, user.ReviewerLogin, logger), message.From, logger)
+	}
 
-	sendMessage(chatID, helpText)
+	sendMessage(chatID, p.T("help.text", formatSettingsHelp(p)))
 	return nil
 }
 
 // Helper functions
 
-func handleNumericSetting(ctx context.Context, message *tba.Message, field string, min, max, step int) error {
+// handleSetting changes a registry setting from its command, with the same validation as the menu
+func handleSetting(ctx context.Context, message *tba.Message, key string, logger *log.Logger) error {
 	chatID := message.From.ID
 
 	// Get user
 	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
 	if err != nil {
-		sendMessage(chatID, "User not found. Please use /start to authenticate.")
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
 		return nil
 	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
 
-	arg := strings.TrimSpace(message.CommandArguments())
-	value, err := strconv.Atoi(arg)
+	values, err := loadSettingValues(ctx, user.ReviewerLogin, prefs)
 	if err != nil {
-		sendMessage(chatID, fmt.Sprintf("Usage: /set_%s <value>\n\nValid range: %d - %d (step %d)", field, min, max, step))
+		sendMessage(chatID, p.T("settings.failed"))
 		return nil
 	}
 
-	// Validate
-	if value < min || value > max {
-		sendMessage(chatID, fmt.Sprintf("Value must be between %d and %d", min, max))
+	setting := settings.Get(key)
+	value, err := settings.Check(setting, message.CommandArguments(), values)
+	if err != nil {
+		sendMessage(chatID, p.Err(err))
 		return nil
 	}
 
 	// Update setting
-	err = ydb.UpdateUserSetting(ctx, user.ReviewerLogin, field, value)
+	err = store.UpdateSetting(ctx, user.ReviewerLogin, key, value)
 	if err != nil {
-		sendMessage(chatID, fmt.Sprintf("Failed to update setting: %v", err))
+		sendMessage(chatID, p.T("common.update_failed", err))
 		return nil
 	}
 
-	sendMessage(chatID, fmt.Sprintf("✅ Setting updated to %d", value))
+	msg := p.T("setting.updated", settingLine(p, setting, value))
+	if name, ok := settingPolicies[key]; ok && value != "0" && !containsPolicy(userChain(prefs), name) {
+		msg += "\n\n" + p.T("setting.not_in_chain", name)
+	}
+	sendMessage(chatID, msg)
 	return nil
 }
 
-func handleBooleanSetting(ctx context.Context, message *tba.Message, field string) error {
-	chatID := message.From.ID
+// settingPolicies maps settings to the decision policy that applies them
+var settingPolicies = map[string]string{
+	settings.DailyCap:           policy.DailyCap,
+	settings.WeeklyCap:          policy.WeeklyCap,
+	settings.MinGap:             policy.MinGap,
+	settings.OutsideHoursAction: policy.WorkingHours,
+}
 
-	// Get user
-	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+// parsePauseUntil parses the /pause arguments: nothing, or "[until] YYYY-MM-DD".
+// The date is inclusive like whitelist expiry dates, the pause ends at the end of it in loc
+func parsePauseUntil(args string, now time.Time, loc *time.Location) (*int64, error) {
+	fields := strings.Fields(strings.ToLower(args))
+	if len(fields) > 0 && fields[0] == "until" {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	if len(fields) > 1 {
+		return nil, i18n.Errorf("pause.invalid_args")
+	}
+
+	date, err := time.ParseInLocation(availability.DateLayout, fields[0], loc)
 	if err != nil {
-		sendMessage(chatID, "User not found. Please use /start to authenticate.")
-		return nil
+		return nil, i18n.Errorf("common.invalid_date", "2026-01-20")
+	}
+	end := date.AddDate(0, 0, 1)
+	if !end.After(now) {
+		return nil, i18n.Errorf("pause.date_in_past")
 	}
 
-	arg := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
-	value := true
+	until := end.Unix()
+	return &until, nil
+}
 
-	if arg == "false" || arg == "no" || arg == "0" || arg == "off" {
-		value = false
+// parseQuietHours parses "HH:MM-HH:MM" or "off" into minutes after midnight.
+// The range may wrap around midnight; "off" returns equal bounds
+func parseQuietHours(arg string) (int32, int32, error) {
+	arg = strings.NewReplacer("–", "-", "—", "-").Replace(strings.ToLower(strings.TrimSpace(arg)))
+	if arg == "off" {
+		return 0, 0, nil
 	}
 
-	// Update setting
-	err = ydb.UpdateUserSetting(ctx, user.ReviewerLogin, field, value)
+	bounds := strings.SplitN(arg, "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, i18n.Errorf("quiet.invalid")
+	}
+
+	start, err := availability.ParseClock(bounds[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := availability.ParseClock(bounds[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	if start == end {
+		return 0, 0, i18n.Errorf("quiet.same_bounds")
+	}
+
+	return start, end, nil
+}
+
+func formatQuietHours(p *i18n.Printer, start, end int32, loc *time.Location) render.HTML {
+	if start == end {
+		return p.T("quiet.off")
+	}
+	return render.Escape(availability.FormatClock(start) + "-" + availability.FormatClock(end) + " " + loc.String())
+}
+
+func formatWorkingHours(p *i18n.Printer, hours string, loc *time.Location) render.HTML {
+	if hours == "" {
+		return p.T("working_hours.off")
+	}
+	return render.Escape(hours + " " + loc.String())
+}
+
+func formatPauseEnd(p *i18n.Printer, until *int64, loc *time.Location) render.HTML {
+	if until == nil {
+		return p.T("pause.until_resume")
+	}
+	return p.T("pause.until", p.FormatShort(timeutil.FromUnixSeconds(*until), loc))
+}
+
+func formatPauseState(p *i18n.Printer, prefs *store.UserPreferences) render.HTML {
+	if !prefs.IsPaused(time.Now()) {
+		return p.T("common.no")
+	}
+	return p.T("pause.state_paused", formatPauseEnd(p, prefs.PausedUntil, prefs.Location()))
+}
+
+// loadPreferences returns the user's preferences, or the defaults if they cannot be read
+func loadPreferences(ctx context.Context, reviewerLogin string, logger *log.Logger) *store.UserPreferences {
+	prefs, err := store.GetUserPreferences(ctx, reviewerLogin)
+	if err != nil {
+		logger.Printf("Failed to get preferences for %s: %v", reviewerLogin, err)
+		return store.DefaultUserPreferences(reviewerLogin)
+	}
+	return prefs
+}
+
+// userPrinter returns a printer in the user's language. The Telegram client language is
+// stored the first time it is seen, so notifications from the periodic job use it too
+func userPrinter(ctx context.Context, prefs *store.UserPreferences, from *tba.User, logger *log.Logger) *i18n.Printer {
+	clientLang := clientLanguage(from)
+	p := prefs.Printer(clientLang)
+	if prefs.Language == "" && clientLang != "" {
+		if err := store.UpdateTextSetting(ctx, prefs.ReviewerLogin, "language", p.Lang()); err != nil {
+			logger.Printf("Failed to store language for %s: %v", prefs.ReviewerLogin, err)
+		} else {
+			prefs.Language = p.Lang()
+		}
+	}
+	return p
+}
+
+// clientPrinter returns a printer in the Telegram client language, for users that are not known yet
+func clientPrinter(from *tba.User) *i18n.Printer {
+	return i18n.New(i18n.Resolve("", clientLanguage(from)))
+}
+
+func clientLanguage(from *tba.User) string {
+	if from == nil {
+		return ""
+	}
+	return from.LanguageCode
+}
+
+func describePausePolicy(p *i18n.Printer, policy string) render.HTML {
+	switch policy {
+	case store.PausePolicyDecline:
+		return p.T("pause_policy.decline")
+	case store.PausePolicyWhitelistedOnly:
+		return p.T("pause_policy.whitelisted_only")
+	default:
+		return p.T("pause_policy.queue")
+	}
+}
+
+func showAvailability(ctx context.Context, chatID int64, reviewerLogin string, p *i18n.Printer, loc *time.Location) error {
+	templates, err := store.GetAvailabilityTemplates(ctx, reviewerLogin)
+	if err != nil {
+		sendMessage(chatID, p.T("availability.failed"))
+		return nil
+	}
+
+	if len(templates) == 0 {
+		sendMessage(chatID, p.T("availability.empty", p.T("availability.usage")))
+		return nil
+	}
+
+	holidays, err := store.GetHolidays(ctx, reviewerLogin)
 	if err != nil {
-		sendMessage(chatID, fmt.Sprintf("Failed to update setting: %v", err))
+		sendMessage(chatID, p.T("availability.holidays_failed"))
 		return nil
 	}
 
-	sendMessage(chatID, fmt.Sprintf("✅ %s set to %t", field, value))
+	msg := p.T("availability.title", loc) + "\n\n"
+	for i, tmpl := range templates {
+		msg += render.Format("%d. %s\n", i+1, availability.FormatWindow(tmpl.WeekdayMask, tmpl.StartMinute, tmpl.EndMinute))
+	}
+
+	if len(holidays) > 0 {
+		msg += "\n" + p.T("availability.holidays") + "\n" + formatList(holidays)
+	}
+
+	sendMessage(chatID, msg)
 	return nil
 }
 
-func sendMessage(chatID int64, text string) {
-	bot, _ := telegram.NewBotClientFromEnv()
-	bot.SendPlainMessage(chatID, text)
+// fetchCalendarSlots returns calendar slots and bookings from now until the given number of days ahead
+func fetchCalendarSlots(ctx context.Context, reviewerLogin string, days int) ([]external.CalendarSlot, []external.CalendarBooking, error) {
+	now := time.Now()
+	return fetchCalendarSlotsBetween(ctx, reviewerLogin, now, now.AddDate(0, 0, days))
+}
+
+// fetchCalendarSlotsBetween returns calendar slots and bookings in the given window
+func fetchCalendarSlotsBetween(ctx context.Context, reviewerLogin string, from, to time.Time) ([]external.CalendarSlot, []external.CalendarBooking, error) {
+	tokens, err := ydb.GetUserTokens(ctx, reviewerLogin)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user tokens: %w", err)
+	}
+
+	client := external.NewS21Client(tokens.AccessToken, tokens.RefreshToken)
+	events, err := client.GetCalendarEvents(ctx, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return external.ExtractSlots(events), external.ExtractBookings(events), nil
+}
+
+// formatSlots renders calendar slots grouped by day in loc, with close/extend buttons for every free slot
+func formatSlots(p *i18n.Printer, slots []external.CalendarSlot, bookings []external.CalendarBooking, days int, loc *time.Location) (render.HTML, [][]telegram.InlineKeyboardButton) {
+	if len(slots) == 0 {
+		return p.T("slots.empty", p.N("unit.days", days)), nil
+	}
+
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start) })
+
+	projects := make(map[string]string, len(bookings))
+	for _, booking := range bookings {
+		projects[booking.EventSlotID] = booking.ProjectName
+	}
+
+	msg := p.T("slots.title", p.N("unit.days", days), loc) + "\n"
+	var rows [][]telegram.InlineKeyboardButton
+	var lastDay render.HTML
+
+	for i, slot := range slots {
+		day := p.FormatDay(slot.Start, loc)
+		if day != lastDay {
+			msg += "\n" + day + "\n"
+			lastDay = day
+		}
+
+		period := slot.Start.In(loc).Format("15:04") + "-" + slot.End.In(loc).Format("15:04")
+		if slot.Type == models.SlotTypeFreeTime {
+			msg += p.T("slots.free", i+1, period) + "\n"
+			rows = append(rows, []telegram.InlineKeyboardButton{
+				{Text: render.Plain(p.T("slots.close_button", i+1)), Data: FormatSlotCallbackData(SlotActionClose, days, slot.ID)},
+				{Text: render.Plain(p.T("slots.extend_button", i+1)), Data: FormatSlotCallbackData(SlotActionExtend, days, slot.ID)},
+			})
+			continue
+		}
+
+		project := render.Escape(projects[slot.ID])
+		if project == "" {
+			project = p.T("slots.review")
+		}
+		msg += p.T("slots.booked", i+1, period, project) + "\n"
+	}
+
+	return msg, rows
+}
+
+// sendKeyboardMessage sends a message with one keyboard row per entry in rows
+func sendKeyboardMessage(chatID int64, text render.HTML, rows [][]telegram.InlineKeyboardButton) (int, error) {
+	bot, err := telegram.NewBotClientFromEnv()
+	if err != nil {
+		return 0, err
+	}
+
+	var keyboard *tba.InlineKeyboardMarkup
+	if len(rows) > 0 {
+		markup := inlineKeyboard(rows)
+		keyboard = &markup
+	}
+
+	return render.Send(bot.GetBot(), chatID, text, keyboard)
+}
+
+// editKeyboardMessage replaces the text and keyboard of a message sent with sendKeyboardMessage
+func editKeyboardMessage(chatID int64, messageID int, text render.HTML, rows [][]telegram.InlineKeyboardButton) error {
+	bot, err := telegram.NewBotClientFromEnv()
+	if err != nil {
+		return err
+	}
+
+	markup := inlineKeyboard(rows)
+	return render.Edit(bot.GetBot(), chatID, messageID, text, &markup)
+}
+
+// inlineKeyboard converts button rows into a Telegram inline keyboard
+func inlineKeyboard(rows [][]telegram.InlineKeyboardButton) tba.InlineKeyboardMarkup {
+	keyboard := make([][]tba.InlineKeyboardButton, len(rows))
+	for i, row := range rows {
+		keyboard[i] = make([]tba.InlineKeyboardButton, len(row))
+		for j, btn := range row {
+			keyboard[i][j] = tba.NewInlineKeyboardButtonData(btn.Text, btn.Data)
+		}
+	}
+	return tba.InlineKeyboardMarkup{InlineKeyboard: keyboard}
+}
+
+// sendMessage sends a rendered message, split into several if it is too long
+func sendMessage(chatID int64, text render.HTML) {
+	bot, err := telegram.NewBotClientFromEnv()
+	if err != nil {
+		log.Printf("Failed to create bot client: %v", err)
+		return
+	}
+	if _, err := render.Send(bot.GetBot(), chatID, text, nil); err != nil {
+		log.Printf("Failed to send message to %d: %v", chatID, err)
+	}
 }
 
-func boolToYesNo(b bool) string {
+func boolToYesNo(p *i18n.Printer, b bool) render.HTML {
 	if b {
-		return "Yes"
+		return p.T("common.yes")
 	}
-	return "No"
+	return p.T("common.no")
 }
 
-func formatList(items []string) string {
-	var result string
+func formatList(items []string) render.HTML {
+	var result render.HTML
 	for _, item := range items {
-		result += fmt.Sprintf("  • %s\n", item)
+		result += render.Format("  • %s\n", item)
 	}
 	return result
 }
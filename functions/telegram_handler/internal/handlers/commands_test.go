@@ -6,15 +6,24 @@ import (
 	"log"
 	"strings"
 	"testing"
+	"time"
 
 	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/external"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/availability"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
 )
 
+// testPrinter renders messages in English, the language the expectations below are written in
+var testPrinter = i18n.New(i18n.English)
+
 // MockYDBClient is a mock for YDB operations
 type MockYDBClient struct {
 	mock.Mock
@@ -745,7 +754,7 @@ func TestBoolToYesNo(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    bool
-		expected string
+		expected render.HTML
 	}{
 		{"True", true, "Yes"},
 		{"False", false, "No"},
@@ -753,17 +762,21 @@ func TestBoolToYesNo(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := boolToYesNo(tt.input)
+			result := boolToYesNo(testPrinter, tt.input)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
+
+	t.Run("Russian", func(t *testing.T) {
+		assert.Equal(t, render.HTML("Да"), boolToYesNo(i18n.New(i18n.Russian), true))
+	})
 }
 
 func TestFormatList(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    []string
-		expected string
+		expected render.HTML
 	}{
 		{
 			name:     "EmptyList",
@@ -785,6 +798,11 @@ func TestFormatList(t *testing.T) {
 			input:    []string{"C - I", "Go Concurrency"},
 			expected: "  • C - I\n  • Go Concurrency\n",
 		},
+		{
+			name:     "ItemsEscaped",
+			input:    []string{"a<b>", "x & y"},
+			expected: "  • a&lt;b&gt;\n  • x &amp; y\n",
+		},
 	}
 
 	for _, tt := range tests {
@@ -863,8 +881,8 @@ func TestSettingsMessageFormatting(t *testing.T) {
 		"🧹 Cleanup Duration: %d minutes",
 		settings.ResponseDeadlineShiftMinutes,
 		settings.NonWhitelistCancelDelayMinutes,
-		boolToYesNo(settings.NotifyWhitelistTimeout),
-		boolToYesNo(settings.NotifyNonWhitelistCancel),
+		boolToYesNo(testPrinter, settings.NotifyWhitelistTimeout),
+		boolToYesNo(testPrinter, settings.NotifyNonWhitelistCancel),
 		settings.SlotShiftThresholdMinutes,
 		settings.SlotShiftDurationMinutes,
 		settings.CleanupDurationsMinutes)
@@ -909,7 +927,7 @@ func TestWhitelistMessageFormatting(t *testing.T) {
 		}
 	}
 
-	msg := "*Your Whitelist*\n\n"
+	msg := render.HTML("*Your Whitelist*\n\n")
 
 	if len(families) > 0 {
 		msg += "📁 Families:\n" + formatList(families)
@@ -1063,7 +1081,7 @@ func TestBooleanSettingParsing(t *testing.T) {
 // Benchmark tests
 func BenchmarkBoolToYesNo(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		boolToYesNo(i%2 == 0)
+		boolToYesNo(testPrinter, i%2 == 0)
 	}
 }
 
@@ -1126,3 +1144,228 @@ func TestEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+// Test slot housekeeping mode parsing
+func TestSlotHousekeepingModeParsing(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"Off", "off", true},
+		{"Trim", "trim", true},
+		{"SplitUpper", "SPLIT", true},
+		{"WithSpaces", "  trim  ", true},
+		{"Empty", "", false},
+		{"Unknown", "shrink", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode := strings.ToUpper(strings.TrimSpace(tt.input))
+			assert.Equal(t, tt.expected, store.IsValidHousekeepingMode(mode))
+		})
+	}
+}
+
+// Test availability window parsing as used by /availability add
+func TestAvailabilityAddParsing(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		hasError bool
+	}{
+		{"Range", "Mon-Thu 19:00-21:00", "Mon-Thu 19:00-21:00", false},
+		{"EnDash", "Mon–Thu 19:00–21:00", "Mon-Thu 19:00-21:00", false},
+		{"List", "mon,wed 18:00-20:00", "Mon,Wed 18:00-20:00", false},
+		{"Weekends", "weekends 10:00-14:00", "Sat,Sun 10:00-14:00", false},
+		{"MissingDays", "19:00-21:00", "", true},
+		{"Empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := availability.ParseWindow(tt.input)
+			if tt.hasError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, availability.FormatWindow(window.WeekdayMask, window.StartMinute, window.EndMinute))
+		})
+	}
+}
+
+// Test /pause argument parsing
+func TestParsePauseUntil(t *testing.T) {
+	now := time.Date(2026, 1, 12, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		input    string
+		expected *time.Time
+		hasError bool
+	}{
+		{"Indefinite", "", nil, false},
+		{"Until", "until 2026-01-20", ptrTime(time.Date(2026, 1, 21, 0, 0, 0, 0, time.UTC)), false},
+		{"DateOnly", "2026-01-20", ptrTime(time.Date(2026, 1, 21, 0, 0, 0, 0, time.UTC)), false},
+		{"Today", "until 2026-01-12", ptrTime(time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC)), false},
+		{"Yesterday", "until 2026-01-11", nil, true},
+		{"Past", "2026-01-01", nil, true},
+		{"BadDate", "until next week", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			until, err := parsePauseUntil(tt.input, now, time.UTC)
+			if tt.hasError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expected == nil {
+				assert.Nil(t, until)
+				return
+			}
+			require.NotNil(t, until)
+			assert.Equal(t, tt.expected.Unix(), *until)
+		})
+	}
+
+	t.Run("EndOfDayInUserTimezone", func(t *testing.T) {
+		moscow, err := time.LoadLocation("Europe/Moscow")
+		require.NoError(t, err)
+
+		until, err := parsePauseUntil("until 2026-01-20", now, moscow)
+		require.NoError(t, err)
+		require.NotNil(t, until)
+		assert.Equal(t, time.Date(2026, 1, 20, 21, 0, 0, 0, time.UTC).Unix(), *until)
+	})
+}
+
+// Test /set_quiet_hours argument parsing
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		start    int32
+		end      int32
+		hasError bool
+	}{
+		{"Night", "23:00-07:00", 23 * 60, 7 * 60, false},
+		{"SameDay", "13:30-14:00", 13*60 + 30, 14 * 60, false},
+		{"EnDash", "22:00–06:30", 22 * 60, 6*60 + 30, false},
+		{"Off", "off", 0, 0, false},
+		{"Equal", "07:00-07:00", 0, 0, true},
+		{"MissingEnd", "23:00", 0, 0, true},
+		{"BadClock", "25:00-07:00", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := parseQuietHours(tt.input)
+			if tt.hasError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+
+	assert.Equal(t, render.HTML("23:00-07:00 UTC"), formatQuietHours(testPrinter, 23*60, 7*60, time.UTC))
+	assert.Equal(t, render.HTML("Off"), formatQuietHours(testPrinter, 0, 0, time.UTC))
+	assert.Equal(t, render.HTML("Выкл"), formatQuietHours(i18n.New(i18n.Russian), 0, 0, time.UTC))
+}
+
+func TestFormatWorkingHours(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, render.HTML("Mon-Fri 10:00-22:00; Sat 12:00-18:00 Europe/Moscow"), formatWorkingHours(testPrinter, "Mon-Fri 10:00-22:00; Sat 12:00-18:00", moscow))
+	assert.Equal(t, render.HTML("Off"), formatWorkingHours(testPrinter, "", moscow))
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+// Test slot listing formatting
+func TestFormatSlots(t *testing.T) {
+	day := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)
+	at := func(offsetDays, hour, minute int) time.Time {
+		return day.AddDate(0, 0, offsetDays).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		text, rows := formatSlots(testPrinter, nil, nil, 3, time.UTC)
+		assert.Contains(t, text, "No slots in the next 3 days")
+		assert.Empty(t, rows)
+	})
+
+	t.Run("FreeAndBooked", func(t *testing.T) {
+		slots := []external.CalendarSlot{
+			{ID: "tomorrow", Start: at(1, 10, 0), End: at(1, 11, 0), Type: models.SlotTypeFreeTime},
+			{ID: "booked", Start: at(0, 19, 0), End: at(0, 19, 30), Type: models.SlotTypeBooking},
+			{ID: "free", Start: at(0, 19, 30), End: at(0, 21, 0), Type: models.SlotTypeFreeTime},
+		}
+		bookings := []external.CalendarBooking{
+			{ID: "b1", EventSlotID: "booked", ProjectName: "go-concurrency"},
+		}
+
+		text, rows := formatSlots(testPrinter, slots, bookings, 3, time.UTC)
+
+		assert.Contains(t, text, "Wed, Jan 14")
+		assert.Contains(t, text, "Thu, Jan 15")
+		assert.Contains(t, text, "1. 📌 19:00-19:30 booked: go-concurrency")
+		assert.Contains(t, text, "2. 🟢 19:30-21:00 free")
+		assert.Contains(t, text, "3. 🟢 10:00-11:00 free")
+		assert.Less(t, strings.Index(string(text), "Wed, Jan 14"), strings.Index(string(text), "Thu, Jan 15"))
+
+		// Only free slots get buttons
+		assert.Len(t, rows, 2)
+		assert.Equal(t, FormatSlotCallbackData(SlotActionClose, 3, "free"), rows[0][0].Data)
+		assert.Equal(t, FormatSlotCallbackData(SlotActionExtend, 3, "free"), rows[0][1].Data)
+		assert.Equal(t, FormatSlotCallbackData(SlotActionClose, 3, "tomorrow"), rows[1][0].Data)
+	})
+
+	t.Run("UserTimezone", func(t *testing.T) {
+		novosibirsk, err := time.LoadLocation("Asia/Novosibirsk")
+		require.NoError(t, err)
+
+		// 19:30 UTC is 02:30 the next day in Novosibirsk
+		slots := []external.CalendarSlot{
+			{ID: "late", Start: at(0, 19, 30), End: at(0, 21, 0), Type: models.SlotTypeFreeTime},
+		}
+		text, _ := formatSlots(testPrinter, slots, nil, 3, novosibirsk)
+
+		assert.Contains(t, text, "(next 3 days, Asia/Novosibirsk)")
+		assert.Contains(t, text, "Thu, Jan 15")
+		assert.Contains(t, text, "1. 🟢 02:30-04:00 free")
+	})
+
+	t.Run("Russian", func(t *testing.T) {
+		slots := []external.CalendarSlot{
+			{ID: "free", Start: at(0, 19, 30), End: at(0, 21, 0), Type: models.SlotTypeFreeTime},
+		}
+		text, rows := formatSlots(i18n.New(i18n.Russian), slots, nil, 5, time.UTC)
+
+		assert.Contains(t, text, "(ближайшие 5 дней, UTC)")
+		assert.Contains(t, text, "Ср, 14 янв")
+		assert.Contains(t, text, "1. 🟢 19:30-21:00 свободен")
+		assert.Equal(t, "❌ Закрыть 1", rows[0][0].Text)
+	})
+
+	t.Run("ProjectNameEscaped", func(t *testing.T) {
+		slots := []external.CalendarSlot{
+			{ID: "booked", Start: at(0, 19, 0), End: at(0, 19, 30), Type: models.SlotTypeBooking},
+		}
+		bookings := []external.CalendarBooking{
+			{ID: "b1", EventSlotID: "booked", ProjectName: "<script> & *x*"},
+		}
+		text, _ := formatSlots(testPrinter, slots, bookings, 3, time.UTC)
+
+		assert.Contains(t, text, "booked: &lt;script&gt; &amp; *x*")
+		assert.Contains(t, text, "<b>")
+	})
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/projects"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/settings"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
+)
+
+// HandleOverride handles the /override command - settings of a single family or project
+func HandleOverride(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	overrides, err := store.GetSettingOverrides(ctx, user.ReviewerLogin)
+	if err != nil {
+		sendMessage(chatID, p.T("override.failed"))
+		return nil
+	}
+
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		sendMessage(chatID, formatOverrideList(p, overrides))
+		return nil
+	}
+
+	action, rest := cutWord(args)
+	remove := strings.EqualFold(action, "remove")
+	if remove {
+		args = rest
+	}
+
+	entryType, name, setting, value, ok := parseOverrideArgs(args, remove)
+	if !ok {
+		sendMessage(chatID, p.T("override.usage", overridableKeys()))
+		return nil
+	}
+	if entryType != models.EntryTypeFamily && entryType != models.EntryTypeProject {
+		sendMessage(chatID, p.T("override.invalid_type"))
+		return nil
+	}
+
+	if remove {
+		return removeOverrides(ctx, chatID, user.ReviewerLogin, overrides, entryType, name, setting, p)
+	}
+	if setting == nil {
+		sendMessage(chatID, p.T("override.unknown_setting", overridableKeys()))
+		return nil
+	}
+
+	// Overrides hold catalog names, so they match the family and project of a booking
+	familyLabel := ""
+	if entryType == models.EntryTypeFamily {
+		familyLabel = name
+	}
+	if catalog, err := projects.Load(ctx); err != nil {
+		logger.Printf("Failed to load project families, overriding %s unchecked: %v", name, err)
+	} else if !catalog.Empty() {
+		pos, ok := catalog.Find(entryType, name)
+		if !ok {
+			sendMessage(chatID, formatPresetSuggestions(p, catalog, entryType, name))
+			return nil
+		}
+		name = catalog.Name(pos)
+		familyLabel = catalog.Family(pos.Family).Label
+	}
+
+	// The rules between settings hold for the values in effect for the family or project
+	values, err := loadSettingValues(ctx, user.ReviewerLogin, prefs)
+	if err != nil {
+		sendMessage(chatID, p.T("settings.failed"))
+		return nil
+	}
+	projectName := ""
+	if entryType == models.EntryTypeProject {
+		projectName = name
+	}
+	checked, err := settings.Check(setting, value, settings.Resolve(values, overrides, projectName, familyLabel))
+	if err != nil {
+		sendMessage(chatID, p.Err(err))
+		return nil
+	}
+
+	override := &settings.Override{EntryType: entryType, Name: name, Key: setting.Key, Value: checked}
+	if err := store.SetSettingOverride(ctx, user.ReviewerLogin, override); err != nil {
+		sendMessage(chatID, p.T("override.update_failed", err))
+		return nil
+	}
+	logger.Printf("User %s overrode %s for %s %s", user.ReviewerLogin, setting.Key, entryType, name)
+
+	sendMessage(chatID, p.T("override.set", describeOverrideTarget(p, override), settingLine(p, setting, checked)))
+	return nil
+}
+
+// parseOverrideArgs splits "<family|project> <name> <setting> <value>". The name may contain
+// spaces and quotes, so the setting and value are taken from the end. For remove there is no
+// value, and the setting is optional: a name ending in a word that is no overridable setting
+// removes every override of the family or project
+func parseOverrideArgs(args string, remove bool) (entryType, name string, setting *settings.Setting, value string, ok bool) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		return "", "", nil, "", false
+	}
+	entryType, fields = strings.ToUpper(fields[0]), fields[1:]
+
+	if remove {
+		if len(fields) > 1 {
+			if setting = findOverridable(fields[len(fields)-1]); setting != nil {
+				fields = fields[:len(fields)-1]
+			}
+		}
+	} else {
+		if len(fields) < 3 {
+			return "", "", nil, "", false
+		}
+		setting, value = findOverridable(fields[len(fields)-2]), fields[len(fields)-1]
+		fields = fields[:len(fields)-2]
+	}
+
+	name = strings.Trim(strings.Join(fields, " "), `"`)
+	return entryType, name, setting, value, name != ""
+}
+
+// findOverridable returns the overridable setting with the given key or command, or nil
+func findOverridable(arg string) *settings.Setting {
+	arg = strings.TrimPrefix(strings.ToLower(arg), "/")
+	for _, s := range settings.Overridable() {
+		if arg == s.Key || arg == s.Command {
+			return s
+		}
+	}
+	return nil
+}
+
+// overridableKeys lists the settings accepted by /override, e.g. "deadline_shift, cancel_delay"
+func overridableKeys() string {
+	var keys []string
+	for _, s := range settings.Overridable() {
+		keys = append(keys, s.Key)
+	}
+	return strings.Join(keys, ", ")
+}
+
+// removeOverrides removes one override of a family or project, or all of them if setting is nil
+func removeOverrides(ctx context.Context, chatID int64, reviewerLogin string, overrides []*settings.Override, entryType, name string, setting *settings.Setting, p *i18n.Printer) error {
+	var matched []*settings.Override
+	for _, o := range overrides {
+		if o.EntryType == entryType && strings.EqualFold(o.Name, name) && (setting == nil || o.Key == setting.Key) {
+			matched = append(matched, o)
+		}
+	}
+
+	target := describeOverrideTarget(p, &settings.Override{EntryType: entryType, Name: name})
+	if len(matched) == 0 {
+		sendMessage(chatID, p.T("override.not_found", target))
+		return nil
+	}
+
+	for _, o := range matched {
+		if err := store.RemoveSettingOverride(ctx, reviewerLogin, o.EntryType, o.Name, o.Key); err != nil {
+			sendMessage(chatID, p.T("override.update_failed", err))
+			return nil
+		}
+	}
+
+	if setting != nil {
+		sendMessage(chatID, p.T("override.removed", setting.Key, target))
+	} else {
+		sendMessage(chatID, p.T("override.removed_all", target))
+	}
+	return nil
+}
+
+// describeOverrideTarget renders the family or project of an override, e.g. "family C - I"
+func describeOverrideTarget(p *i18n.Printer, o *settings.Override) render.HTML {
+	return whitelist.Describe(p, &models.WhitelistEntry{EntryType: o.EntryType, Name: o.Name})
+}
+
+// formatOverrides renders the overrides as lines of /settings and /override
+func formatOverrides(p *i18n.Printer, overrides []*settings.Override) render.HTML {
+	lines := []render.HTML{p.T("settings.overrides")}
+	for _, o := range overrides {
+		s := settings.Get(o.Key)
+		if s == nil || !s.Overridable {
+			continue
+		}
+		lines = append(lines, "  • "+p.T("override.item", describeOverrideTarget(p, o), settingLine(p, s, o.Value)))
+	}
+	return render.Join(lines, "\n")
+}
+
+// formatOverrideList renders the /override answer without arguments
+func formatOverrideList(p *i18n.Printer, overrides []*settings.Override) render.HTML {
+	if len(overrides) == 0 {
+		return p.T("override.list_empty", overridableKeys())
+	}
+	return formatOverrides(p, overrides) + "\n\n" + p.T("override.list_hint")
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/settings"
+)
+
+func TestParseOverrideArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      string
+		remove    bool
+		entryType string
+		target    string
+		setting   string
+		value     string
+		ok        bool
+	}{
+		{"Family", `family "C - I" deadline_shift 45`, false, models.EntryTypeFamily, "C - I", settings.DeadlineShift, "45", true},
+		{"ProjectByCommand", "project C2_SimpleBashUtils /set_cancel_delay 10", false, models.EntryTypeProject, "C2_SimpleBashUtils", settings.CancelDelay, "10", true},
+		{"UnknownSetting", "project DO1_Linux language ru", false, models.EntryTypeProject, "DO1_Linux", "", "ru", true},
+		{"Enum", "family C - I timeout_action whitelisted", false, models.EntryTypeFamily, "C - I", settings.TimeoutAction, "whitelisted", true},
+		{"MissingValue", "family Go deadline_shift", false, "", "", "", "", false},
+		{"MissingName", "family", false, "", "", "", "", false},
+		{"RemoveOne", "family C - I cancel_delay", true, models.EntryTypeFamily, "C - I", settings.CancelDelay, "", true},
+		{"RemoveAll", "family C - I", true, models.EntryTypeFamily, "C - I", "", "", true},
+		{"RemoveNameOnly", "project cancel_delay", true, models.EntryTypeProject, "cancel_delay", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entryType, target, setting, value, ok := parseOverrideArgs(tt.args, tt.remove)
+			require.Equal(t, tt.ok, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, tt.entryType, entryType)
+			assert.Equal(t, tt.target, target)
+			assert.Equal(t, tt.value, value)
+			if tt.setting == "" {
+				assert.Nil(t, setting)
+			} else {
+				require.NotNil(t, setting)
+				assert.Equal(t, tt.setting, setting.Key)
+			}
+		})
+	}
+}
+
+func TestFormatOverrideList(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		text := formatOverrideList(testPrinter, nil)
+		assert.Contains(t, text, "You have no overrides")
+		assert.Contains(t, text, overridableKeys())
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		overrides := []*settings.Override{
+			{EntryType: models.EntryTypeFamily, Name: "C - I", Key: settings.DeadlineShift, Value: "45"},
+			{EntryType: models.EntryTypeProject, Name: "DO1_Linux", Key: settings.CancelDelay, Value: "10"},
+			{EntryType: models.EntryTypeProject, Name: "DO1_Linux", Key: settings.Language, Value: "ru"},
+		}
+		text := formatOverrideList(testPrinter, overrides)
+		assert.Contains(t, text, "family C - I: 📅 Response Deadline Shift: 45 minutes")
+		assert.Contains(t, text, "project DO1_Linux: ⏱️ Non-Whitelist Cancel Delay: 10 minutes")
+		assert.NotContains(t, text, "Language", "settings that cannot be overridden are skipped")
+		assert.Contains(t, text, "A project override wins")
+	})
+}
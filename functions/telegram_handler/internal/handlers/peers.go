@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/policy"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+// loginPattern matches School 21 logins once lowercased
+var loginPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,31}$`)
+
+// HandlePeers handles the /peers command - students whose bookings are kept or held back
+func HandlePeers(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	action, rest := cutWord(strings.TrimSpace(message.CommandArguments()))
+	action = strings.ToLower(action)
+	switch action {
+	case "", "list":
+		peers, err := store.GetPeers(ctx, user.ReviewerLogin)
+		if err != nil {
+			sendMessage(chatID, p.T("peers.failed"))
+			return nil
+		}
+		sendMessage(chatID, formatPeerList(p, peers))
+		return nil
+
+	case "trust", "block", "remove":
+		logins, invalid := parsePeerLogins(rest)
+		if invalid != "" {
+			sendMessage(chatID, p.T("peers.invalid_login", invalid))
+			return nil
+		}
+		if len(logins) == 0 {
+			sendMessage(chatID, p.T("peers.usage"))
+			return nil
+		}
+
+		if action == "remove" {
+			for _, login := range logins {
+				if err := store.RemovePeer(ctx, user.ReviewerLogin, login); err != nil {
+					sendMessage(chatID, p.T("peers.update_failed", err))
+					return nil
+				}
+			}
+			sendMessage(chatID, p.T("peers.removed", strings.Join(logins, ", ")))
+			return nil
+		}
+
+		kind, key := store.PeerTrusted, "peers.trusted"
+		if action == "block" {
+			kind, key = store.PeerBlocked, "peers.blocked"
+		}
+		now := time.Now().Unix()
+		for _, login := range logins {
+			peer := &store.Peer{ReviewerLogin: user.ReviewerLogin, Login: login, Kind: kind, CreatedAt: now}
+			if err := store.UpsertPeer(ctx, peer); err != nil {
+				sendMessage(chatID, p.T("peers.update_failed", err))
+				return nil
+			}
+		}
+		logger.Printf("User %s marked %d peers as %s", user.ReviewerLogin, len(logins), kind)
+
+		msg := p.T(key, strings.Join(logins, ", "))
+		if !containsPolicy(userChain(prefs), policy.Peers) {
+			msg += "\n\n" + p.T("peers.not_in_chain")
+		}
+		sendMessage(chatID, msg)
+		return nil
+
+	default:
+		sendMessage(chatID, p.T("peers.usage"))
+		return nil
+	}
+}
+
+// parsePeerLogins splits logins separated by spaces or commas and lowercases them,
+// dropping a leading @ and repeats. It returns the first invalid login, if any
+func parsePeerLogins(args string) ([]string, string) {
+	var logins []string
+	seen := make(map[string]bool)
+	for _, field := range strings.FieldsFunc(args, func(r rune) bool { return r == ',' || r == ' ' }) {
+		login := strings.ToLower(strings.TrimPrefix(field, "@"))
+		if !loginPattern.MatchString(login) {
+			return nil, field
+		}
+		if !seen[login] {
+			seen[login] = true
+			logins = append(logins, login)
+		}
+	}
+	return logins, ""
+}
+
+// formatPeerList renders the /peers answer without arguments
+func formatPeerList(p *i18n.Printer, peers []*store.Peer) render.HTML {
+	if len(peers) == 0 {
+		return p.T("peers.list_empty")
+	}
+
+	var trusted, blocked []string
+	for _, peer := range peers {
+		if peer.Kind == store.PeerBlocked {
+			blocked = append(blocked, peer.Login)
+		} else {
+			trusted = append(trusted, peer.Login)
+		}
+	}
+	return p.T("peers.list_title") + "\n\n" +
+		p.T("peers.list_trusted", joinOrNone(p, trusted)) + "\n" +
+		p.T("peers.list_blocked", joinOrNone(p, blocked)) + "\n\n" +
+		p.T("peers.list_hint")
+}
+
+// joinOrNone joins logins with commas, or says there are none
+func joinOrNone(p *i18n.Printer, logins []string) render.HTML {
+	if len(logins) == 0 {
+		return p.T("peers.list_none")
+	}
+	return render.Escape(strings.Join(logins, ", "))
+}
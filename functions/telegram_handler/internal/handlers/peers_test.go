@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+func TestParsePeerLogins(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		invalid  string
+	}{
+		{"Empty", " ", nil, ""},
+		{"Single", "friendly", []string{"friendly"}, ""},
+		{"Several", "@Friendly, groupmate  careless,friendly", []string{"friendly", "groupmate", "careless"}, ""},
+		{"Invalid", "friendly gr@upmate", nil, "gr@upmate"},
+		{"TooShort", "f", nil, "f"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logins, invalid := parsePeerLogins(tt.input)
+			assert.Equal(t, tt.expected, logins)
+			assert.Equal(t, tt.invalid, invalid)
+		})
+	}
+}
+
+func TestFormatPeerList(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		assert.Contains(t, formatPeerList(testPrinter, nil), "You have no peers")
+	})
+
+	t.Run("Peers", func(t *testing.T) {
+		text := formatPeerList(testPrinter, []*store.Peer{
+			{Login: "careless", Kind: store.PeerBlocked},
+			{Login: "friendly", Kind: store.PeerTrusted},
+			{Login: "groupmate", Kind: store.PeerTrusted},
+		})
+		assert.Contains(t, text, "Trusted: friendly, groupmate\nBlocked: careless")
+		assert.Contains(t, text, "/set_blocked_peer_action")
+	})
+
+	t.Run("NoneBlocked", func(t *testing.T) {
+		text := formatPeerList(testPrinter, []*store.Peer{{Login: "friendly", Kind: store.PeerTrusted}})
+		assert.Contains(t, text, "Blocked: none")
+	})
+}
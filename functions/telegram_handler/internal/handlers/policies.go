@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/policy"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+// HandlePolicies handles the /policies command - the order of the decision policies
+func HandlePolicies(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		sendMessage(chatID, formatPolicyList(p, userChain(prefs)))
+		return nil
+	}
+
+	// An empty chain stands for the default, so later changes to it reach the user
+	if strings.EqualFold(args, "reset") {
+		if err := store.UpdateTextSetting(ctx, user.ReviewerLogin, "decision_policies", ""); err != nil {
+			sendMessage(chatID, p.T("policies.update_failed", err))
+			return nil
+		}
+		sendMessage(chatID, p.T("policies.reset", formatPolicyChain(policy.DefaultChain)))
+		return nil
+	}
+
+	names, err := policy.ParseChain(args)
+	if err != nil {
+		sendMessage(chatID, p.Err(err))
+		return nil
+	}
+	if err := store.UpdateTextSetting(ctx, user.ReviewerLogin, "decision_policies", policy.FormatChain(names)); err != nil {
+		sendMessage(chatID, p.T("policies.update_failed", err))
+		return nil
+	}
+	logger.Printf("User %s set decision policies %v", user.ReviewerLogin, names)
+
+	sendMessage(chatID, p.T("policies.updated", formatPolicyChain(names)))
+	return nil
+}
+
+// userChain returns the user's decision policies, falling back to the default chain
+// if the stored one no longer parses
+func userChain(prefs *store.UserPreferences) []string {
+	names, err := policy.ParseChain(prefs.DecisionPolicies)
+	if err != nil {
+		return policy.DefaultChain
+	}
+	return names
+}
+
+// formatPolicyChain renders a chain in the order its policies are asked, e.g. "deny_list → whitelist"
+func formatPolicyChain(names []string) string {
+	return strings.Join(names, " → ")
+}
+
+// formatPolicyList renders the /policies answer without arguments
+func formatPolicyList(p *i18n.Printer, names []string) render.HTML {
+	var lines []render.HTML
+	for _, name := range policy.Names() {
+		lines = append(lines, render.Escape("• "+name+" - ")+p.T("policy."+name))
+	}
+	return p.T("policies.list", formatPolicyChain(names), render.Join(lines, "\n"))
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/policy"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+func TestUserChain(t *testing.T) {
+	prefs := store.DefaultUserPreferences("testuser")
+	assert.Equal(t, policy.DefaultChain, userChain(prefs))
+
+	prefs.DecisionPolicies = "deny_list,whitelist"
+	assert.Equal(t, []string{policy.DenyList, policy.Whitelist}, userChain(prefs))
+
+	prefs.DecisionPolicies = "whitelist,retired"
+	assert.Equal(t, policy.DefaultChain, userChain(prefs), "a chain that no longer parses")
+}
+
+func TestFormatPolicyList(t *testing.T) {
+	text := formatPolicyList(testPrinter, []string{policy.Whitelist, policy.DenyList})
+	assert.Contains(t, text, "Your chain: whitelist → deny_list")
+	assert.Contains(t, text, "• deny_list - cancel bookings of blacklisted projects right away")
+	assert.Contains(t, text, "• whitelist - keep bookings of whitelisted projects")
+	assert.Contains(t, text, "/policies reset")
+}
@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/projects"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
+)
+
+// isAdmin reports whether a user may change every preset. Admins are listed by login,
+// comma-separated, in the BOT_ADMINS environment variable
+func isAdmin(reviewerLogin string) bool {
+	for _, login := range strings.Split(os.Getenv("BOT_ADMINS"), ",") {
+		if strings.TrimSpace(login) == reviewerLogin {
+			return true
+		}
+	}
+	return false
+}
+
+// canEditPreset reports whether a user may change a preset: its owner and admins can
+func canEditPreset(preset *store.WhitelistPreset, reviewerLogin string) bool {
+	return preset.OwnerLogin == reviewerLogin || isAdmin(reviewerLogin)
+}
+
+// cutWord splits the first word off s
+func cutWord(s string) (string, string) {
+	word, rest, _ := strings.Cut(strings.TrimSpace(s), " ")
+	return word, strings.TrimSpace(rest)
+}
+
+// HandlePreset handles the /preset command - shared whitelist presets
+func HandlePreset(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	action, rest := cutWord(message.CommandArguments())
+	if action == "" {
+		return showPresets(ctx, chatID, user.ReviewerLogin, p)
+	}
+
+	name, rest := cutWord(rest)
+	if name == "" {
+		sendMessage(chatID, p.T("preset.usage"))
+		return nil
+	}
+
+	switch strings.ToLower(action) {
+	case "create":
+		if err := whitelist.ValidatePresetName(name); err != nil {
+			sendMessage(chatID, p.Err(err))
+			return nil
+		}
+		if _, ok := loadPreset(ctx, chatID, name, p, false); ok {
+			sendMessage(chatID, p.T("preset.exists", name))
+			return nil
+		}
+
+		preset := &store.WhitelistPreset{Name: name, OwnerLogin: user.ReviewerLogin, CreatedAt: time.Now().Unix()}
+		if err := store.CreatePreset(ctx, preset); err != nil {
+			sendMessage(chatID, p.T("preset.update_failed", err))
+			return nil
+		}
+		logger.Printf("User %s created preset %s", user.ReviewerLogin, name)
+		sendMessage(chatID, p.T("preset.created", name, name))
+		return nil
+
+	case "show":
+		preset, ok := loadPreset(ctx, chatID, name, p, true)
+		if !ok {
+			return nil
+		}
+		entries, err := store.GetPresetEntries(ctx, preset.Name)
+		if err != nil {
+			sendMessage(chatID, p.T("preset.failed"))
+			return nil
+		}
+		sendMessage(chatID, formatPreset(p, preset, entries))
+		return nil
+
+	case "subscribe":
+		preset, ok := loadPreset(ctx, chatID, name, p, true)
+		if !ok {
+			return nil
+		}
+		if err := store.SubscribePreset(ctx, user.ReviewerLogin, preset.Name); err != nil {
+			sendMessage(chatID, p.T("preset.update_failed", err))
+			return nil
+		}
+		sendMessage(chatID, p.T("preset.subscribed", preset.Name))
+		return nil
+
+	case "unsubscribe":
+		subscriptions, err := store.GetSubscriptions(ctx, user.ReviewerLogin)
+		if err != nil {
+			sendMessage(chatID, p.T("preset.failed"))
+			return nil
+		}
+		if !containsString(subscriptions, name) {
+			sendMessage(chatID, p.T("preset.not_subscribed", name))
+			return nil
+		}
+		if err := store.UnsubscribePreset(ctx, user.ReviewerLogin, name); err != nil {
+			sendMessage(chatID, p.T("preset.update_failed", err))
+			return nil
+		}
+		sendMessage(chatID, p.T("preset.unsubscribed", name))
+		return nil
+
+	case "add", "remove", "delete":
+		preset, ok := loadPreset(ctx, chatID, name, p, true)
+		if !ok {
+			return nil
+		}
+		if !canEditPreset(preset, user.ReviewerLogin) {
+			sendMessage(chatID, p.T("preset.forbidden", preset.Name))
+			return nil
+		}
+		return editPreset(ctx, chatID, strings.ToLower(action), preset, rest, p, logger)
+
+	default:
+		sendMessage(chatID, p.T("preset.usage"))
+		return nil
+	}
+}
+
+// loadPreset fetches a preset by name and reports whether there is one. With reply set, it
+// tells the user when there is not
+func loadPreset(ctx context.Context, chatID int64, name string, p *i18n.Printer, reply bool) (*store.WhitelistPreset, bool) {
+	preset, err := store.GetPreset(ctx, name)
+	if err != nil {
+		if reply {
+			sendMessage(chatID, p.T("preset.failed"))
+		}
+		return nil, false
+	}
+	if preset == nil {
+		if reply {
+			sendMessage(chatID, p.T("preset.not_found", name))
+		}
+		return nil, false
+	}
+	return preset, true
+}
+
+// editPreset adds an entry to a preset, removes one, or deletes the whole preset
+func editPreset(ctx context.Context, chatID int64, action string, preset *store.WhitelistPreset, args string, p *i18n.Printer, logger *log.Logger) error {
+	switch action {
+	case "delete":
+		if err := store.DeletePreset(ctx, preset.Name); err != nil {
+			sendMessage(chatID, p.T("preset.update_failed", err))
+			return nil
+		}
+		logger.Printf("Preset %s deleted", preset.Name)
+		sendMessage(chatID, p.T("preset.deleted", preset.Name))
+		return nil
+
+	case "remove":
+		name := strings.Trim(args, `"`)
+		if name == "" {
+			sendMessage(chatID, p.T("preset.usage"))
+			return nil
+		}
+		entries, err := store.GetPresetEntries(ctx, preset.Name)
+		if err != nil {
+			sendMessage(chatID, p.T("preset.failed"))
+			return nil
+		}
+		found := false
+		for _, entry := range entries {
+			found = found || entry.Name == name
+		}
+		if !found {
+			sendMessage(chatID, p.T("preset.entry_not_found", name, preset.Name))
+			return nil
+		}
+		if err := store.RemovePresetEntry(ctx, preset.Name, name); err != nil {
+			sendMessage(chatID, p.T("preset.update_failed", err))
+			return nil
+		}
+		sendMessage(chatID, p.T("preset.entry_removed", name, preset.Name))
+		return nil
+	}
+
+	// add <family|project|glob|regex> <entry>
+	entryType, name := cutWord(args)
+	entryType = strings.ToUpper(entryType)
+	name = strings.Trim(name, `"`)
+	if name == "" {
+		sendMessage(chatID, p.T("preset.usage"))
+		return nil
+	}
+	if !whitelist.IsValidPresetEntryType(entryType) {
+		sendMessage(chatID, p.T("whitelist.invalid_type"))
+		return nil
+	}
+
+	if whitelist.IsPattern(entryType) {
+		if err := whitelist.Validate(entryType, name); err != nil {
+			sendMessage(chatID, p.Err(err))
+			return nil
+		}
+	} else if catalog, err := projects.Load(ctx); err != nil {
+		logger.Printf("Failed to load project families, adding %s unchecked: %v", name, err)
+	} else if !catalog.Empty() {
+		// Same check as /whitelist_add, but suggestions are plain text here since the
+		// buttons add to the personal whitelist
+		pos, ok := catalog.Find(entryType, name)
+		if !ok {
+			sendMessage(chatID, formatPresetSuggestions(p, catalog, entryType, name))
+			return nil
+		}
+		name = catalog.Name(pos)
+	}
+
+	entry := &store.PresetEntry{PresetName: preset.Name, EntryType: entryType, Name: name}
+	if err := store.AddPresetEntry(ctx, entry); err != nil {
+		sendMessage(chatID, p.T("preset.update_failed", err))
+		return nil
+	}
+	sendMessage(chatID, p.T("preset.entry_added", whitelist.Describe(p, presetWhitelistEntry(entry)), preset.Name))
+	return nil
+}
+
+// formatPresetSuggestions renders the reply to an unknown name in /preset add
+func formatPresetSuggestions(p *i18n.Printer, catalog *projects.Catalog, entryType, name string) render.HTML {
+	key := "whitelist.unknown_project"
+	if entryType == models.EntryTypeFamily {
+		key = "whitelist.unknown_family"
+	}
+	msg := p.T(key, name)
+
+	var names []string
+	for _, pos := range catalog.Suggest(entryType, name, whitelistSuggestions) {
+		names = append(names, catalog.Name(pos))
+	}
+	if len(names) == 0 {
+		return msg + "\n\n" + p.T("whitelist.no_suggestions")
+	}
+	return msg + "\n\n" + p.T("preset.did_you_mean", strings.Join(names, ", "))
+}
+
+// showPresets lists all presets, marking the ones the user subscribes to
+func showPresets(ctx context.Context, chatID int64, reviewerLogin string, p *i18n.Printer) error {
+	presets, err := store.GetPresets(ctx)
+	if err != nil {
+		sendMessage(chatID, p.T("preset.failed"))
+		return nil
+	}
+	subscriptions, err := store.GetSubscriptions(ctx, reviewerLogin)
+	if err != nil {
+		sendMessage(chatID, p.T("preset.failed"))
+		return nil
+	}
+
+	counts := make(map[string]int, len(presets))
+	for _, preset := range presets {
+		entries, err := store.GetPresetEntries(ctx, preset.Name)
+		if err != nil {
+			sendMessage(chatID, p.T("preset.failed"))
+			return nil
+		}
+		counts[preset.Name] = len(entries)
+	}
+
+	sendMessage(chatID, formatPresetList(p, presets, counts, subscriptions))
+	return nil
+}
+
+// formatPresetList renders the /preset answer
+func formatPresetList(p *i18n.Printer, presets []*store.WhitelistPreset, counts map[string]int, subscriptions []string) render.HTML {
+	if len(presets) == 0 {
+		return p.T("preset.list_empty")
+	}
+
+	msg := p.T("preset.list_title") + "\n\n"
+	for _, preset := range presets {
+		mark := "▫️ "
+		if containsString(subscriptions, preset.Name) {
+			mark = "✅ "
+		}
+		msg += render.Escape(mark) + p.T("preset.list_item", preset.Name, p.N("unit.entries", counts[preset.Name]), preset.OwnerLogin) + "\n"
+	}
+	return msg + "\n" + p.T("preset.list_hint")
+}
+
+// formatPreset renders the /preset show answer
+func formatPreset(p *i18n.Printer, preset *store.WhitelistPreset, entries []*store.PresetEntry) render.HTML {
+	msg := p.T("preset.show_title", preset.Name, preset.OwnerLogin) + "\n\n"
+	if len(entries) == 0 {
+		return msg + p.T("preset.show_empty")
+	}
+	for _, entry := range entries {
+		msg += "  • " + whitelist.Describe(p, presetWhitelistEntry(entry)) + "\n"
+	}
+	return msg
+}
+
+func presetWhitelistEntry(entry *store.PresetEntry) *models.WhitelistEntry {
+	return &models.WhitelistEntry{EntryType: entry.EntryType, Name: entry.Name}
+}
+
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
+)
+
+func TestIsAdmin(t *testing.T) {
+	t.Setenv("BOT_ADMINS", "alice, bob")
+	assert.True(t, isAdmin("alice"))
+	assert.True(t, isAdmin("bob"))
+	assert.False(t, isAdmin("carol"))
+
+	t.Setenv("BOT_ADMINS", "")
+	assert.False(t, isAdmin("alice"))
+}
+
+func TestCanEditPreset(t *testing.T) {
+	t.Setenv("BOT_ADMINS", "admin")
+	preset := &store.WhitelistPreset{Name: "go-team", OwnerLogin: "alice"}
+
+	assert.True(t, canEditPreset(preset, "alice"))
+	assert.True(t, canEditPreset(preset, "admin"))
+	assert.False(t, canEditPreset(preset, "bob"))
+}
+
+func TestCutWord(t *testing.T) {
+	tests := []struct {
+		input string
+		word  string
+		rest  string
+	}{
+		{"", "", ""},
+		{"show", "show", ""},
+		{"  add go-team  family C - I ", "add", "go-team  family C - I"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			word, rest := cutWord(tt.input)
+			assert.Equal(t, tt.word, word)
+			assert.Equal(t, tt.rest, rest)
+		})
+	}
+}
+
+func TestFormatPresetList(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		assert.Contains(t, string(formatPresetList(testPrinter, nil, nil, nil)), "There are no presets yet")
+	})
+
+	t.Run("MarksSubscriptions", func(t *testing.T) {
+		presets := []*store.WhitelistPreset{
+			{Name: "c-piscine", OwnerLogin: "alice"},
+			{Name: "go-team", OwnerLogin: "bob"},
+		}
+		counts := map[string]int{"c-piscine": 1, "go-team": 3}
+
+		msg := string(formatPresetList(testPrinter, presets, counts, []string{"go-team"}))
+		assert.Contains(t, msg, "▫️ c-piscine - 1 entry, by alice")
+		assert.Contains(t, msg, "✅ go-team - 3 entries, by bob")
+	})
+}
+
+func TestFormatPreset(t *testing.T) {
+	preset := &store.WhitelistPreset{Name: "go-team", OwnerLogin: "bob"}
+
+	msg := string(formatPreset(testPrinter, preset, nil))
+	assert.Contains(t, msg, "Owner: bob")
+	assert.Contains(t, msg, "The preset has no entries yet.")
+
+	entries := []*store.PresetEntry{
+		{PresetName: "go-team", EntryType: models.EntryTypeFamily, Name: "Go"},
+		{PresetName: "go-team", EntryType: whitelist.EntryTypeGlob, Name: "go-*"},
+	}
+	msg = string(formatPreset(testPrinter, preset, entries))
+	assert.Contains(t, msg, "• family Go")
+	assert.Contains(t, msg, "• glob pattern go-*")
+	assert.NotContains(t, msg, "no entries")
+}
+
+func TestFormatPresetSuggestions(t *testing.T) {
+	catalog := testProjectCatalog()
+
+	msg := string(formatPresetSuggestions(testPrinter, catalog, models.EntryTypeProject, "go-concurency"))
+	assert.Contains(t, msg, "Did you mean go-concurrency")
+
+	msg = string(formatPresetSuggestions(testPrinter, catalog, models.EntryTypeFamily, "zzzzzzzz"))
+	assert.NotContains(t, msg, "Did you mean")
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/uuid"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/timeutil"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/availability"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/policy"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/projects"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/rules"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+// HandleRule handles the /rule command - decision rules written as expressions
+func HandleRule(ctx context.Context, message *tba.Message, logger *log.Logger) error {
+	chatID := message.From.ID
+
+	// Get user
+	user, err := ydb.GetUserByTelegramChatID(ctx, chatID)
+	if err != nil {
+		sendMessage(chatID, clientPrinter(message.From).T("common.user_not_found"))
+		return nil
+	}
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, message.From, logger)
+
+	action, rest := cutWord(strings.TrimSpace(message.CommandArguments()))
+	switch strings.ToLower(action) {
+	case "", "list":
+		decisionRules, err := store.GetDecisionRules(ctx, user.ReviewerLogin)
+		if err != nil {
+			sendMessage(chatID, p.T("rule.failed"))
+			return nil
+		}
+		sendMessage(chatID, formatRuleList(p, decisionRules))
+		return nil
+
+	case "add":
+		ruleAction, expression := cutWord(rest)
+		ruleAction = strings.ToLower(ruleAction)
+		if !rules.IsValidAction(ruleAction) || expression == "" {
+			sendMessage(chatID, p.T("rule.usage", strings.Join(rules.AttributeNames(), ", ")))
+			return nil
+		}
+
+		// Rules are checked when saved, so the periodic job only meets valid ones
+		if _, err := rules.Compile(expression); err != nil {
+			sendMessage(chatID, p.T("rule.invalid", p.Err(err)))
+			return nil
+		}
+
+		rule := &store.DecisionRule{
+			ReviewerLogin: user.ReviewerLogin,
+			ID:            uuid.New().String(),
+			Action:        ruleAction,
+			Expression:    expression,
+			CreatedAt:     time.Now().Unix(),
+		}
+		if err := store.AddDecisionRule(ctx, rule); err != nil {
+			sendMessage(chatID, p.T("rule.add_failed", err))
+			return nil
+		}
+		logger.Printf("User %s added a %s rule", user.ReviewerLogin, ruleAction)
+
+		msg := p.T("rule.added", formatRule(p, rule))
+		if !containsPolicy(userChain(prefs), policy.Rules) {
+			msg += "\n\n" + p.T("rule.not_in_chain")
+		}
+		sendMessage(chatID, msg)
+		return nil
+
+	case "remove":
+		index, err := strconv.Atoi(rest)
+		if err != nil {
+			sendMessage(chatID, p.T("rule.remove_usage"))
+			return nil
+		}
+
+		decisionRules, err := store.GetDecisionRules(ctx, user.ReviewerLogin)
+		if err != nil {
+			sendMessage(chatID, p.T("rule.failed"))
+			return nil
+		}
+		if index < 1 || index > len(decisionRules) {
+			sendMessage(chatID, p.T("rule.not_found", index))
+			return nil
+		}
+
+		rule := decisionRules[index-1]
+		if err := store.RemoveDecisionRule(ctx, user.ReviewerLogin, rule.ID); err != nil {
+			sendMessage(chatID, p.T("rule.remove_failed", err))
+			return nil
+		}
+		sendMessage(chatID, p.T("rule.removed", formatRule(p, rule)))
+		return nil
+
+	case "test":
+		return testRules(ctx, chatID, user.ReviewerLogin, rest, prefs, p, logger)
+
+	default:
+		sendMessage(chatID, p.T("rule.usage", strings.Join(rules.AttributeNames(), ", ")))
+		return nil
+	}
+}
+
+// testRules answers /rule test <project> <day> <HH:MM> with the rules that hold for such a booking
+func testRules(ctx context.Context, chatID int64, reviewerLogin, args string, prefs *store.UserPreferences, p *i18n.Printer, logger *log.Logger) error {
+	fields := strings.Fields(args)
+	if len(fields) < 3 {
+		sendMessage(chatID, p.T("rule.test_usage"))
+		return nil
+	}
+
+	now := time.Now()
+	loc := prefs.Location()
+	day, err := availability.ParseDay(fields[len(fields)-2], now, loc)
+	if err != nil {
+		sendMessage(chatID, p.T("rule.test_invalid", p.Err(err)))
+		return nil
+	}
+	minute, err := availability.ParseClock(fields[len(fields)-1])
+	if err != nil {
+		sendMessage(chatID, p.T("rule.test_invalid", p.Err(err)))
+		return nil
+	}
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, int(minute), 0, 0, loc)
+
+	// Rules see catalog names, like the bookings the periodic job decides on
+	projectName, familyLabel := strings.Join(fields[:len(fields)-2], " "), ""
+	if catalog, err := projects.Load(ctx); err != nil {
+		logger.Printf("Failed to load project families, testing %s without a family: %v", projectName, err)
+	} else if pos, ok := catalog.Find(models.EntryTypeProject, projectName); ok {
+		projectName = catalog.Name(pos)
+		familyLabel = catalog.Family(pos.Family).Label
+	}
+
+	decisionRules, err := store.GetDecisionRules(ctx, reviewerLogin)
+	if err != nil {
+		sendMessage(chatID, p.T("rule.failed"))
+		return nil
+	}
+	accepted, err := ydb.GetReviewRequestsByUserAndStatus(ctx, reviewerLogin, []string{models.StatusApproved, models.StatusWhitelisted})
+	if err != nil {
+		logger.Printf("Failed to get accepted reviews of %s, testing without them: %v", reviewerLogin, err)
+	}
+
+	booking := &policy.Booking{
+		ProjectName: projectName,
+		FamilyLabel: familyLabel,
+		Start:       start,
+		Rules:       decisionRules,
+		Location:    loc,
+		Now:         now,
+	}
+	for _, req := range accepted {
+		booking.Accepted = append(booking.Accepted, timeutil.FromUnixSeconds(req.ReviewStartTime))
+	}
+
+	sendMessage(chatID, formatRuleTest(p, booking))
+	return nil
+}
+
+// formatRule renders a rule as "approve: <expression>"
+func formatRule(p *i18n.Printer, rule *store.DecisionRule) render.HTML {
+	return p.T("rule.item", rule.Action, rule.Expression)
+}
+
+// formatRuleList renders the /rule answer without arguments
+func formatRuleList(p *i18n.Printer, decisionRules []*store.DecisionRule) render.HTML {
+	attributes := strings.Join(rules.AttributeNames(), ", ")
+	if len(decisionRules) == 0 {
+		return p.T("rule.list_empty", attributes)
+	}
+
+	lines := []render.HTML{p.T("rule.list_title")}
+	for i, rule := range decisionRules {
+		lines = append(lines, render.Escape(strconv.Itoa(i+1)+". ")+formatRule(p, rule))
+	}
+	return render.Join(lines, "\n") + "\n\n" + p.T("rule.list_hint", attributes)
+}
+
+// formatRuleTest renders the attributes of a booking, whether each rule holds for it
+// and the decision of the rules policy
+func formatRuleTest(p *i18n.Printer, b *policy.Booking) render.HTML {
+	attrs := policy.RuleAttributes(b)
+	msg := p.T("rule.test_title", p.FormatShort(b.Start, b.Location), rules.FormatAttributes(attrs))
+	if len(b.Rules) == 0 {
+		return msg + "\n\n" + p.T("rule.test_no_rules")
+	}
+
+	lines := make([]render.HTML, 0, len(b.Rules))
+	for i, rule := range b.Rules {
+		mark := "❌"
+		program, err := rules.Compile(rule.Expression)
+		if err == nil {
+			var holds bool
+			if holds, err = program.Eval(attrs, rules.EvalTimeout); holds {
+				mark = "✅"
+			}
+		}
+		line := render.Escape(mark+" "+strconv.Itoa(i+1)+". ") + formatRule(p, rule)
+		if err != nil {
+			line += " " + p.T("rule.test_error", p.Err(err))
+		}
+		lines = append(lines, line)
+	}
+	msg += "\n\n" + render.Join(lines, "\n")
+
+	result := policy.NewChain([]string{policy.Rules}).Decide(b)
+	if result.Rule == nil {
+		return msg + "\n\n" + p.T("rule.test_none")
+	}
+	return msg + "\n\n" + p.T("rule.test_decision", result.Rule.Action)
+}
+
+// containsPolicy reports whether the chain asks the named policy
+func containsPolicy(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
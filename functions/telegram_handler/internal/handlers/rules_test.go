@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/policy"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/rules"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+func TestFormatRuleList(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		text := formatRuleList(testPrinter, nil)
+		assert.Contains(t, text, "You have no rules")
+		assert.Contains(t, text, "minutes_until")
+	})
+
+	t.Run("Rules", func(t *testing.T) {
+		text := formatRuleList(testPrinter, []*store.DecisionRule{
+			{Action: rules.ActionApprove, Expression: `family == "CPP" && hour >= 18`},
+			{Action: rules.ActionDecline, Expression: "reviews_today >= 3"},
+		})
+		assert.Contains(t, text, "1. approve: <code>family == \"CPP\" &amp;&amp; hour &gt;= 18</code>")
+		assert.Contains(t, text, "2. decline: <code>reviews_today &gt;= 3</code>")
+		assert.Contains(t, text, "The first rule that holds decides")
+	})
+}
+
+func TestFormatRuleTest(t *testing.T) {
+	start := time.Date(2026, 3, 4, 19, 0, 0, 0, time.UTC)
+	booking := &policy.Booking{
+		ProjectName: "CPP1_s21_matrix+",
+		FamilyLabel: "CPP",
+		Start:       start,
+		Location:    time.UTC,
+		Now:         start.Add(-2 * time.Hour),
+	}
+
+	t.Run("NoRules", func(t *testing.T) {
+		text := formatRuleTest(testPrinter, booking)
+		assert.Contains(t, text, `project = "CPP1_s21_matrix+"`)
+		assert.Contains(t, text, "weekday = 3")
+		assert.Contains(t, text, "minutes_until = 120")
+		assert.Contains(t, text, "You have no rules yet")
+	})
+
+	t.Run("Decision", func(t *testing.T) {
+		b := *booking
+		b.Rules = []*store.DecisionRule{
+			{Action: rules.ActionDecline, Expression: "hour < 9"},
+			{Action: rules.ActionAsk, Expression: "room == 1"},
+			{Action: rules.ActionApprove, Expression: "weekday in [1..4]"},
+		}
+		text := formatRuleTest(testPrinter, &b)
+		assert.Contains(t, text, "❌ 1. decline")
+		assert.Contains(t, text, "❌ 2. ask: <code>room == 1</code> (Unknown attribute \"room\" at position 1")
+		assert.Contains(t, text, "✅ 3. approve")
+		assert.Contains(t, text, "Decision: approve")
+	})
+
+	t.Run("NoRuleHolds", func(t *testing.T) {
+		b := *booking
+		b.Rules = []*store.DecisionRule{{Action: rules.ActionDecline, Expression: "hour < 9"}}
+		assert.Contains(t, formatRuleTest(testPrinter, &b), "No rule holds")
+	})
+}
+
+func TestContainsPolicy(t *testing.T) {
+	assert.True(t, containsPolicy(policy.DefaultChain, policy.Rules))
+	assert.False(t, containsPolicy([]string{policy.Whitelist}, policy.Rules))
+}
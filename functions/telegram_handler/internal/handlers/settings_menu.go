@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/telegram"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/settings"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+// menuView holds how a registry setting is presented in the /settings menu
+type menuView struct {
+	Jump        int // Int settings also offer -/+ Jump next to -/+ Step, if set
+	optionLabel func(p *i18n.Printer, option string) render.HTML
+}
+
+var menuViews = map[string]menuView{
+	settings.DeadlineShift:      {Jump: 5},
+	settings.HousekeepingBuffer: {Jump: 15},
+	settings.AvailabilityDays:   {Jump: 7},
+	settings.Lookahead:          {Jump: 24},
+	settings.Lookback:           {Jump: 6},
+	settings.PausePolicy:        {optionLabel: describePausePolicy},
+	settings.MinGap:             {Jump: 30},
+	settings.LimitAction:        {optionLabel: describeAction},
+	settings.OutsideHoursAction: {optionLabel: describeAction},
+	settings.BlockedPeerAction:  {optionLabel: describeAction},
+	settings.TimeoutAction:      {optionLabel: describeTimeoutAction},
+	settings.NonWhitelistAction: {optionLabel: func(p *i18n.Printer, action string) render.HTML {
+		return p.T("non_whitelist_action." + strings.ToLower(action))
+	}},
+	settings.NonWhitelistDeadline: {Jump: 15},
+	settings.Language: {optionLabel: func(p *i18n.Printer, lang string) render.HTML {
+		if lang == "" {
+			// Not chosen yet, the printer already uses the client language
+			return p.T("language.name")
+		}
+		return i18n.New(lang).T("language.name")
+	}},
+}
+
+// describeAction labels the ask and decline options of the limit, outside hours and blocked peer actions
+func describeAction(p *i18n.Printer, action string) render.HTML {
+	return p.T("limit_action." + strings.ToLower(action))
+}
+
+// describeTimeoutAction labels the options of the timeout action
+func describeTimeoutAction(p *i18n.Printer, action string) render.HTML {
+	switch action {
+	case settings.TimeoutApprove:
+		return p.T("timeout_action.approve")
+	case settings.TimeoutApproveWhitelisted:
+		return p.T("timeout_action.whitelisted")
+	default:
+		return p.T("timeout_action.cancel")
+	}
+}
+
+// displaySetting renders a value in the form shown by /settings
+func displaySetting(p *i18n.Printer, s *settings.Setting, value string) render.HTML {
+	switch s.Type {
+	case settings.Bool:
+		return boolToYesNo(p, value == "true")
+	case settings.Enum:
+		if label := menuViews[s.Key].optionLabel; label != nil {
+			return label(p, value)
+		}
+		return render.Escape(value)
+	default:
+		n, _ := strconv.Atoi(value)
+		if n == 0 && s.Off {
+			return p.T("settings.off")
+		}
+		return p.N("unit."+s.Unit, n)
+	}
+}
+
+// settingLine renders the settings line with the current value
+func settingLine(p *i18n.Printer, s *settings.Setting, value string) render.HTML {
+	return p.T(s.Label, displaySetting(p, s, value))
+}
+
+// formatSettingsHelp renders the /help lines of the registry settings, e.g.
+// "/set_lookback <hours> - How far back new bookings are picked up (0-24)"
+func formatSettingsHelp(p *i18n.Printer) render.HTML {
+	lines := make([]render.HTML, 0, len(settings.All()))
+	for _, s := range settings.All() {
+		line := render.Format("/%s %s - %s", s.Command, helpArgument(p, s), p.T(s.Description))
+		switch {
+		case s.Type != settings.Int:
+		case len(s.Values) > 0:
+			line += render.Format(" (%s)", s.FormatValues())
+		case s.Step > 1:
+			line += render.Format(" (%d-%d, %s)", s.Min, s.Max, p.T("help.step", s.Step))
+		default:
+			line += render.Format(" (%d-%d)", s.Min, s.Max)
+		}
+		lines = append(lines, line)
+	}
+	return render.Join(lines, "\n")
+}
+
+// helpArgument renders the argument of a setting command, e.g. "<minutes>" or "<off|trim|split>"
+func helpArgument(p *i18n.Printer, s *settings.Setting) render.HTML {
+	switch s.Type {
+	case settings.Bool:
+		return render.Escape("<on|off>")
+	case settings.Enum:
+		names := make([]string, len(s.Options))
+		for i, option := range s.Options {
+			names[i] = strings.ToLower(option)
+			// Prefer the short form the usage message shows
+			for alias, target := range s.Aliases {
+				if target == option {
+					names[i] = alias
+				}
+			}
+		}
+		return render.Escape("<" + strings.Join(names, "|") + ">")
+	default:
+		return p.T("help.arg_" + s.Unit)
+	}
+}
+
+// loadSettingValues returns the current value of every registry setting
+func loadSettingValues(ctx context.Context, reviewerLogin string, prefs *store.UserPreferences) (settings.Values, error) {
+	userSettings, err := ydb.GetUserSettings(ctx, reviewerLogin)
+	if err != nil {
+		return nil, err
+	}
+	return store.SettingValues(userSettings, prefs), nil
+}
+
+// loadOverrides returns the user's setting overrides, or none if they cannot be read
+func loadOverrides(ctx context.Context, reviewerLogin string, logger *log.Logger) []*settings.Override {
+	overrides, err := store.GetSettingOverrides(ctx, reviewerLogin)
+	if err != nil {
+		logger.Printf("Failed to get setting overrides for user %s: %v", reviewerLogin, err)
+	}
+	return overrides
+}
+
+// Settings menu callback actions
+const (
+	SettingsActionMenu = "menu"
+	SettingsActionOpen = "open"
+	SettingsActionSet  = "set"
+)
+
+const settingsCallbackPrefix = "SETTINGS"
+
+// FormatSettingsCallbackData creates callback data for a settings menu button, e.g. "SETTINGS:set:lookback:6"
+func FormatSettingsCallbackData(action, key, value string) string {
+	return strings.TrimRight(fmt.Sprintf("%s:%s:%s:%s", settingsCallbackPrefix, action, key, value), ":")
+}
+
+// ParseSettingsCallbackData parses callback data created by FormatSettingsCallbackData
+func ParseSettingsCallbackData(data string) (action, key, value string, ok bool) {
+	parts := strings.SplitN(data, ":", 4)
+	if len(parts) < 2 || parts[0] != settingsCallbackPrefix {
+		return "", "", "", false
+	}
+	for len(parts) < 4 {
+		parts = append(parts, "")
+	}
+
+	action, key, value = parts[1], parts[2], parts[3]
+	switch action {
+	case SettingsActionMenu:
+		return action, "", "", true
+	case SettingsActionOpen:
+		return action, key, "", settings.Get(key) != nil
+	case SettingsActionSet:
+		return action, key, value, settings.Get(key) != nil && value != ""
+	default:
+		return "", "", "", false
+	}
+}
+
+// formatSettingsMenu renders the /settings message: settings outside the registry and the
+// family and project overrides as text, and one button per registry setting showing its current value
+func formatSettingsMenu(p *i18n.Printer, values settings.Values, prefs *store.UserPreferences, overrides []*settings.Override) (render.HTML, [][]telegram.InlineKeyboardButton) {
+	lines := []render.HTML{
+		p.T("settings.paused", formatPauseState(p, prefs)),
+		p.T("settings.quiet_hours", formatQuietHours(p, prefs.QuietHoursStartMinute, prefs.QuietHoursEndMinute, prefs.Location())),
+		p.T("settings.working_hours", formatWorkingHours(p, prefs.WorkingHours, prefs.Location())),
+		p.T("settings.timezone", prefs.Location()),
+		p.T("settings.policies", formatPolicyChain(userChain(prefs))),
+	}
+	msg := p.T("settings.title") + "\n\n" + render.Join(lines, "\n")
+	if len(overrides) > 0 {
+		msg += "\n\n" + formatOverrides(p, overrides)
+	}
+	msg += "\n\n" + p.T("settings.menu_hint")
+
+	rows := make([][]telegram.InlineKeyboardButton, 0, len(settings.All()))
+	for _, s := range settings.All() {
+		value := values[s.Key]
+		data := FormatSettingsCallbackData(SettingsActionOpen, s.Key, "")
+		if s.Type == settings.Bool {
+			// Toggles flip right away
+			data = FormatSettingsCallbackData(SettingsActionSet, s.Key, strconv.FormatBool(value != "true"))
+		}
+		rows = append(rows, []telegram.InlineKeyboardButton{
+			{Text: render.Plain(settingLine(p, s, value)), Data: data},
+		})
+	}
+
+	return msg, rows
+}
+
+// formatSettingEditor renders the view for changing a single setting
+func formatSettingEditor(p *i18n.Printer, s *settings.Setting, value string) (render.HTML, [][]telegram.InlineKeyboardButton) {
+	msg := settingLine(p, s, value)
+	var rows [][]telegram.InlineKeyboardButton
+
+	switch {
+	case s.Type == settings.Enum:
+		msg += "\n\n" + p.T("settings.menu_pick")
+		for _, option := range s.Options {
+			rows = append(rows, []telegram.InlineKeyboardButton{
+				{Text: markSelected(render.Plain(displaySetting(p, s, option)), option == value), Data: FormatSettingsCallbackData(SettingsActionSet, s.Key, option)},
+			})
+		}
+
+	case len(s.Values) > 0:
+		msg += "\n\n" + p.T("settings.menu_pick")
+		var row []telegram.InlineKeyboardButton
+		for _, v := range s.Values {
+			option := strconv.Itoa(v)
+			row = append(row, telegram.InlineKeyboardButton{
+				Text: markSelected(option, option == value),
+				Data: FormatSettingsCallbackData(SettingsActionSet, s.Key, option),
+			})
+		}
+		rows = append(rows, row)
+
+	default:
+		msg += "\n\n" + p.T("settings.menu_range", s.Min, s.Max)
+		current, _ := strconv.Atoi(value)
+		jump := menuViews[s.Key].Jump
+		var row []telegram.InlineKeyboardButton
+		for _, delta := range []int{-jump, -s.Step, s.Step, jump} {
+			target := current + delta
+			if delta == 0 || target < s.Min || target > s.Max {
+				continue
+			}
+			row = append(row, telegram.InlineKeyboardButton{
+				Text: fmt.Sprintf("%+d", delta),
+				Data: FormatSettingsCallbackData(SettingsActionSet, s.Key, strconv.Itoa(target)),
+			})
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+	}
+
+	rows = append(rows, []telegram.InlineKeyboardButton{
+		{Text: render.Plain(p.T("settings.menu_back")), Data: FormatSettingsCallbackData(SettingsActionMenu, "", "")},
+	})
+	return msg, rows
+}
+
+func markSelected(text string, selected bool) string {
+	if selected {
+		return "• " + text + " •"
+	}
+	return text
+}
+
+// HandleSettingsCallback handles the buttons of the /settings menu, editing the message in place
+func HandleSettingsCallback(ctx context.Context, user *models.User, action, key, value string, callback *tba.CallbackQuery, logger *log.Logger) error {
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, callback.From, logger)
+
+	values, err := loadSettingValues(ctx, user.ReviewerLogin, prefs)
+	if err != nil {
+		return sendCallbackError(callback, p.T("settings.failed"))
+	}
+
+	setting := settings.Get(key)
+	answer := ""
+
+	if action == SettingsActionSet {
+		logger.Printf("User %s changed %s to %s from the settings menu", user.ReviewerLogin, key, value)
+
+		checked, err := settings.Check(setting, value, values)
+		if err != nil {
+			return sendCallbackError(callback, p.Err(err))
+		}
+		if err := store.UpdateSetting(ctx, user.ReviewerLogin, key, checked); err != nil {
+			return sendCallbackError(callback, p.T("common.update_failed", err))
+		}
+
+		// Reload, so the view shows what was stored, in the new language if it changed
+		prefs = loadPreferences(ctx, user.ReviewerLogin, logger)
+		p = prefs.Printer(clientLanguage(callback.From))
+		answer = render.Plain(p.T("settings.menu_saved"))
+
+		if values, err = loadSettingValues(ctx, user.ReviewerLogin, prefs); err != nil {
+			return sendCallbackError(callback, p.T("settings.failed"))
+		}
+	}
+
+	var text render.HTML
+	var rows [][]telegram.InlineKeyboardButton
+	if setting == nil || setting.Type == settings.Bool {
+		text, rows = formatSettingsMenu(p, values, prefs, loadOverrides(ctx, user.ReviewerLogin, logger))
+	} else {
+		text, rows = formatSettingEditor(p, setting, values[key])
+	}
+
+	if callback.Message != nil {
+		if err := editKeyboardMessage(callback.Message.Chat.ID, callback.Message.MessageID, text, rows); err != nil {
+			logger.Printf("Failed to refresh settings message: %v", err)
+		}
+	}
+
+	bot, _ := telegram.NewBotClientFromEnv()
+	bot.AnswerCallbackQuery(callback.ID, answer)
+
+	return nil
+}
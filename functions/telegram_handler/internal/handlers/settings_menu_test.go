@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/settings"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+func testUserSettings() *models.UserSettings {
+	return &models.UserSettings{
+		ReviewerLogin:                  "testuser",
+		ResponseDeadlineShiftMinutes:   20,
+		NonWhitelistCancelDelayMinutes: 5,
+		NotifyWhitelistTimeout:         true,
+		NotifyNonWhitelistCancel:       false,
+		SlotShiftThresholdMinutes:      25,
+		SlotShiftDurationMinutes:       15,
+		CleanupDurationsMinutes:        30,
+	}
+}
+
+func TestMenuViews(t *testing.T) {
+	for key, view := range menuViews {
+		t.Run(key, func(t *testing.T) {
+			s := settings.Get(key)
+			require.NotNil(t, s, "menu view of a setting missing from the registry")
+			if view.Jump != 0 {
+				assert.Zero(t, view.Jump%s.Step, "jump must keep values on the step")
+			}
+		})
+	}
+}
+
+func TestMenuSettings_Labels(t *testing.T) {
+	en := i18n.New(i18n.English)
+	for _, s := range settings.All() {
+		t.Run(s.Key, func(t *testing.T) {
+			assert.NotEqual(t, s.Label, string(en.T(s.Label, "")), "label %s is missing from the catalog", s.Label)
+			assert.NotEqual(t, s.Description, string(en.T(s.Description)), "description %s is missing from the catalog", s.Description)
+			if s.Unit != "" {
+				assert.NotEqual(t, "help.arg_"+s.Unit, string(en.T("help.arg_"+s.Unit)))
+			}
+
+			// Telegram limits callback data to 64 bytes
+			longest := FormatSettingsCallbackData(SettingsActionSet, s.Key, "WHITELISTED_ONLY")
+			assert.LessOrEqual(t, len(longest), 64)
+		})
+	}
+}
+
+func TestSettingsCallbackData(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		action string
+		key    string
+		value  string
+		ok     bool
+	}{
+		{"Menu", FormatSettingsCallbackData(SettingsActionMenu, "", ""), SettingsActionMenu, "", "", true},
+		{"Open", FormatSettingsCallbackData(SettingsActionOpen, "lookback", ""), SettingsActionOpen, "lookback", "", true},
+		{"Set", FormatSettingsCallbackData(SettingsActionSet, "lookback", "6"), SettingsActionSet, "lookback", "6", true},
+		{"SetWithoutValue", "SETTINGS:set:lookback", "", "", "", false},
+		{"UnknownKey", "SETTINGS:open:nope", "", "", "", false},
+		{"UnknownAction", "SETTINGS:drop:lookback", "", "", "", false},
+		{"OtherPrefix", "APPROVE:req-1", "", "", "", false},
+		{"SlotData", FormatSlotCallbackData(SlotActionClose, 3, "slot"), "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, key, value, ok := ParseSettingsCallbackData(tt.data)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.action, action)
+				assert.Equal(t, tt.key, key)
+				assert.Equal(t, tt.value, value)
+			}
+		})
+	}
+
+	assert.Equal(t, "SETTINGS:menu", FormatSettingsCallbackData(SettingsActionMenu, "", ""))
+}
+
+func TestFormatSettingsMenu(t *testing.T) {
+	prefs := store.DefaultUserPreferences("testuser")
+	values := store.SettingValues(testUserSettings(), prefs)
+	text, rows := formatSettingsMenu(testPrinter, values, prefs, nil)
+
+	assert.Contains(t, text, "<b>Your Settings</b>")
+	assert.Contains(t, text, "Quiet Hours: Off")
+	assert.Contains(t, text, "Working Hours: Off")
+	assert.Contains(t, text, "Decision Policies: deny_list → working_hours → daily_cap → weekly_cap → min_gap → peers → rules → whitelist")
+	assert.Contains(t, text, "Tap a setting")
+	assert.NotContains(t, text, "Overrides")
+
+	require.Len(t, rows, len(settings.All()))
+	assert.Equal(t, "📅 Response Deadline Shift: 20 minutes", rows[0][0].Text)
+	assert.Equal(t, FormatSettingsCallbackData(SettingsActionOpen, "deadline_shift", ""), rows[0][0].Data)
+
+	// Toggles flip right from the menu
+	assert.Equal(t, "🔔 Notify Whitelist Timeout: Yes", rows[2][0].Text)
+	assert.Equal(t, FormatSettingsCallbackData(SettingsActionSet, "notify_whitelist_timeout", "false"), rows[2][0].Data)
+	assert.Equal(t, FormatSettingsCallbackData(SettingsActionSet, "notify_non_whitelist_cancel", "true"), rows[3][0].Data)
+
+	t.Run("Russian", func(t *testing.T) {
+		_, rows := formatSettingsMenu(i18n.New(i18n.Russian), values, prefs, nil)
+		assert.Equal(t, "📅 Сдвиг срока ответа: 20 минут", rows[0][0].Text)
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		overrides := []*settings.Override{{EntryType: models.EntryTypeFamily, Name: "C - I", Key: settings.DeadlineShift, Value: "45"}}
+		text, _ := formatSettingsMenu(testPrinter, values, prefs, overrides)
+		assert.Contains(t, text, "family C - I: 📅 Response Deadline Shift: 45 minutes")
+	})
+
+	t.Run("TimeoutAction", func(t *testing.T) {
+		overrides := []*settings.Override{{EntryType: models.EntryTypeFamily, Name: "C - I", Key: settings.TimeoutAction, Value: settings.TimeoutApproveWhitelisted}}
+		text, _ := formatSettingsMenu(testPrinter, values, prefs, overrides)
+		assert.Contains(t, text, "family C - I: ⏰ On Timeout: Approve whitelisted")
+		assert.Contains(t, settingLine(testPrinter, settings.Get(settings.TimeoutAction), settings.TimeoutCancel), "On Timeout: Cancel")
+	})
+
+	t.Run("NonWhitelist", func(t *testing.T) {
+		assert.Contains(t, settingLine(testPrinter, settings.Get(settings.NonWhitelistDeadline), "30"), "Non-Whitelisted Answer Time: 30 minutes")
+		assert.Contains(t, settingLine(testPrinter, settings.Get(settings.NonWhitelistAction), settings.NonWhitelistAsk), "Non-Whitelisted Bookings: Ask")
+	})
+
+	t.Run("WorkingHours", func(t *testing.T) {
+		withHours := *prefs
+		withHours.WorkingHours = "Mon-Fri 10:00-22:00"
+		text, _ := formatSettingsMenu(testPrinter, values, &withHours, nil)
+		assert.Contains(t, text, "Working Hours: Mon-Fri 10:00-22:00 Europe/Moscow")
+		assert.Contains(t, settingLine(testPrinter, settings.Get(settings.OutsideHoursAction), settings.OutsideHoursDecline), "Outside Working Hours: Decline")
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		assert.Contains(t, settingLine(testPrinter, settings.Get(settings.DailyCap), "0"), "Daily Review Cap: Off")
+		assert.Contains(t, settingLine(testPrinter, settings.Get(settings.DailyCap), "1"), "Daily Review Cap: 1 review")
+		assert.Contains(t, settingLine(testPrinter, settings.Get(settings.MinGap), "90"), "Minimum Gap Between Reviews: 90 minutes")
+		assert.Contains(t, settingLine(testPrinter, settings.Get(settings.LimitAction), settings.LimitActionDecline), "On Limit: Decline")
+		assert.Contains(t, settingLine(testPrinter, settings.Get(settings.HousekeepingBuffer), "0"), "0 minutes", "0 is a value of settings without Off")
+	})
+
+	t.Run("LanguageNotChosen", func(t *testing.T) {
+		_, rows := formatSettingsMenu(testPrinter, values.With(settings.Language, ""), prefs, nil)
+		assert.Contains(t, rows[len(rows)-1][0].Text, "English")
+	})
+}
+
+func TestFormatSettingEditor(t *testing.T) {
+	back := FormatSettingsCallbackData(SettingsActionMenu, "", "")
+
+	t.Run("NumberAtMin", func(t *testing.T) {
+		text, rows := formatSettingEditor(testPrinter, settings.Get("deadline_shift"), "20")
+		assert.Contains(t, text, "Allowed: 20 - 60")
+
+		require.Len(t, rows, 2)
+		require.Len(t, rows[0], 2, "no buttons below the minimum")
+		assert.Equal(t, "+1", rows[0][0].Text)
+		assert.Equal(t, FormatSettingsCallbackData(SettingsActionSet, "deadline_shift", "21"), rows[0][0].Data)
+		assert.Equal(t, "+5", rows[0][1].Text)
+		assert.Equal(t, FormatSettingsCallbackData(SettingsActionSet, "deadline_shift", "25"), rows[0][1].Data)
+		assert.Equal(t, back, rows[1][0].Data)
+	})
+
+	t.Run("NumberInMiddle", func(t *testing.T) {
+		_, rows := formatSettingEditor(testPrinter, settings.Get("lookback"), "12")
+		require.Len(t, rows[0], 4)
+		assert.Equal(t, "-6", rows[0][0].Text)
+		assert.Equal(t, "-1", rows[0][1].Text)
+		assert.Equal(t, "+1", rows[0][2].Text)
+		assert.Equal(t, "+6", rows[0][3].Text)
+	})
+
+	t.Run("NumberWithoutJump", func(t *testing.T) {
+		_, rows := formatSettingEditor(testPrinter, settings.Get("slot_shift_duration"), "30")
+		require.Len(t, rows[0], 2)
+		assert.Equal(t, "-15", rows[0][0].Text)
+		assert.Equal(t, "+15", rows[0][1].Text)
+	})
+
+	t.Run("Values", func(t *testing.T) {
+		_, rows := formatSettingEditor(testPrinter, settings.Get("cleanup_duration"), "30")
+		require.Len(t, rows[0], 4)
+		assert.Equal(t, "15", rows[0][0].Text)
+		assert.Equal(t, "• 30 •", rows[0][1].Text)
+	})
+
+	t.Run("Choice", func(t *testing.T) {
+		text, rows := formatSettingEditor(testPrinter, settings.Get("pause_policy"), store.PausePolicyQueue)
+		assert.Contains(t, text, "Pick a value")
+
+		require.Len(t, rows, 4)
+		assert.Equal(t, render.Plain(testPrinter.T("pause_policy.decline")), rows[0][0].Text)
+		assert.Equal(t, FormatSettingsCallbackData(SettingsActionSet, "pause_policy", store.PausePolicyDecline), rows[0][0].Data)
+		assert.Equal(t, "• queue them until you resume •", rows[2][0].Text)
+	})
+
+	t.Run("LanguageShowsOwnNames", func(t *testing.T) {
+		_, rows := formatSettingEditor(i18n.New(i18n.Russian), settings.Get("language"), i18n.Russian)
+		assert.Equal(t, "English", rows[0][0].Text)
+		assert.Equal(t, "• Русский •", rows[1][0].Text)
+		assert.Equal(t, "« Назад", rows[2][0].Text)
+	})
+}
+
+func TestFormatSettingsHelp(t *testing.T) {
+	help := string(formatSettingsHelp(testPrinter))
+	lines := strings.Split(help, "\n")
+	require.Len(t, lines, len(settings.All()))
+
+	assert.Contains(t, lines, "/set_deadline_shift &lt;minutes&gt; - Response deadline shift (20-60)")
+	assert.Contains(t, lines, "/set_slot_shift_duration &lt;minutes&gt; - Slot shift duration (15-60, step 15)")
+	assert.Contains(t, lines, "/set_cleanup_duration &lt;minutes&gt; - Cleanup duration (15, 30, 45, 60)")
+	assert.Contains(t, lines, "/set_notify_whitelist_timeout &lt;on|off&gt; - Notify on whitelist timeout")
+	assert.Contains(t, lines, "/set_pause_policy &lt;decline|whitelisted|queue&gt; - What happens to new bookings while paused")
+	assert.Contains(t, lines, "/set_daily_cap &lt;reviews&gt; - Most reviews per day, 0 for no cap (0-20)")
+	assert.Contains(t, lines, "/set_limit_action &lt;ask|decline&gt; - What happens to bookings that break a limit")
+	assert.Contains(t, lines, "/set_outside_hours_action &lt;ask|decline&gt; - What happens to bookings outside your working hours")
+	assert.Contains(t, lines, "/language &lt;en|ru&gt; - Change the bot language")
+
+	t.Run("Russian", func(t *testing.T) {
+		help := string(formatSettingsHelp(i18n.New(i18n.Russian)))
+		assert.Contains(t, help, "/set_lookback &lt;часы&gt; - Насколько назад учитывать новые бронирования (0-24)")
+		assert.Contains(t, help, "(0-60, шаг 5)")
+	})
+}
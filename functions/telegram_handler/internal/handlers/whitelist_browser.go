@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/telegram"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/projects"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
+)
+
+// Whitelist browser page sizes and the number of "did you mean" suggestions
+const (
+	whitelistPageSize    = 8
+	whitelistSuggestions = 3
+)
+
+// whitelistSet holds a user's whitelist entries for lookups
+type whitelistSet map[string]bool
+
+func newWhitelistSet(entries []*models.WhitelistEntry) whitelistSet {
+	set := make(whitelistSet, len(entries))
+	for _, entry := range entries {
+		set[entry.EntryType+":"+entry.Name] = true
+	}
+	return set
+}
+
+func (s whitelistSet) has(entryType, name string) bool {
+	return s[entryType+":"+name]
+}
+
+// pageBounds returns the clamped page and the range of items shown on it
+func pageBounds(total, page int) (int, int, int) {
+	pages := (total + whitelistPageSize - 1) / whitelistPageSize
+	if page >= pages {
+		page = pages - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+	start := page * whitelistPageSize
+	return page, start, min(start+whitelistPageSize, total)
+}
+
+// pageRow returns the ‹ › buttons for a paged list, or nil if everything fits on one page
+func pageRow(total, page int, data func(page int) string) []telegram.InlineKeyboardButton {
+	pages := (total + whitelistPageSize - 1) / whitelistPageSize
+	if pages <= 1 {
+		return nil
+	}
+
+	var row []telegram.InlineKeyboardButton
+	if page > 0 {
+		row = append(row, telegram.InlineKeyboardButton{Text: "‹", Data: data(page - 1)})
+	}
+	row = append(row, telegram.InlineKeyboardButton{Text: fmt.Sprintf("%d/%d", page+1, pages), Data: data(page)})
+	if page < pages-1 {
+		row = append(row, telegram.InlineKeyboardButton{Text: "›", Data: data(page + 1)})
+	}
+	return row
+}
+
+// listKey returns the catalog key of the list an entry type belongs to, e.g. "blacklist.added"
+func listKey(entryType, key string) string {
+	if whitelist.IsBlacklisted(entryType) {
+		return "blacklist." + key
+	}
+	return "whitelist." + key
+}
+
+func markWhitelisted(name string, whitelisted bool) string {
+	if whitelisted {
+		return "✅ " + name
+	}
+	return "▫️ " + name
+}
+
+// formatWhitelistFamilies renders the first view of the browser: one button per family
+func formatWhitelistFamilies(p *i18n.Printer, catalog *projects.Catalog, wl whitelistSet, page int) (render.HTML, [][]telegram.InlineKeyboardButton) {
+	page, start, end := pageBounds(len(catalog.Families), page)
+
+	var rows [][]telegram.InlineKeyboardButton
+	for i := start; i < end; i++ {
+		family := catalog.Families[i]
+		label := markWhitelisted(family.Label, wl.has(models.EntryTypeFamily, family.Label))
+		rows = append(rows, []telegram.InlineKeyboardButton{
+			{Text: fmt.Sprintf("%s (%d)", label, len(family.Projects)), Data: whitelist.FormatCallbackData(whitelist.Callback{Action: whitelist.ActionFamily, Family: projects.ID(family.Label)})},
+		})
+	}
+	if row := pageRow(len(catalog.Families), page, func(page int) string {
+		return whitelist.FormatCallbackData(whitelist.Callback{Action: whitelist.ActionFamilies, Page: page})
+	}); row != nil {
+		rows = append(rows, row)
+	}
+
+	return p.T("whitelist.browser_title"), rows
+}
+
+// formatWhitelistFamily renders a family: a button for the whole family and one per project
+func formatWhitelistFamily(p *i18n.Printer, catalog *projects.Catalog, index int, wl whitelistSet, page int) (render.HTML, [][]telegram.InlineKeyboardButton) {
+	family := catalog.Family(index)
+	page, start, end := pageBounds(len(family.Projects), page)
+
+	familyID := projects.ID(family.Label)
+	familyWhitelisted := wl.has(models.EntryTypeFamily, family.Label)
+	msg := p.T("whitelist.browser_family", family.Label, p.N("unit.projects", len(family.Projects)))
+	if familyWhitelisted {
+		msg += "\n\n" + p.T("whitelist.browser_family_whitelisted")
+	}
+
+	rows := [][]telegram.InlineKeyboardButton{{
+		{
+			Text: markWhitelisted(render.Plain(p.T("whitelist.browser_whole_family")), familyWhitelisted),
+			Data: whitelist.FormatCallbackData(whitelist.Callback{Action: whitelist.ActionToggleFamily, Family: familyID, Page: page}),
+		},
+	}}
+	for j := start; j < end; j++ {
+		project := family.Projects[j]
+		data := whitelist.FormatCallbackData(whitelist.Callback{Action: whitelist.ActionToggleProject, Family: familyID, Project: projects.ID(project), Page: page})
+		rows = append(rows, []telegram.InlineKeyboardButton{
+			{Text: markWhitelisted(project, wl.has(models.EntryTypeProject, project)), Data: data},
+		})
+	}
+	if row := pageRow(len(family.Projects), page, func(page int) string {
+		return whitelist.FormatCallbackData(whitelist.Callback{Action: whitelist.ActionFamily, Family: familyID, Page: page})
+	}); row != nil {
+		rows = append(rows, row)
+	}
+
+	rows = append(rows, []telegram.InlineKeyboardButton{
+		{Text: render.Plain(p.T("whitelist.browser_back")), Data: whitelist.FormatCallbackData(whitelist.Callback{Action: whitelist.ActionFamilies, Page: index / whitelistPageSize})},
+	})
+	return msg, rows
+}
+
+// formatWhitelistSuggestions renders the reply to an unknown name: the closest known names
+// as buttons that add them to the list of entryType
+func formatWhitelistSuggestions(p *i18n.Printer, catalog *projects.Catalog, entryType, name string, expiresAt *time.Time) (render.HTML, [][]telegram.InlineKeyboardButton) {
+	baseType := whitelist.BaseType(entryType)
+	key := "whitelist.unknown_project"
+	if baseType == models.EntryTypeFamily {
+		key = "whitelist.unknown_family"
+	}
+	msg := p.T(key, name)
+
+	suggestions := catalog.Suggest(baseType, name, whitelistSuggestions)
+	if len(suggestions) == 0 {
+		return msg + "\n\n" + p.T(listKey(entryType, "no_suggestions")), nil
+	}
+
+	addFamily, addProject := whitelist.ActionAddFamily, whitelist.ActionAddProject
+	if whitelist.IsBlacklisted(entryType) {
+		addFamily, addProject = whitelist.ActionBlacklistFamily, whitelist.ActionBlacklistProject
+	}
+
+	var expiry int64
+	if expiresAt != nil {
+		expiry = expiresAt.Unix()
+	}
+
+	var rows [][]telegram.InlineKeyboardButton
+	for _, pos := range suggestions {
+		callback := whitelist.Callback{Action: addProject, ExpiresAt: expiry}
+		if pos.Project < 0 {
+			callback.Action = addFamily
+		}
+		callback.Family, callback.Project = catalog.IDs(pos)
+		rows = append(rows, []telegram.InlineKeyboardButton{{Text: catalog.Name(pos), Data: whitelist.FormatCallbackData(callback)}})
+	}
+	return msg + "\n\n" + p.T(listKey(entryType, "did_you_mean")), rows
+}
+
+// sendWhitelistBrowser sends the first view of the whitelist browser
+func sendWhitelistBrowser(ctx context.Context, chatID int64, reviewerLogin string, p *i18n.Printer, logger *log.Logger) error {
+	catalog, err := projects.Load(ctx)
+	if err != nil {
+		logger.Printf("Failed to load project families: %v", err)
+		sendMessage(chatID, p.T("whitelist.browser_failed"))
+		return nil
+	}
+	if catalog.Empty() {
+		sendMessage(chatID, p.T("whitelist.browser_empty"))
+		return nil
+	}
+
+	entries, _, err := whitelist.Entries(ctx, reviewerLogin, time.Now())
+	if err != nil {
+		sendMessage(chatID, p.T("whitelist.failed"))
+		return nil
+	}
+
+	text, rows := formatWhitelistFamilies(p, catalog, newWhitelistSet(entries), 0)
+	if _, err := sendKeyboardMessage(chatID, text, rows); err != nil {
+		logger.Printf("Failed to send whitelist browser: %v", err)
+	}
+	return nil
+}
+
+// HandleWhitelistCallback handles the buttons of the whitelist browser, of "did you mean"
+// suggestions and of the suggestions learned from approval history, editing the message in place.
+// Buttons whose family or project is gone from the catalog are refused as outdated
+func HandleWhitelistCallback(ctx context.Context, user *models.User, data whitelist.Callback, callback *tba.CallbackQuery, logger *log.Logger) error {
+	action, page := data.Action, data.Page
+
+	prefs := loadPreferences(ctx, user.ReviewerLogin, logger)
+	p := userPrinter(ctx, prefs, callback.From, logger)
+
+	catalog, err := projects.Load(ctx)
+	if err != nil {
+		logger.Printf("Failed to load project families: %v", err)
+		return sendCallbackError(callback, p.T("whitelist.browser_failed"))
+	}
+
+	entryType := models.EntryTypeProject
+	switch action {
+	case whitelist.ActionFamily, whitelist.ActionToggleFamily, whitelist.ActionAddFamily, whitelist.ActionDismissFamily:
+		entryType = models.EntryTypeFamily
+	case whitelist.ActionBlacklistFamily:
+		entryType = whitelist.BlacklistType(models.EntryTypeFamily)
+	case whitelist.ActionBlacklistProject:
+		entryType = whitelist.BlacklistType(models.EntryTypeProject)
+	}
+
+	var pos projects.Position
+	if action != whitelist.ActionFamilies {
+		var ok bool
+		pos, ok = catalog.Lookup(data.Family, data.Project)
+		// A project button without a project would otherwise resolve to the family
+		if !ok || (whitelist.BaseType(entryType) == models.EntryTypeFamily) != (pos.Project < 0) {
+			return sendCallbackError(callback, p.T("whitelist.browser_outdated"))
+		}
+	}
+
+	entries, _, err := whitelist.Entries(ctx, user.ReviewerLogin, time.Now())
+	if err != nil {
+		return sendCallbackError(callback, p.T("whitelist.failed"))
+	}
+	wl := newWhitelistSet(entries)
+
+	toggle := action == whitelist.ActionToggleFamily || action == whitelist.ActionToggleProject
+	var expiresAt *time.Time
+	if !toggle && data.ExpiresAt > 0 {
+		t := time.Unix(data.ExpiresAt, 0)
+		if !t.After(time.Now()) {
+			return sendCallbackError(callback, p.T("whitelist.expiry_in_past"))
+		}
+		expiresAt = &t
+	}
+
+	answer := ""
+	var text render.HTML
+	var rows [][]telegram.InlineKeyboardButton
+
+	switch action {
+	case whitelist.ActionFamilies:
+		text, rows = formatWhitelistFamilies(p, catalog, wl, page)
+
+	case whitelist.ActionFamily:
+		text, rows = formatWhitelistFamily(p, catalog, pos.Family, wl, page)
+
+	case whitelist.ActionDismissFamily, whitelist.ActionDismissProject:
+		name := catalog.Name(pos)
+		logger.Printf("User %s dismissed the suggestion about %s", user.ReviewerLogin, name)
+		if err := store.DismissSuggestion(ctx, user.ReviewerLogin, entryType, name); err != nil {
+			return sendCallbackError(callback, p.T("suggest.dismiss_failed", err))
+		}
+		text = p.T("suggest.dismissed", name)
+
+	default:
+		name := catalog.Name(pos)
+		remove := wl.has(entryType, name) && toggle
+
+		if remove {
+			logger.Printf("User %s removed %s from the whitelist browser", user.ReviewerLogin, name)
+			if err := store.RemoveWhitelistEntry(ctx, user.ReviewerLogin, entryType, name); err != nil {
+				return sendCallbackError(callback, p.T("whitelist.remove_failed", err))
+			}
+			delete(wl, entryType+":"+name)
+			answer = render.Plain(p.T("whitelist.removed", name))
+		} else {
+			logger.Printf("User %s added %s %s from the whitelist browser", user.ReviewerLogin, entryType, name)
+			entry := &models.WhitelistEntry{ReviewerLogin: user.ReviewerLogin, EntryType: entryType, Name: name}
+			if err := whitelist.Add(ctx, entry, expiresAt); err != nil {
+				return sendCallbackError(callback, p.T(listKey(entryType, "add_failed"), err))
+			}
+			wl[entryType+":"+name] = true
+			answer = render.Plain(p.T(listKey(entryType, "added"), name))
+		}
+
+		if !toggle {
+			// A suggestion was picked, the question is answered
+			entry := &models.WhitelistEntry{EntryType: entryType, Name: name}
+			text = formatEntryAdded(p, entry, expiresAt, prefs.Location())
+		} else {
+			text, rows = formatWhitelistFamily(p, catalog, pos.Family, wl, page)
+		}
+	}
+
+	if callback.Message != nil {
+		if err := editKeyboardMessage(callback.Message.Chat.ID, callback.Message.MessageID, text, rows); err != nil {
+			logger.Printf("Failed to refresh whitelist browser: %v", err)
+		}
+	}
+
+	bot, _ := telegram.NewBotClientFromEnv()
+	bot.AnswerCallbackQuery(callback.ID, answer)
+
+	return nil
+}
+
+// addWhitelistPattern validates and stores a glob or regex entry of either list, and tells
+// how many known projects it matches
+func addWhitelistPattern(ctx context.Context, chatID int64, reviewerLogin, entryType, pattern string, expiresAt *time.Time, p *i18n.Printer, loc *time.Location, logger *log.Logger) error {
+	if err := whitelist.Validate(entryType, pattern); err != nil {
+		sendMessage(chatID, p.Err(err))
+		return nil
+	}
+
+	entry := &models.WhitelistEntry{
+		ReviewerLogin: reviewerLogin,
+		EntryType:     entryType,
+		Name:          pattern,
+	}
+	if err := whitelist.Add(ctx, entry, expiresAt); err != nil {
+		sendMessage(chatID, p.T(listKey(entryType, "add_failed"), err))
+		return nil
+	}
+
+	msg := p.T(listKey(entryType, "pattern_added"), pattern)
+	if expiresAt != nil {
+		msg += " " + p.T("whitelist.expires", p.FormatShort(*expiresAt, loc))
+	}
+	catalog, err := projects.Load(ctx)
+	if err != nil {
+		logger.Printf("Failed to load project families: %v", err)
+	} else if !catalog.Empty() {
+		msg += "\n\n" + formatPatternMatches(p, catalog, entry)
+	}
+
+	sendMessage(chatID, msg)
+	return nil
+}
+
+// formatEntryAdded confirms an added family or project entry, with its expiry if temporary
+func formatEntryAdded(p *i18n.Printer, entry *models.WhitelistEntry, expiresAt *time.Time, loc *time.Location) render.HTML {
+	msg := p.T(listKey(entry.EntryType, "added"), entry.Name)
+	if expiresAt != nil {
+		msg += " " + p.T("whitelist.expires", p.FormatShort(*expiresAt, loc))
+	}
+	return msg
+}
+
+// formatPatternMatches tells how many known projects a pattern entry accepts, with a few examples
+func formatPatternMatches(p *i18n.Printer, catalog *projects.Catalog, entry *models.WhitelistEntry) render.HTML {
+	entries := []*models.WhitelistEntry{entry}
+	var matched []string
+	for _, family := range catalog.Families {
+		for _, project := range family.Projects {
+			if whitelist.Find(entries, project, family.Label) != nil {
+				matched = append(matched, project)
+			}
+		}
+	}
+
+	if len(matched) == 0 {
+		return p.T("whitelist.pattern_matches_none")
+	}
+	examples := matched[:min(len(matched), whitelistSuggestions)]
+	return p.T("whitelist.pattern_matches", p.N("unit.projects", len(matched)), strings.Join(examples, ", "))
+}
+
+// formatWhitelistTest renders the /whitelist_test answer
+func formatWhitelistTest(p *i18n.Printer, projectName, familyLabel string, match *whitelist.Match) render.HTML {
+	var msg render.HTML
+	if match == nil {
+		msg = p.T("whitelist.test_no_match", projectName)
+	} else {
+		matchedBy := p.T("whitelist.test_by_project", projectName)
+		if match.ByFamily {
+			matchedBy = p.T("whitelist.test_by_family", familyLabel)
+		}
+		key := "whitelist.test_match"
+		if match.Blacklisted {
+			key = "whitelist.test_blacklisted"
+		}
+		msg = p.T(key, projectName, whitelist.Describe(p, match.Entry), matchedBy)
+		if match.Preset != "" {
+			msg += "\n" + p.T("whitelist.test_preset", match.Preset)
+		}
+	}
+
+	if familyLabel == "" {
+		msg += "\n\n" + p.T("whitelist.test_unknown_project")
+	} else {
+		msg += "\n\n" + p.T("whitelist.test_family", familyLabel)
+	}
+	return msg
+}
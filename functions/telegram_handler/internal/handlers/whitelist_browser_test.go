@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/projects"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
+)
+
+// callbackData returns the callback data of a whitelist button about a family or project
+func callbackData(action, family, project string, page int, expiresAt int64) string {
+	c := whitelist.Callback{Action: action, Family: projects.ID(family), Page: page, ExpiresAt: expiresAt}
+	if family == "" {
+		c.Family = ""
+	}
+	if project != "" {
+		c.Project = projects.ID(project)
+	}
+	return whitelist.FormatCallbackData(c)
+}
+
+func testProjectCatalog() *projects.Catalog {
+	rows := []*models.ProjectFamily{
+		{FamilyLabel: "Go", ProjectName: "go-concurrency"},
+		{FamilyLabel: "Go", ProjectName: "go-boilerplate"},
+	}
+	// Enough families and projects for two pages each
+	for i := 0; i < 10; i++ {
+		rows = append(rows, &models.ProjectFamily{FamilyLabel: fmt.Sprintf("Family %02d", i), ProjectName: fmt.Sprintf("project-%02d", i)})
+		rows = append(rows, &models.ProjectFamily{FamilyLabel: "C - I", ProjectName: fmt.Sprintf("C%d_project", i)})
+	}
+	return projects.New(rows)
+}
+
+func TestFormatWhitelistFamilies(t *testing.T) {
+	catalog := testProjectCatalog()
+	wl := newWhitelistSet([]*models.WhitelistEntry{{EntryType: models.EntryTypeFamily, Name: "Go"}})
+
+	text, rows := formatWhitelistFamilies(testPrinter, catalog, wl, 0)
+	assert.Contains(t, text, "<b>Whitelist</b>")
+
+	require.Len(t, rows, whitelistPageSize+1)
+	assert.Equal(t, "▫️ C - I (10)", rows[0][0].Text)
+	assert.Equal(t, callbackData(whitelist.ActionFamily, "C - I", "", 0, 0), rows[0][0].Data)
+
+	paging := rows[whitelistPageSize]
+	require.Len(t, paging, 2, "no previous button on the first page")
+	assert.Equal(t, "1/2", paging[0].Text)
+	assert.Equal(t, callbackData(whitelist.ActionFamilies, "", "", 1, 0), paging[1].Data)
+
+	t.Run("LastPage", func(t *testing.T) {
+		_, rows := formatWhitelistFamilies(testPrinter, catalog, wl, 5)
+		require.Len(t, rows, 12-whitelistPageSize+1, "page is clamped to the last one")
+		assert.Equal(t, "✅ Go (2)", rows[len(rows)-2][0].Text)
+		assert.Equal(t, "‹", rows[len(rows)-1][0].Text)
+	})
+}
+
+func TestFormatWhitelistFamily(t *testing.T) {
+	catalog := testProjectCatalog()
+	wl := newWhitelistSet([]*models.WhitelistEntry{{EntryType: models.EntryTypeProject, Name: "go-concurrency"}})
+
+	t.Run("SinglePage", func(t *testing.T) {
+		goIndex := len(catalog.Families) - 1
+		text, rows := formatWhitelistFamily(testPrinter, catalog, goIndex, wl, 0)
+		assert.Contains(t, text, "<b>Go</b>")
+		assert.Contains(t, text, "2 projects")
+		assert.NotContains(t, text, "whole family is whitelisted")
+
+		require.Len(t, rows, 4)
+		assert.Equal(t, "▫️ Whole family", rows[0][0].Text)
+		assert.Equal(t, callbackData(whitelist.ActionToggleFamily, "Go", "", 0, 0), rows[0][0].Data)
+		assert.Equal(t, "▫️ go-boilerplate", rows[1][0].Text)
+		assert.Equal(t, "✅ go-concurrency", rows[2][0].Text)
+		assert.Equal(t, callbackData(whitelist.ActionToggleProject, "Go", "go-concurrency", 0, 0), rows[2][0].Data)
+		assert.Equal(t, "« Families", rows[3][0].Text)
+		assert.Equal(t, callbackData(whitelist.ActionFamilies, "", "", 1, 0), rows[3][0].Data, "back to the page with the family")
+	})
+
+	t.Run("SecondPage", func(t *testing.T) {
+		_, rows := formatWhitelistFamily(testPrinter, catalog, 0, wl, 1)
+		// whole family, 2 projects, paging, back
+		require.Len(t, rows, 5)
+		assert.Equal(t, "▫️ C8_project", rows[1][0].Text)
+		assert.Equal(t, callbackData(whitelist.ActionToggleProject, "C - I", "C8_project", 1, 0), rows[1][0].Data)
+		assert.Equal(t, callbackData(whitelist.ActionToggleFamily, "C - I", "", 1, 0), rows[0][0].Data, "toggling keeps the page")
+	})
+
+	t.Run("FamilyWhitelisted", func(t *testing.T) {
+		wl := newWhitelistSet([]*models.WhitelistEntry{{EntryType: models.EntryTypeFamily, Name: "C - I"}})
+		text, rows := formatWhitelistFamily(testPrinter, catalog, 0, wl, 0)
+		assert.Contains(t, text, "whole family is whitelisted")
+		assert.Equal(t, "✅ Whole family", rows[0][0].Text)
+	})
+
+	t.Run("ButtonsSurviveNewProjects", func(t *testing.T) {
+		goIndex := len(catalog.Families) - 1
+		_, rows := formatWhitelistFamily(testPrinter, catalog, goIndex, wl, 0)
+		data, ok := whitelist.ParseCallbackData(rows[2][0].Data)
+		require.True(t, ok)
+
+		// A family and a project sorted before the button's are added meanwhile
+		grown := projects.New([]*models.ProjectFamily{
+			{FamilyLabel: "Go", ProjectName: "go-api"},
+			{FamilyLabel: "Algorithms", ProjectName: "A1_Maze"},
+			{FamilyLabel: "Go", ProjectName: "go-concurrency"},
+			{FamilyLabel: "Go", ProjectName: "go-boilerplate"},
+		})
+		pos, ok := grown.Lookup(data.Family, data.Project)
+		require.True(t, ok)
+		assert.Equal(t, "go-concurrency", grown.Name(pos))
+	})
+}
+
+func TestFormatWhitelistSuggestions(t *testing.T) {
+	catalog := testProjectCatalog()
+
+	t.Run("Project", func(t *testing.T) {
+		text, rows := formatWhitelistSuggestions(testPrinter, catalog, models.EntryTypeProject, "go-concurency", nil)
+		assert.Contains(t, text, "There is no project called go-concurency")
+		assert.Contains(t, text, "Did you mean")
+
+		require.Len(t, rows, 1)
+		assert.Equal(t, "go-concurrency", rows[0][0].Text)
+		assert.Equal(t, callbackData(whitelist.ActionAddProject, "Go", "go-concurrency", 0, 0), rows[0][0].Data)
+	})
+
+	t.Run("Family", func(t *testing.T) {
+		text, rows := formatWhitelistSuggestions(testPrinter, catalog, models.EntryTypeFamily, "<go>", nil)
+		assert.Contains(t, text, "There is no family called &lt;go&gt;")
+		require.NotEmpty(t, rows)
+		assert.Equal(t, "Go", rows[0][0].Text)
+		assert.Equal(t, callbackData(whitelist.ActionAddFamily, "Go", "", 0, 0), rows[0][0].Data)
+	})
+
+	t.Run("NothingClose", func(t *testing.T) {
+		text, rows := formatWhitelistSuggestions(testPrinter, catalog, models.EntryTypeProject, "kubernetes", nil)
+		assert.Contains(t, text, "without arguments")
+		assert.Empty(t, rows)
+	})
+
+	t.Run("Temporary", func(t *testing.T) {
+		expiresAt := time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC)
+		_, rows := formatWhitelistSuggestions(testPrinter, catalog, models.EntryTypeProject, "go-concurency", &expiresAt)
+		require.Len(t, rows, 1)
+		assert.Equal(t, callbackData(whitelist.ActionAddProject, "Go", "go-concurrency", 0, expiresAt.Unix()), rows[0][0].Data)
+	})
+
+	t.Run("Blacklist", func(t *testing.T) {
+		text, rows := formatWhitelistSuggestions(testPrinter, catalog, whitelist.BlacklistType(models.EntryTypeFamily), "go", nil)
+		assert.Contains(t, text, "There is no family called go")
+		assert.Contains(t, text, "add it to your blacklist")
+		require.NotEmpty(t, rows)
+		assert.Equal(t, callbackData(whitelist.ActionBlacklistFamily, "Go", "", 0, 0), rows[0][0].Data)
+	})
+}
+
+func TestFormatPatternMatches(t *testing.T) {
+	catalog := testProjectCatalog()
+
+	t.Run("Some", func(t *testing.T) {
+		entry := &models.WhitelistEntry{EntryType: whitelist.EntryTypeRegex, Name: "^go-"}
+		assert.Equal(t, "It matches 2 projects right now, e.g. go-boilerplate, go-concurrency.", string(formatPatternMatches(testPrinter, catalog, entry)))
+	})
+
+	t.Run("ByFamily", func(t *testing.T) {
+		entry := &models.WhitelistEntry{EntryType: whitelist.EntryTypeGlob, Name: "c - *"}
+		assert.Contains(t, formatPatternMatches(testPrinter, catalog, entry), "10 projects")
+	})
+
+	t.Run("None", func(t *testing.T) {
+		entry := &models.WhitelistEntry{EntryType: whitelist.EntryTypeGlob, Name: "CPP*"}
+		assert.Contains(t, formatPatternMatches(testPrinter, catalog, entry), "does not match")
+	})
+}
+
+func TestFormatWhitelistTest(t *testing.T) {
+	t.Run("MatchedByPattern", func(t *testing.T) {
+		match := &whitelist.Match{Entry: &models.WhitelistEntry{EntryType: whitelist.EntryTypeGlob, Name: "go-*"}}
+		msg := string(formatWhitelistTest(testPrinter, "go-concurrency", "Go", match))
+		assert.Contains(t, msg, "✅ go-concurrency is whitelisted.")
+		assert.Contains(t, msg, "Entry: glob pattern go-*")
+		assert.Contains(t, msg, "Matched: project name go-concurrency")
+		assert.Contains(t, msg, "Family: Go")
+	})
+
+	t.Run("MatchedByFamily", func(t *testing.T) {
+		match := &whitelist.Match{Entry: &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "C - I"}, ByFamily: true}
+		msg := string(formatWhitelistTest(testPrinter, "C3_SimpleBashUtils", "C - I", match))
+		assert.Contains(t, msg, "Entry: family C - I")
+		assert.Contains(t, msg, "Matched: family label C - I")
+	})
+
+	t.Run("FromPreset", func(t *testing.T) {
+		match := &whitelist.Match{Entry: &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "Go"}, ByFamily: true, Preset: "go-team"}
+		msg := string(formatWhitelistTest(testPrinter, "go-concurrency", "Go", match))
+		assert.Contains(t, msg, "Entry: family Go")
+		assert.Contains(t, msg, "Preset: go-team")
+	})
+
+	t.Run("Blacklisted", func(t *testing.T) {
+		match := &whitelist.Match{Entry: &models.WhitelistEntry{EntryType: whitelist.BlacklistType(models.EntryTypeProject), Name: "DO1_Linux"}, Blacklisted: true}
+		msg := string(formatWhitelistTest(testPrinter, "DO1_Linux", "DevOps", match))
+		assert.Contains(t, msg, "🚫 DO1_Linux is blacklisted")
+		assert.Contains(t, msg, "Entry: project DO1_Linux")
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		msg := string(formatWhitelistTest(testPrinter, "<b>x</b>", "", nil))
+		assert.Contains(t, msg, "❌ &lt;b&gt;x&lt;/b&gt; is not whitelisted")
+		assert.Contains(t, msg, "family is unknown")
+	})
+}
+
+func TestListKey(t *testing.T) {
+	assert.Equal(t, "whitelist.added", listKey(whitelist.EntryTypeGlob, "added"))
+	assert.Equal(t, "blacklist.added", listKey(whitelist.BlacklistType(whitelist.EntryTypeGlob), "added"))
+}
+
+func TestFormatEntryAdded(t *testing.T) {
+	entry := &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "C - I"}
+	assert.Equal(t, "✅ Added C - I to your whitelist.", string(formatEntryAdded(testPrinter, entry, nil, time.UTC)))
+
+	expiresAt := time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC)
+	entry = &models.WhitelistEntry{EntryType: whitelist.BlacklistType(models.EntryTypeFamily), Name: "C - I"}
+	msg := string(formatEntryAdded(testPrinter, entry, &expiresAt, time.UTC))
+	assert.Contains(t, msg, "Added C - I to your blacklist")
+	assert.Contains(t, msg, "It expires on Jan 20 00:00 UTC.")
+}
@@ -12,6 +12,10 @@ import (
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/telegram"
 	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
 	"github.com/arseniisemenow/review-slot-guard-bot/functions/telegram_handler/internal/handlers"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
 )
 
 // init initializes the database schema
@@ -24,6 +28,9 @@ func init() {
 		log.Printf("WARNING: Failed to initialize database schema: %v", err)
 		// Don't panic - tables may already exist
 	}
+	if err := store.InitSchema(ctx); err != nil {
+		log.Printf("WARNING: Failed to initialize store schema: %v", err)
+	}
 }
 
 // main function for local testing
@@ -85,20 +92,41 @@ func handleCallbackQuery(ctx context.Context, callback *tba.CallbackQuery, logge
 		return err
 	}
 
+	p := i18n.New(i18n.Resolve("", callback.From.LanguageCode))
+
 	// Get user by telegram_chat_id
 	user, err := ydb.GetUserByTelegramChatID(ctx, callback.From.ID)
 	if err != nil {
 		logger.Printf("User not found for telegram_chat_id %d: %v", callback.From.ID, err)
 		// Answer the callback anyway
-		bot.AnswerCallbackQuery(callback.ID, "User not found. Please use /start to authenticate.")
+		bot.AnswerCallbackQuery(callback.ID, render.Plain(p.T("common.user_not_found")))
 		return nil
 	}
 
+	if prefs, err := store.GetUserPreferences(ctx, user.ReviewerLogin); err == nil {
+		p = prefs.Printer(callback.From.LanguageCode)
+	}
+
+	// Buttons of the /settings menu
+	if action, key, value, ok := handlers.ParseSettingsCallbackData(callback.Data); ok {
+		return handlers.HandleSettingsCallback(ctx, user, action, key, value, callback, logger)
+	}
+
+	// Buttons of the whitelist browser and "did you mean" suggestions
+	if data, ok := whitelist.ParseCallbackData(callback.Data); ok {
+		return handlers.HandleWhitelistCallback(ctx, user, data, callback, logger)
+	}
+
+	// Slot buttons from the /slots listing
+	if action, days, slotID, ok := handlers.ParseSlotCallbackData(callback.Data); ok {
+		return handlers.HandleSlotCallback(ctx, user, action, days, slotID, callback, logger)
+	}
+
 	// Parse callback data
 	action, reviewRequestID, err := telegram.ParseCallbackData(callback.Data)
 	if err != nil {
 		logger.Printf("Failed to parse callback data %s: %v", callback.Data, err)
-		bot.AnswerCallbackQuery(callback.ID, "Invalid callback data")
+		bot.AnswerCallbackQuery(callback.ID, render.Plain(p.T("callback.invalid")))
 		return nil
 	}
 
@@ -106,14 +134,14 @@ func handleCallbackQuery(ctx context.Context, callback *tba.CallbackQuery, logge
 	req, err := ydb.GetReviewRequestByID(ctx, reviewRequestID)
 	if err != nil {
 		logger.Printf("Review request not found: %s", reviewRequestID)
-		bot.AnswerCallbackQuery(callback.ID, "Review request not found")
+		bot.AnswerCallbackQuery(callback.ID, render.Plain(p.T("callback.review_not_found")))
 		return nil
 	}
 
 	// Verify the review belongs to the user
 	if req.ReviewerLogin != user.ReviewerLogin {
 		logger.Printf("User %s attempted to access review %s belonging to %s", user.ReviewerLogin, reviewRequestID, req.ReviewerLogin)
-		bot.AnswerCallbackQuery(callback.ID, "Access denied")
+		bot.AnswerCallbackQuery(callback.ID, render.Plain(p.T("callback.access_denied")))
 		return nil
 	}
 
@@ -127,7 +155,7 @@ func handleCallbackQuery(ctx context.Context, callback *tba.CallbackQuery, logge
 
 	default:
 		logger.Printf("Unknown action: %s", action)
-		bot.AnswerCallbackQuery(callback.ID, "Unknown action")
+		bot.AnswerCallbackQuery(callback.ID, render.Plain(p.T("common.unknown_action")))
 	}
 
 	return nil
@@ -177,6 +205,18 @@ func handleCommand(ctx context.Context, message *tba.Message, logger *log.Logger
 	case "whitelist_remove":
 		return handlers.HandleWhitelistRemove(ctx, message, logger)
 
+	case "whitelist_test":
+		return handlers.HandleWhitelistTest(ctx, message, logger)
+
+	case "blacklist_add":
+		return handlers.HandleBlacklistAdd(ctx, message, logger)
+
+	case "blacklist_remove":
+		return handlers.HandleBlacklistRemove(ctx, message, logger)
+
+	case "preset":
+		return handlers.HandlePreset(ctx, message, logger)
+
 	case "set_deadline_shift":
 		return handlers.HandleSetDeadlineShift(ctx, message, logger)
 
@@ -192,12 +232,99 @@ func handleCommand(ctx context.Context, message *tba.Message, logger *log.Logger
 	case "set_cleanup_duration":
 		return handlers.HandleSetCleanupDuration(ctx, message, logger)
 
+	case "override":
+		return handlers.HandleOverride(ctx, message, logger)
+
+	case "policies":
+		return handlers.HandlePolicies(ctx, message, logger)
+
+	case "rule":
+		return handlers.HandleRule(ctx, message, logger)
+
+	case "peers":
+		return handlers.HandlePeers(ctx, message, logger)
+
 	case "set_notify_whitelist_timeout":
 		return handlers.HandleSetNotifyWhitelistTimeout(ctx, message, logger)
 
 	case "set_notify_non_whitelist_cancel":
 		return handlers.HandleSetNotifyNonWhitelistCancel(ctx, message, logger)
 
+	case "set_slot_housekeeping":
+		return handlers.HandleSetSlotHousekeeping(ctx, message, logger)
+
+	case "set_housekeeping_buffer":
+		return handlers.HandleSetHousekeepingBuffer(ctx, message, logger)
+
+	case "availability":
+		return handlers.HandleAvailability(ctx, message, logger)
+
+	case "set_availability_days":
+		return handlers.HandleSetAvailabilityDays(ctx, message, logger)
+
+	case "set_lookahead":
+		return handlers.HandleSetLookahead(ctx, message, logger)
+
+	case "set_lookback":
+		return handlers.HandleSetLookback(ctx, message, logger)
+
+	case "set_sync_completed":
+		return handlers.HandleSetSyncCompleted(ctx, message, logger)
+
+	case "pause":
+		return handlers.HandlePause(ctx, message, logger)
+
+	case "resume":
+		return handlers.HandleResume(ctx, message, logger)
+
+	case "set_pause_policy":
+		return handlers.HandleSetPausePolicy(ctx, message, logger)
+
+	case "set_daily_cap":
+		return handlers.HandleSetDailyCap(ctx, message, logger)
+
+	case "set_weekly_cap":
+		return handlers.HandleSetWeeklyCap(ctx, message, logger)
+
+	case "set_min_gap":
+		return handlers.HandleSetMinGap(ctx, message, logger)
+
+	case "set_limit_action":
+		return handlers.HandleSetLimitAction(ctx, message, logger)
+
+	case "set_outside_hours_action":
+		return handlers.HandleSetOutsideHoursAction(ctx, message, logger)
+
+	case "set_blocked_peer_action":
+		return handlers.HandleSetBlockedPeerAction(ctx, message, logger)
+
+	case "set_timeout_action":
+		return handlers.HandleSetTimeoutAction(ctx, message, logger)
+
+	case "set_non_whitelist_action":
+		return handlers.HandleSetNonWhitelistAction(ctx, message, logger)
+
+	case "set_non_whitelist_deadline":
+		return handlers.HandleSetNonWhitelistDeadline(ctx, message, logger)
+
+	case "set_working_hours":
+		return handlers.HandleSetWorkingHours(ctx, message, logger)
+
+	case "set_quiet_hours":
+		return handlers.HandleSetQuietHours(ctx, message, logger)
+
+	case "set_timezone":
+		return handlers.HandleSetTimezone(ctx, message, logger)
+
+	case "language":
+		return handlers.HandleLanguage(ctx, message, logger)
+
+	case "slots":
+		return handlers.HandleSlots(ctx, message, logger)
+
+	case "openslot":
+		return handlers.HandleOpenSlot(ctx, message, logger)
+
 	case "status":
 		return handlers.HandleStatus(ctx, message, logger)
 
@@ -0,0 +1,249 @@
+package availability
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+// DateLayout is the layout used for holiday dates and occurrence keys
+const DateLayout = "2006-01-02"
+
+// SlotGranularityMinutes is the step School 21 calendar slots are aligned to
+const SlotGranularityMinutes = 15
+
+// MinNotice is how far ahead of its start a slot has to be opened
+const MinNotice = 30 * time.Minute
+
+// weekOrder lists weekdays in the order they are shown to users
+var weekOrder = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// dayNames maps accepted day names to weekdays
+var dayNames = map[string]time.Weekday{
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+	"sun": time.Sunday, "sunday": time.Sunday,
+}
+
+// dayGroups maps shorthand names to weekday masks
+var dayGroups = map[string]int32{
+	"daily":    0b1111111,
+	"weekdays": 0b0111110,
+	"weekends": 0b1000001,
+}
+
+// Window is a weekly availability window parsed from user input
+type Window struct {
+	WeekdayMask int32
+	StartMinute int32
+	EndMinute   int32
+}
+
+// Occurrence is a concrete slot produced by a template on a specific date
+type Occurrence struct {
+	TemplateID string
+	Date       string
+	Start      time.Time
+	End        time.Time
+}
+
+// Key identifies an occurrence so the same slot is never opened twice
+func (o Occurrence) Key() string {
+	return o.TemplateID + "/" + o.Date
+}
+
+// ParseWindow parses input such as "Mon-Thu 19:00-21:00", "Mon,Wed 18:00-20:00" or "weekdays 10:00-12:00"
+func ParseWindow(input string) (Window, error) {
+	normalized := strings.NewReplacer("–", "-", "—", "-").Replace(strings.TrimSpace(input))
+	fields := strings.Fields(normalized)
+	if len(fields) < 2 {
+		return Window{}, i18n.Errorf("input.expected_window", "Mon-Thu 19:00-21:00")
+	}
+
+	mask, err := ParseWeekdays(strings.Join(fields[:len(fields)-1], ""))
+	if err != nil {
+		return Window{}, err
+	}
+
+	start, end, err := ParseTimeRange(fields[len(fields)-1])
+	if err != nil {
+		return Window{}, err
+	}
+
+	return Window{WeekdayMask: mask, StartMinute: start, EndMinute: end}, nil
+}
+
+// ParseWeekdays parses a comma-separated list of days and day ranges into a weekday mask
+func ParseWeekdays(input string) (int32, error) {
+	var mask int32
+	for _, item := range strings.Split(strings.ToLower(input), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if group, ok := dayGroups[item]; ok {
+			mask |= group
+			continue
+		}
+
+		bounds := strings.SplitN(item, "-", 2)
+		first, ok := dayNames[bounds[0]]
+		if !ok {
+			return 0, i18n.Errorf("input.unknown_day", bounds[0])
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, ok = dayNames[bounds[1]]; !ok {
+				return 0, i18n.Errorf("input.unknown_day", bounds[1])
+			}
+		}
+
+		// Ranges may wrap around the week, e.g. Sat-Mon
+		for day := first; ; day = (day + 1) % 7 {
+			mask |= 1 << day
+			if day == last {
+				break
+			}
+		}
+	}
+
+	if mask == 0 {
+		return 0, i18n.Errorf("input.no_days")
+	}
+	return mask, nil
+}
+
+// ParseTimeRange parses "HH:MM-HH:MM" into minutes after midnight
+func ParseTimeRange(input string) (int32, int32, error) {
+	bounds := strings.SplitN(input, "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, i18n.Errorf("input.expected_range", "19:00-21:00")
+	}
+
+	start, err := ParseClock(bounds[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := ParseClock(bounds[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if end <= start {
+		return 0, 0, i18n.Errorf("input.range_order")
+	}
+	if start%SlotGranularityMinutes != 0 || end%SlotGranularityMinutes != 0 {
+		return 0, 0, i18n.Errorf("input.granularity", SlotGranularityMinutes)
+	}
+
+	return start, end, nil
+}
+
+// ParseClock parses "HH:MM" into minutes after midnight
+func ParseClock(input string) (int32, error) {
+	parts := strings.SplitN(strings.TrimSpace(input), ":", 2)
+	if len(parts) != 2 {
+		return 0, i18n.Errorf("input.invalid_time", input)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 || hours > 23 {
+		return 0, i18n.Errorf("input.invalid_time", input)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, i18n.Errorf("input.invalid_time", input)
+	}
+
+	return int32(hours*60 + minutes), nil
+}
+
+// FormatWindow renders a window as "Mon-Thu 19:00-21:00"
+func FormatWindow(mask, startMinute, endMinute int32) string {
+	return fmt.Sprintf("%s %s-%s", FormatWeekdays(mask), FormatClock(startMinute), FormatClock(endMinute))
+}
+
+// FormatWeekdays renders a weekday mask, collapsing consecutive days into ranges
+func FormatWeekdays(mask int32) string {
+	var parts []string
+	for i := 0; i < len(weekOrder); {
+		if mask&(1<<weekOrder[i]) == 0 {
+			i++
+			continue
+		}
+
+		j := i
+		for j+1 < len(weekOrder) && mask&(1<<weekOrder[j+1]) != 0 {
+			j++
+		}
+
+		first := weekOrder[i].String()[:3]
+		if j-i >= 2 {
+			parts = append(parts, first+"-"+weekOrder[j].String()[:3])
+		} else {
+			for k := i; k <= j; k++ {
+				parts = append(parts, weekOrder[k].String()[:3])
+			}
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// FormatClock renders minutes after midnight as "HH:MM"
+func FormatClock(minutes int32) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Occurrences expands templates into concrete slots for the days starting at from.
+// Dates listed in holidays (YYYY-MM-DD) are skipped, as are slots starting less than
+// MinNotice after from.
+func Occurrences(templates []*store.AvailabilityTemplate, holidays map[string]bool, from time.Time, days int, loc *time.Location) []Occurrence {
+	var occurrences []Occurrence
+
+	local := from.In(loc)
+	for d := 0; d < days; d++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+d, 0, 0, 0, 0, loc)
+		date := day.Format(DateLayout)
+		if holidays[date] {
+			continue
+		}
+
+		for _, tmpl := range templates {
+			if tmpl.WeekdayMask&(1<<day.Weekday()) == 0 {
+				continue
+			}
+
+			start := time.Date(day.Year(), day.Month(), day.Day(), 0, int(tmpl.StartMinute), 0, 0, loc)
+			end := time.Date(day.Year(), day.Month(), day.Day(), 0, int(tmpl.EndMinute), 0, 0, loc)
+			if start.Before(from.Add(MinNotice)) {
+				continue
+			}
+
+			occurrences = append(occurrences, Occurrence{
+				TemplateID: tmpl.ID,
+				Date:       date,
+				Start:      start,
+				End:        end,
+			})
+		}
+	}
+
+	return occurrences
+}
+
+// Overlaps reports whether [start, end) intersects [otherStart, otherEnd)
+func Overlaps(start, end, otherStart, otherEnd time.Time) bool {
+	return start.Before(otherEnd) && otherStart.Before(end)
+}
@@ -0,0 +1,145 @@
+package availability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		mask     int32
+		start    int32
+		end      int32
+		hasError bool
+	}{
+		{"Range", "Mon-Thu 19:00-21:00", 0b0011110, 19 * 60, 21 * 60, false},
+		{"EnDash", "Mon–Thu 19:00–21:00", 0b0011110, 19 * 60, 21 * 60, false},
+		{"List", "Mon,Wed 18:00-20:00", 0b0001010, 18 * 60, 20 * 60, false},
+		{"ListWithSpaces", "Mon, Wed 18:00-20:00", 0b0001010, 18 * 60, 20 * 60, false},
+		{"FullNames", "saturday 10:00-12:00", 0b1000000, 10 * 60, 12 * 60, false},
+		{"Wrapping", "Sat-Mon 10:00-12:00", 0b1000011, 10 * 60, 12 * 60, false},
+		{"Weekdays", "weekdays 09:15-10:45", 0b0111110, 9*60 + 15, 10*60 + 45, false},
+		{"MissingTime", "Mon-Thu", 0, 0, 0, true},
+		{"UnknownDay", "Funday 10:00-12:00", 0, 0, 0, true},
+		{"EndBeforeStart", "Mon 21:00-19:00", 0, 0, 0, true},
+		{"NotAligned", "Mon 19:10-21:00", 0, 0, 0, true},
+		{"BadClock", "Mon 25:00-26:00", 0, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := ParseWindow(tt.input)
+			if tt.hasError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.mask, window.WeekdayMask)
+			assert.Equal(t, tt.start, window.StartMinute)
+			assert.Equal(t, tt.end, window.EndMinute)
+		})
+	}
+}
+
+func TestFormatWindow(t *testing.T) {
+	tests := []struct {
+		mask     int32
+		expected string
+	}{
+		{0b0011110, "Mon-Thu 19:00-21:00"},
+		{0b0001010, "Mon,Wed 19:00-21:00"},
+		{0b0000110, "Mon,Tue 19:00-21:00"},
+		{0b1111111, "Mon-Sun 19:00-21:00"},
+		{0b1000001, "Sat,Sun 19:00-21:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FormatWindow(tt.mask, 19*60, 21*60))
+		})
+	}
+}
+
+func TestFormatWindow_RoundTrip(t *testing.T) {
+	window, err := ParseWindow("Tue-Fri 08:30-09:45")
+	require.NoError(t, err)
+
+	formatted := FormatWindow(window.WeekdayMask, window.StartMinute, window.EndMinute)
+	assert.Equal(t, "Tue-Fri 08:30-09:45", formatted)
+
+	reparsed, err := ParseWindow(formatted)
+	require.NoError(t, err)
+	assert.Equal(t, window, reparsed)
+}
+
+func TestOccurrences(t *testing.T) {
+	// Sunday, Jan 11 2026
+	from := time.Date(2026, 1, 11, 8, 0, 0, 0, time.UTC)
+	templates := []*store.AvailabilityTemplate{
+		{ID: "evening", WeekdayMask: 0b0011110, StartMinute: 19 * 60, EndMinute: 21 * 60},
+	}
+
+	t.Run("next week", func(t *testing.T) {
+		occurrences := Occurrences(templates, nil, from, 7, time.UTC)
+		require.Len(t, occurrences, 4)
+
+		assert.Equal(t, "2026-01-12", occurrences[0].Date)
+		assert.Equal(t, time.Date(2026, 1, 12, 19, 0, 0, 0, time.UTC), occurrences[0].Start)
+		assert.Equal(t, time.Date(2026, 1, 12, 21, 0, 0, 0, time.UTC), occurrences[0].End)
+		assert.Equal(t, "evening/2026-01-12", occurrences[0].Key())
+		assert.Equal(t, "2026-01-15", occurrences[3].Date)
+	})
+
+	t.Run("holidays are skipped", func(t *testing.T) {
+		holidays := map[string]bool{"2026-01-13": true}
+		occurrences := Occurrences(templates, holidays, from, 7, time.UTC)
+		require.Len(t, occurrences, 3)
+		for _, occ := range occurrences {
+			assert.NotEqual(t, "2026-01-13", occ.Date)
+		}
+	})
+
+	t.Run("slots inside the notice period are skipped", func(t *testing.T) {
+		monday := time.Date(2026, 1, 12, 18, 45, 0, 0, time.UTC)
+		occurrences := Occurrences(templates, nil, monday, 1, time.UTC)
+		assert.Empty(t, occurrences)
+
+		occurrences = Occurrences(templates, nil, monday.Add(-time.Hour), 1, time.UTC)
+		assert.Len(t, occurrences, 1)
+	})
+
+	t.Run("local time across DST", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+
+		daily := []*store.AvailabilityTemplate{
+			{ID: "daily", WeekdayMask: 0b1111111, StartMinute: 19 * 60, EndMinute: 21 * 60},
+		}
+		// Berlin switches from CET to CEST on Sunday, Mar 29 2026
+		saturday := time.Date(2026, 3, 28, 8, 0, 0, 0, time.UTC)
+		occurrences := Occurrences(daily, nil, saturday, 2, berlin)
+		require.Len(t, occurrences, 2)
+
+		assert.Equal(t, "2026-03-28", occurrences[0].Date)
+		assert.Equal(t, time.Date(2026, 3, 28, 18, 0, 0, 0, time.UTC), occurrences[0].Start.UTC())
+		assert.Equal(t, "2026-03-29", occurrences[1].Date)
+		assert.Equal(t, time.Date(2026, 3, 29, 17, 0, 0, 0, time.UTC), occurrences[1].Start.UTC())
+		assert.Equal(t, 2*time.Hour, occurrences[1].End.Sub(occurrences[1].Start))
+	})
+}
+
+func TestOverlaps(t *testing.T) {
+	base := time.Date(2026, 1, 12, 19, 0, 0, 0, time.UTC)
+
+	assert.True(t, Overlaps(base, base.Add(2*time.Hour), base.Add(time.Hour), base.Add(3*time.Hour)))
+	assert.True(t, Overlaps(base, base.Add(2*time.Hour), base.Add(30*time.Minute), base.Add(time.Hour)))
+	assert.False(t, Overlaps(base, base.Add(2*time.Hour), base.Add(2*time.Hour), base.Add(3*time.Hour)))
+	assert.False(t, Overlaps(base, base.Add(time.Hour), base.Add(-time.Hour), base))
+}
@@ -0,0 +1,125 @@
+package availability
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+)
+
+// Hours is a daily range in minutes after midnight. An end before the start runs past
+// midnight into the next day, e.g. 20:00-01:00, and equal bounds cover the whole day
+type Hours struct {
+	StartMinute int32
+	EndMinute   int32
+}
+
+// wholeDay reports whether the hours cover the whole day
+func (h Hours) wholeDay() bool {
+	return h.StartMinute == h.EndMinute
+}
+
+// wraps reports whether the hours run past midnight
+func (h Hours) wraps() bool {
+	return h.EndMinute < h.StartMinute
+}
+
+// WorkingHours are the hours the user takes reviews in on each weekday. Days without
+// hours take no reviews, while empty WorkingHours put no limit on any day
+type WorkingHours map[time.Weekday]Hours
+
+// ParseWorkingHours parses input such as "Mon-Fri 10:00-22:00; Sat 12:00-18:00".
+// "off" or an empty input returns empty WorkingHours
+func ParseWorkingHours(input string) (WorkingHours, error) {
+	normalized := strings.NewReplacer("–", "-", "—", "-").Replace(strings.TrimSpace(input))
+	if normalized == "" || strings.EqualFold(normalized, "off") {
+		return nil, nil
+	}
+
+	hours := make(WorkingHours)
+	for _, part := range strings.Split(normalized, ";") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, i18n.Errorf("input.expected_window", "Mon-Fri 10:00-22:00")
+		}
+
+		mask, err := ParseWeekdays(strings.Join(fields[:len(fields)-1], ""))
+		if err != nil {
+			return nil, err
+		}
+
+		bounds := strings.SplitN(fields[len(fields)-1], "-", 2)
+		if len(bounds) != 2 {
+			return nil, i18n.Errorf("input.expected_range", "10:00-22:00")
+		}
+		start, err := ParseClock(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		end, err := ParseClock(bounds[1])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, day := range weekOrder {
+			if mask&(1<<day) == 0 {
+				continue
+			}
+			if _, ok := hours[day]; ok {
+				return nil, i18n.Errorf("input.day_twice", day.String()[:3])
+			}
+			hours[day] = Hours{StartMinute: start, EndMinute: end}
+		}
+	}
+
+	if len(hours) == 0 {
+		return nil, i18n.Errorf("input.no_days")
+	}
+	return hours, nil
+}
+
+// FormatWorkingHours renders working hours as "Mon-Fri 10:00-22:00; Sat 12:00-18:00",
+// the form ParseWorkingHours reads back. Empty working hours render as ""
+func FormatWorkingHours(hours WorkingHours) string {
+	var order []Hours
+	masks := make(map[Hours]int32)
+	for _, day := range weekOrder {
+		h, ok := hours[day]
+		if !ok {
+			continue
+		}
+		if _, seen := masks[h]; !seen {
+			order = append(order, h)
+		}
+		masks[h] |= 1 << day
+	}
+
+	parts := make([]string, len(order))
+	for i, h := range order {
+		parts[i] = fmt.Sprintf("%s %s-%s", FormatWeekdays(masks[h]), FormatClock(h.StartMinute), FormatClock(h.EndMinute))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Contains reports whether t falls into the working hours on the wall clock of loc.
+// Hours that run past midnight count for the early hours of the next day
+func (w WorkingHours) Contains(t time.Time, loc *time.Location) bool {
+	if len(w) == 0 {
+		return true
+	}
+
+	t = t.In(loc)
+	minute := int32(t.Hour()*60 + t.Minute())
+	if h, ok := w[t.Weekday()]; ok {
+		if h.wholeDay() || minute >= h.StartMinute && (h.wraps() || minute < h.EndMinute) {
+			return true
+		}
+	}
+
+	previous, ok := w[(t.Weekday()+6)%7]
+	return ok && previous.wraps() && minute < previous.EndMinute
+}
@@ -0,0 +1,109 @@
+package availability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+)
+
+func TestParseWorkingHours(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected WorkingHours
+		errKey   string
+	}{
+		{"Off", "off", nil, ""},
+		{"Empty", " ", nil, ""},
+		{"Single", "Mon-Wed 10:00-22:00", WorkingHours{
+			time.Monday: {600, 1320}, time.Tuesday: {600, 1320}, time.Wednesday: {600, 1320},
+		}, ""},
+		{"PerWeekday", "weekdays 10:00-22:00; Sat 12:30–18:00;", WorkingHours{
+			time.Monday: {600, 1320}, time.Tuesday: {600, 1320}, time.Wednesday: {600, 1320},
+			time.Thursday: {600, 1320}, time.Friday: {600, 1320}, time.Saturday: {750, 1080},
+		}, ""},
+		{"PastMidnight", "Fri 20:00-01:00", WorkingHours{time.Friday: {1200, 60}}, ""},
+		{"WholeDay", "Sun 00:00-00:00", WorkingHours{time.Sunday: {0, 0}}, ""},
+		{"DayTwice", "Mon-Fri 10:00-22:00; Fri 12:00-18:00", nil, "input.day_twice"},
+		{"MissingTime", "Mon-Fri", nil, "input.expected_window"},
+		{"UnknownDay", "Funday 10:00-12:00", nil, "input.unknown_day"},
+		{"BadRange", "Mon 10:00", nil, "input.expected_range"},
+		{"BadClock", "Mon 10:00-24:00", nil, "input.invalid_time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hours, err := ParseWorkingHours(tt.input)
+			if tt.errKey != "" {
+				var i18nErr *i18n.Error
+				require.ErrorAs(t, err, &i18nErr)
+				assert.Equal(t, tt.errKey, i18nErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, hours)
+		})
+	}
+}
+
+func TestFormatWorkingHours(t *testing.T) {
+	input := "Sat 12:00-18:00; Mon-Fri 10:00-22:00; Sun 20:00-01:00"
+	hours, err := ParseWorkingHours(input)
+	require.NoError(t, err)
+
+	formatted := FormatWorkingHours(hours)
+	assert.Equal(t, "Mon-Fri 10:00-22:00; Sat 12:00-18:00; Sun 20:00-01:00", formatted)
+
+	again, err := ParseWorkingHours(formatted)
+	require.NoError(t, err)
+	assert.Equal(t, hours, again)
+	assert.Empty(t, FormatWorkingHours(nil))
+}
+
+func TestWorkingHours_Contains(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	hours, err := ParseWorkingHours("Mon-Thu 10:00-22:00; Fri 20:00-02:00; Sun 00:00-00:00")
+	require.NoError(t, err)
+
+	// 2026-03-02 is a Monday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, moscow)
+	}
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		expected bool
+	}{
+		{"Inside", at(3, 15, 0), true},
+		{"AtStart", at(2, 10, 0), true},
+		{"BeforeStart", at(2, 9, 59), false},
+		{"AtEnd", at(2, 22, 0), false},
+		{"NoHoursOnSaturdayEvening", at(7, 21, 0), false},
+		{"FridayEvening", at(6, 23, 30), true},
+		{"PastMidnightIntoSaturday", at(7, 1, 45), true},
+		{"EndPastMidnight", at(7, 2, 0), false},
+		{"ThursdayHoursEndAtMidnight", at(6, 0, 30), false},
+		{"WholeSunday", at(8, 23, 59), true},
+		{"SundayHoursDoNotRunIntoMonday", at(9, 0, 30), false},
+		// 19:30 UTC on Monday is 22:30 in Moscow, past the Monday hours
+		{"UserTimezone", time.Date(2026, 3, 2, 19, 30, 0, 0, time.UTC), false},
+		// 21:30 UTC on Friday is 00:30 on Saturday in Moscow, inside the Friday hours
+		{"UserTimezoneNextDay", time.Date(2026, 3, 6, 21, 30, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, hours.Contains(tt.t, moscow))
+		})
+	}
+
+	t.Run("NoWorkingHours", func(t *testing.T) {
+		assert.True(t, WorkingHours(nil).Contains(at(7, 3, 0), moscow))
+	})
+}
@@ -0,0 +1,96 @@
+package availability
+
+import (
+	"strings"
+	"time"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/external"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+)
+
+// MinSlotDuration is the shortest slot that can be opened
+const MinSlotDuration = SlotGranularityMinutes * time.Minute
+
+// ParseDay parses "today", "tomorrow", a day name or a YYYY-MM-DD date into midnight of that day.
+// Day names refer to the next such day, today included.
+func ParseDay(input string, now time.Time, loc *time.Location) (time.Time, error) {
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	day := strings.ToLower(strings.TrimSpace(input))
+	switch day {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if weekday, ok := dayNames[day]; ok {
+		offset := (int(weekday) - int(today.Weekday()) + 7) % 7
+		return today.AddDate(0, 0, offset), nil
+	}
+
+	date, err := time.ParseInLocation(DateLayout, day, loc)
+	if err != nil {
+		return time.Time{}, i18n.Errorf("input.unknown_date", input)
+	}
+	return date, nil
+}
+
+// ParseSlot parses input such as "tomorrow 19:00-21:00" into a slot start and end
+func ParseSlot(input string, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	normalized := strings.NewReplacer("–", "-", "—", "-").Replace(strings.TrimSpace(input))
+	fields := strings.Fields(normalized)
+	if len(fields) != 2 {
+		return time.Time{}, time.Time{}, i18n.Errorf("input.expected_slot")
+	}
+
+	day, err := ParseDay(fields[0], now, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	startMinute, endMinute, err := ParseTimeRange(fields[1])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, int(startMinute), 0, 0, loc)
+	end := time.Date(day.Year(), day.Month(), day.Day(), 0, int(endMinute), 0, 0, loc)
+	return start, end, nil
+}
+
+// ValidateNewSlot checks a slot against the platform rules before it is opened:
+// it must be at least MinSlotDuration long, start at least MinNotice from now
+// and not overlap any existing calendar slot
+func ValidateNewSlot(start, end time.Time, existing []external.CalendarSlot, now time.Time) error {
+	if end.Sub(start) < MinSlotDuration {
+		return i18n.Errorf("openslot.too_short", SlotGranularityMinutes)
+	}
+
+	if start.Before(now.Add(MinNotice)) {
+		return i18n.Errorf("openslot.too_soon", int(MinNotice.Minutes()))
+	}
+
+	if conflict := FindConflict(start, end, existing, ""); conflict != nil {
+		loc := start.Location()
+		return i18n.Errorf("openslot.overlap",
+			conflict.Start.In(loc).Format(DateLayout+" 15:04"), conflict.End.In(loc).Format("15:04 MST"))
+	}
+
+	return nil
+}
+
+// FindConflict returns the first existing slot overlapping [start, end), ignoring the slot with ignoreID
+func FindConflict(start, end time.Time, existing []external.CalendarSlot, ignoreID string) *external.CalendarSlot {
+	for i := range existing {
+		if ignoreID != "" && existing[i].ID == ignoreID {
+			continue
+		}
+		if Overlaps(start, end, existing[i].Start, existing[i].End) {
+			return &existing[i]
+		}
+	}
+	return nil
+}
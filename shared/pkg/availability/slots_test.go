@@ -0,0 +1,130 @@
+package availability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/external"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+)
+
+func TestParseDay(t *testing.T) {
+	// Wednesday, Jan 14 2026
+	now := time.Date(2026, 1, 14, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		expected string
+		hasError bool
+	}{
+		{"today", "2026-01-14", false},
+		{"Tomorrow", "2026-01-15", false},
+		{"wed", "2026-01-14", false},
+		{"fri", "2026-01-16", false},
+		{"monday", "2026-01-19", false},
+		{"2026-02-01", "2026-02-01", false},
+		{"someday", "", true},
+		{"2026-13-01", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			day, err := ParseDay(tt.input, now, time.UTC)
+			if tt.hasError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, day.Format(DateLayout))
+			assert.Equal(t, 0, day.Hour())
+		})
+	}
+}
+
+func TestParseSlot(t *testing.T) {
+	now := time.Date(2026, 1, 14, 10, 30, 0, 0, time.UTC)
+
+	start, end, err := ParseSlot("tomorrow 19:00–21:30", now, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 15, 19, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 1, 15, 21, 30, 0, 0, time.UTC), end)
+
+	t.Run("local time across DST", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+
+		// Berlin switches to CEST overnight, so 19:00 tomorrow is 17:00 UTC, not 18:00
+		saturday := time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC)
+		start, end, err := ParseSlot("tomorrow 19:00-20:00", saturday, berlin)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 3, 29, 17, 0, 0, 0, time.UTC), start.UTC())
+		assert.Equal(t, time.Date(2026, 3, 29, 18, 0, 0, 0, time.UTC), end.UTC())
+	})
+
+	// Errors come from the catalog, so they are shown in the user's language
+	var i18nErr *i18n.Error
+	_, _, err = ParseSlot("tomorrow", now, time.UTC)
+	require.ErrorAs(t, err, &i18nErr)
+	assert.Equal(t, "input.expected_slot", i18nErr.Key)
+
+	_, _, err = ParseSlot("tomorrow 21:00-19:00", now, time.UTC)
+	require.ErrorAs(t, err, &i18nErr)
+	assert.Equal(t, "input.range_order", i18nErr.Key)
+	assert.Equal(t, "Время окончания должно быть позже времени начала", string(i18n.New(i18n.Russian).Err(err)))
+}
+
+func TestValidateNewSlot(t *testing.T) {
+	now := time.Date(2026, 1, 14, 10, 30, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 1, 14, hour, minute, 0, 0, time.UTC)
+	}
+	existing := []external.CalendarSlot{
+		{ID: "free", Start: at(14, 0), End: at(15, 0), Type: "FREE_TIME"},
+		{ID: "booked", Start: at(16, 0), End: at(16, 30), Type: "BOOKING"},
+	}
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		end      time.Time
+		hasError bool
+	}{
+		{"Valid", at(12, 0), at(13, 0), false},
+		{"AdjacentToExisting", at(15, 0), at(16, 0), false},
+		{"TooSoon", at(10, 45), at(11, 30), true},
+		{"ExactlyMinNotice", at(11, 0), at(11, 30), false},
+		{"OverlapsFree", at(14, 30), at(15, 30), true},
+		{"OverlapsBooked", at(15, 45), at(17, 0), true},
+		{"Covers", at(13, 0), at(17, 0), true},
+		{"ZeroLength", at(12, 0), at(12, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNewSlot(tt.start, tt.end, existing, now)
+			if tt.hasError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFindConflict(t *testing.T) {
+	base := time.Date(2026, 1, 14, 14, 0, 0, 0, time.UTC)
+	existing := []external.CalendarSlot{
+		{ID: "a", Start: base, End: base.Add(time.Hour)},
+		{ID: "b", Start: base.Add(2 * time.Hour), End: base.Add(3 * time.Hour)},
+	}
+
+	conflict := FindConflict(base.Add(30*time.Minute), base.Add(150*time.Minute), existing, "a")
+	require.NotNil(t, conflict)
+	assert.Equal(t, "b", conflict.ID)
+
+	assert.Nil(t, FindConflict(base, base.Add(90*time.Minute), existing, "a"))
+}
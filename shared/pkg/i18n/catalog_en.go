@@ -0,0 +1,530 @@
+package i18n
+
+// en is the English catalog. Plural messages have one key per form, e.g. "unit.minutes.one"
+var en = map[string]string{
+	// Common
+	"common.user_not_found": "User not found. Please use /start to authenticate.",
+	"common.update_failed":  "Failed to update setting: %v",
+	"common.status_failed":  "Failed to update status: %v",
+	"common.tokens_failed":  "Failed to get tokens: %v",
+	"common.unknown_action": "Unknown action",
+	"common.yes":            "Yes",
+	"common.no":             "No",
+	"common.invalid_date":   "Invalid date. Use the YYYY-MM-DD format, e.g. %s",
+
+	// Units
+	"unit.minutes.one":    "%d minute",
+	"unit.minutes.other":  "%d minutes",
+	"unit.hours.one":      "%d hour",
+	"unit.hours.other":    "%d hours",
+	"unit.days.one":       "%d day",
+	"unit.days.other":     "%d days",
+	"unit.reviews.one":    "%d review",
+	"unit.reviews.other":  "%d reviews",
+	"unit.projects.one":   "%d project",
+	"unit.projects.other": "%d projects",
+	"unit.entries.one":    "%d entry",
+	"unit.entries.other":  "%d entries",
+
+	// Dates
+	"time.short": "%[1]s %[2]d %[3]s",
+	"time.day":   "%[1]s, %[2]s %[3]d",
+	"month.1":    "Jan",
+	"month.2":    "Feb",
+	"month.3":    "Mar",
+	"month.4":    "Apr",
+	"month.5":    "May",
+	"month.6":    "Jun",
+	"month.7":    "Jul",
+	"month.8":    "Aug",
+	"month.9":    "Sep",
+	"month.10":   "Oct",
+	"month.11":   "Nov",
+	"month.12":   "Dec",
+	"weekday.0":  "Sun",
+	"weekday.1":  "Mon",
+	"weekday.2":  "Tue",
+	"weekday.3":  "Wed",
+	"weekday.4":  "Thu",
+	"weekday.5":  "Fri",
+	"weekday.6":  "Sat",
+
+	// Authentication
+	"start.welcome_back":        "Welcome back, %s! You are already authenticated.",
+	"start.prompt":              "Please authenticate by sending your School 21 credentials in the format:\n\n`login:password`\n\nYour credentials will be stored securely in YDB.",
+	"auth.invalid_format":       "Invalid format. Please send your credentials in the format:\n\n`login:password`",
+	"auth.already":              "You are already authenticated as %s.\n\nUse /logout first if you want to re-authenticate.",
+	"auth.failed":               "Authentication failed. Please check your credentials and try again.",
+	"auth.tokens_failed":        "Authentication succeeded, but failed to store tokens. Please contact support.",
+	"auth.user_failed":          "Authentication succeeded, but failed to create user record. Please contact support.",
+	"auth.success":              "✅ Successfully authenticated as %s!\n\nYou can now use the bot. Use /help to see available commands.",
+	"logout.not_authenticated":  "You are not authenticated.",
+	"logout.success":            "✅ Logged out successfully. You can authenticate again with /start.",
+	"command.unknown":           "Unknown command: %s\n\nUse /help to see available commands.",
+	"callback.invalid":          "Invalid callback data",
+	"callback.review_not_found": "Review request not found",
+	"callback.access_denied":    "Access denied",
+
+	// Settings
+	"settings.failed":                      "Failed to retrieve settings.",
+	"settings.title":                       "*Your Settings*",
+	"settings.deadline_shift":              "📅 Response Deadline Shift: %s",
+	"settings.cancel_delay":                "⏱️ Non-Whitelist Cancel Delay: %s",
+	"settings.notify_whitelist_timeout":    "🔔 Notify Whitelist Timeout: %s",
+	"settings.notify_non_whitelist_cancel": "🔔 Notify Non-Whitelist Cancel: %s",
+	"settings.slot_shift_threshold":        "🔄 Slot Shift Threshold: %s",
+	"settings.slot_shift_duration":         "⬇️ Slot Shift Duration: %s",
+	"settings.cleanup_duration":            "🧹 Cleanup Duration: %s",
+	"settings.housekeeping":                "✂️ Slot Housekeeping: %s",
+	"settings.housekeeping_buffer":         "↔️ Housekeeping Buffer: %s",
+	"settings.availability_days":           "🗓️ Availability Days Ahead: %s",
+	"settings.lookahead":                   "🔭 Booking Lookahead: %s",
+	"settings.lookback":                    "⏪ Booking Lookback: %s",
+	"settings.sync_completed":              "🎓 Whitelist Completed Projects: %s",
+	"settings.paused":                      "⏸️ Paused: %s",
+	"settings.pause_policy":                "📥 Pause Policy: %s",
+	"settings.quiet_hours":                 "🌙 Quiet Hours: %s",
+	"settings.timezone":                    "🌍 Timezone: %s",
+	"settings.daily_cap":                   "📆 Daily Review Cap: %s",
+	"settings.weekly_cap":                  "🗓️ Weekly Review Cap: %s",
+	"settings.min_gap":                     "↔️ Minimum Gap Between Reviews: %s",
+	"settings.limit_action":                "🚦 On Limit: %s",
+	"settings.working_hours":               "🕘 Working Hours: %s",
+	"settings.outside_hours_action":        "🌃 Outside Working Hours: %s",
+	"settings.blocked_peer_action":         "🙅 Blocked Peers: %s",
+	"settings.timeout_action":              "⏰ On Timeout: %s",
+	"settings.non_whitelist_action":        "🚫 Non-Whitelisted Bookings: %s",
+	"settings.non_whitelist_deadline":      "⏳ Non-Whitelisted Answer Time: %s",
+	"settings.off":                         "Off",
+	"settings.language":                    "🗣️ Language: %s",
+	"settings.policies":                    "⚖️ Decision Policies: %s",
+	"settings.overrides":                   "🎯 Overrides:",
+	"settings.menu_hint":                   "Tap a setting to change it. Pause, quiet hours, working hours, the timezone and decision policies are changed with /pause, /set_quiet_hours, /set_working_hours, /set_timezone and /policies.",
+	"settings.menu_range":                  "Allowed: %d - %d",
+	"settings.menu_pick":                   "Pick a value:",
+	"settings.menu_back":                   "« Back",
+	"settings.menu_saved":                  "✅ Saved",
+	"setting.numeric_usage":                "Usage: /%s <value>\n\nValid range: %d - %d (step %d)",
+	"setting.out_of_range":                 "Value must be between %d and %d",
+	"setting.values_usage":                 "Usage: /%s <value>\n\nAllowed values: %s",
+	"setting.not_allowed":                  "Invalid value. Allowed values: %s",
+	"setting.bool_usage":                   "Usage: /%s <on|off>",
+	"setting.step":                         "Value must be a multiple of %d",
+	"setting.rule_threshold_deadline":      "Slot shift threshold (%d min) must be greater than the response deadline shift (%d min)",
+	"setting.rule_daily_weekly":            "The daily cap (%d) must not be above the weekly cap (%d)",
+	"setting.rule_duration_threshold":      "Slot shift duration (%d min) must be less than the slot shift threshold (%d min)",
+	"setting.updated":                      "✅ Saved. %s",
+	"setting.not_in_chain":                 "The %s policy is not in your decision policies, so the setting has no effect until you add it with /policies.",
+	"housekeeping.usage":                   "Usage: /set_slot_housekeeping <off|trim|split>\n\noff - leave partially booked slots as they are\ntrim - shrink slots to their bookings plus the buffer\nsplit - keep free time, but leave a buffer-sized break around bookings",
+
+	// Whitelist
+	"whitelist.failed":                     "Failed to retrieve whitelist.",
+	"whitelist.empty":                      "Your whitelist and blacklist are empty.\n\nUse /whitelist_add to add projects or families, and /blacklist_add for those you never review.",
+	"whitelist.title":                      "*Your Whitelist*",
+	"whitelist.families":                   "📁 Families:",
+	"whitelist.projects":                   "📦 Projects:",
+	"whitelist.add_usage":                  "Usage: /whitelist_add <family|project|glob|regex> <name> [until <YYYY-MM-DD>|for <duration>]\nSend /whitelist_add alone to browse the known families and projects.\n\nExample:\n/whitelist_add family \"C - I\"\n/whitelist_add project \"go-concurrency\"\n/whitelist_add glob CPP*\n/whitelist_add regex ^go-\n/whitelist_add family \"C - I\" for 5d",
+	"whitelist.invalid_type":               "Invalid entry type. Use 'family', 'project', 'glob' or 'regex'.",
+	"whitelist.add_failed":                 "Failed to add to whitelist: %v",
+	"whitelist.added":                      "✅ Added %s to your whitelist.",
+	"whitelist.remove_usage":               "Usage: /whitelist_remove <name>\n\nExample: /whitelist_remove \"C - I\"",
+	"whitelist.remove_failed":              "Failed to remove from whitelist: %v",
+	"whitelist.removed":                    "✅ Removed %s from your whitelist.",
+	"whitelist.not_found":                  "%s is not in your whitelist.",
+	"whitelist.browser_title":              "*Whitelist*\n\nPick a family to see its projects. ✅ marks whitelisted families and projects.",
+	"whitelist.browser_family":             "*%s*\n\n%s. Tap one to add it to your whitelist or remove it.",
+	"whitelist.browser_family_whitelisted": "The whole family is whitelisted, so all its projects are accepted.",
+	"whitelist.browser_whole_family":       "Whole family",
+	"whitelist.browser_back":               "« Families",
+	"whitelist.browser_empty":              "The list of projects has not been loaded yet. Use /whitelist_add <family|project> <name> for now.",
+	"whitelist.browser_failed":             "Failed to load the list of projects.",
+	"whitelist.browser_outdated":           "The list of projects has changed, send /whitelist_add again.",
+	"whitelist.unknown_family":             "❓ There is no family called %s.",
+	"whitelist.unknown_project":            "❓ There is no project called %s.",
+	"whitelist.did_you_mean":               "Did you mean one of these? Tap to add it.",
+	"whitelist.no_suggestions":             "Send /whitelist_add without arguments to browse the known names.",
+	"whitelist.patterns":                   "🔎 Patterns:",
+	"whitelist.synced":                     "🎓 Completed projects (kept in sync):",
+	"whitelist.pattern_empty":              "The pattern is empty.",
+	"whitelist.pattern_too_long":           "The pattern is too long, the limit is %d characters.",
+	"whitelist.invalid_glob":               "%s is not a valid glob pattern. Use * for any text, ? for one character and [abc] for one of several.",
+	"whitelist.invalid_regex":              "%s is not a valid regular expression: %v",
+	"whitelist.pattern_added":              "✅ Added pattern %s to your whitelist. It is checked against project names and family labels.",
+	"whitelist.pattern_matches":            "It matches %s right now, e.g. %s.",
+	"whitelist.pattern_matches_none":       "It does not match any known project yet.",
+	"whitelist.test_usage":                 "Usage: /whitelist_test <project>\n\nExample: /whitelist_test go-concurrency",
+	"whitelist.test_match":                 "✅ %s is whitelisted.\n\nEntry: %s\nMatched: %s",
+	"whitelist.test_blacklisted":           "🚫 %s is blacklisted, its bookings are cancelled right away.\n\nEntry: %s\nMatched: %s",
+	"whitelist.test_no_match":              "❌ %s is not whitelisted, no entry matches it or its family.",
+	"whitelist.test_by_project":            "project name %s",
+	"whitelist.test_by_family":             "family label %s",
+	"whitelist.test_family":                "Family: %s",
+	"whitelist.test_unknown_project":       "This project is not in the list of known projects, so its family is unknown and family entries were not checked.",
+	"whitelist.entry_family":               "family %s",
+	"whitelist.entry_project":              "project %s",
+	"whitelist.entry_glob":                 "glob pattern %s",
+	"whitelist.entry_regex":                "regular expression %s",
+	"whitelist.presets":                    "📚 Subscribed presets:",
+	"whitelist.test_preset":                "Preset: %s",
+	"whitelist.expires":                    "It expires on %s.",
+	"whitelist.until":                      "until %s",
+	"whitelist.invalid_duration":           "%s is not a valid duration. Use a number of hours, days or weeks, e.g. 12h, 3d or 2w.",
+	"whitelist.expiry_in_past":             "The expiry must be in the future.",
+	"whitelist.expiry_too_far":             "The expiry must be within %d days.",
+
+	// Overrides
+	"override.usage":           "Usage:\n/override - list overrides\n/override <family|project> <name> <setting> <value>\n/override remove <family|project> <name> [setting]\n\nSettings: %s\n\nExample:\n/override family \"C - I\" deadline_shift 45",
+	"override.failed":          "Failed to load setting overrides.",
+	"override.invalid_type":    "Overrides are set for a 'family' or a 'project'.",
+	"override.unknown_setting": "This setting cannot be overridden. Use one of: %s.",
+	"override.update_failed":   "Failed to update override: %v",
+	"override.set":             "✅ For %s: %s",
+	"override.item":            "%s: %s",
+	"override.removed":         "✅ Removed the %s override of %s.",
+	"override.removed_all":     "✅ Removed every override of %s.",
+	"override.not_found":       "There is no such override of %s.",
+	"override.list_empty":      "You have no overrides, so your settings apply to every project. Use /override <family|project> <name> <setting> <value> to change one of %s for a single family or project.",
+	"override.list_hint":       "A project override wins over a family override, which wins over /settings. Remove one with /override remove <family|project> <name> [setting].",
+
+	// Decision policies
+	"policies.list":          "⚖️ *Decision Policies*\n\nYour chain: %s\n\nAvailable policies:\n%s\n\nThe first policy that decides on a booking wins. The working hours and limit policies hold back bookings a policy after them keeps, so putting them after whitelist exempts whitelisted projects. Bookings no policy decides on are cancelled after the non-whitelist cancel delay.\n\nChange the order: /policies <policy> [policy...]\nBack to the default: /policies reset",
+	"policies.updated":       "✅ Decision policies: %s",
+	"policies.reset":         "✅ Decision policies reset to %s.",
+	"policies.update_failed": "Failed to update decision policies: %v",
+	"policies.unknown":       "Unknown policy %s. Available: %s.",
+	"policies.duplicate":     "Policy %s is listed twice.",
+	"policies.deny_first":    "Policy %s must come before %s, so a project on both lists is never kept.",
+	"policy.deny_list":       "cancel bookings of blacklisted projects right away",
+	"policy.working_hours":   "ask about or decline bookings outside your working hours, see /set_outside_hours_action",
+	"policy.daily_cap":       "hold back bookings over your daily cap, see /set_daily_cap",
+	"policy.weekly_cap":      "hold back bookings over your weekly cap, see /set_weekly_cap",
+	"policy.min_gap":         "hold back bookings too close to another review, see /set_min_gap",
+	"policy.peers":           "keep bookings of trusted students, ask about or decline blocked ones",
+	"policy.rules":           "apply the first of your /rule rules that holds",
+	"policy.whitelist":       "keep bookings of whitelisted projects",
+
+	// Decision rules
+	"rule.usage":         "Usage:\n/rule - List your rules\n/rule add <approve|ask|decline> <expression>\n/rule remove <number>\n/rule test <project> <day> <HH:MM>\n\nAttributes: %s\n\nExample:\n/rule add approve family == \"CPP\" && weekday in [1..4] && hour >= 18",
+	"rule.failed":        "Failed to load decision rules.",
+	"rule.invalid":       "Invalid rule: %v",
+	"rule.add_failed":    "Failed to add rule: %v",
+	"rule.added":         "✅ Added rule %s",
+	"rule.not_in_chain":  "Rules are not in your decision policies, so the rule has no effect until you add them with /policies.",
+	"rule.remove_usage":  "Usage: /rule remove <number>\n\nUse /rule to see the numbers.",
+	"rule.not_found":     "No rule with number %d.",
+	"rule.remove_failed": "Failed to remove rule: %v",
+	"rule.removed":       "✅ Removed rule %s",
+	"rule.item":          "%s: `%s`",
+	"rule.list_empty":    "You have no rules. Rules decide on bookings with conditions such as\n`family == \"CPP\" && weekday in [1..4] && hour >= 18`\n\nAdd one with /rule add <approve|ask|decline> <expression>. Attributes: %s.",
+	"rule.list_title":    "📐 *Your Rules*",
+	"rule.list_hint":     "The first rule that holds decides. Attributes: %s. Check a booking with /rule test <project> <day> <HH:MM>.",
+	"rule.test_usage":    "Usage: /rule test <project> <day> <HH:MM>\n\nExample: /rule test CPP1_s21_matrix+ tomorrow 19:00",
+	"rule.test_invalid":  "Invalid booking time: %v",
+	"rule.test_title":    "🧪 *Booking at %s*\n\n%s",
+	"rule.test_no_rules": "You have no rules yet.",
+	"rule.test_error":    "(%v)",
+	"rule.test_none":     "No rule holds, so the next policy decides.",
+	"rule.test_decision": "Decision: %s",
+
+	// Rule expression errors
+	"rule_error.empty":           "The rule is empty",
+	"rule_error.too_long":        "The rule is longer than %d characters",
+	"rule_error.not_condition":   "A rule must be a condition, e.g. hour >= 18",
+	"rule_error.timeout":         "Rule evaluation timed out",
+	"rule_error.unterminated":    "Unterminated text at position %d",
+	"rule_error.unexpected":      "Unexpected \"%s\" at position %d",
+	"rule_error.expected":        "Expected \"%s\" at position %d",
+	"rule_error.expected_end":    "Expected \"%s\" at the end",
+	"rule_error.ends_early":      "The rule ends too early",
+	"rule_error.not_needs":       "\"!\" at position %d needs a condition, not %s",
+	"rule_error.matches_pattern": "\"matches\" at position %d needs a pattern in quotes, e.g. project matches \"CPP*\"",
+	"rule_error.matches_text":    "\"matches\" at position %d needs text, not %s",
+	"rule_error.bad_pattern":     "Invalid pattern \"%s\" at position %d",
+	"rule_error.range":           "The range at position %d needs numbers, like 1..4",
+	"rule_error.list_kind":       "The list item at position %d is %s, but is compared with %s",
+	"rule_error.too_large":       "Number %s at position %d is too large",
+	"rule_error.unknown_attr":    "Unknown attribute \"%s\" at position %d, use one of %s",
+	"rule_error.joins":           "\"%s\" at position %d joins conditions, e.g. hour >= 18 && weekday <= 5",
+	"rule_error.compare":         "Cannot compare %s with %s at position %d",
+	"rule_error.numbers_only":    "\"%s\" at position %d compares numbers, not %s",
+	"rule_error.kind_number":     "a number",
+	"rule_error.kind_text":       "text",
+	"rule_error.kind_bool":       "a condition",
+
+	// Peers
+	"peers.usage":         "Usage:\n/peers - List your peers\n/peers trust <login...> - Keep bookings of these students\n/peers block <login...> - Ask about or decline their bookings, see /set_blocked_peer_action\n/peers remove <login...>",
+	"peers.failed":        "Failed to load your peers.",
+	"peers.invalid_login": "%s is not a valid login.",
+	"peers.update_failed": "Failed to update your peers: %v",
+	"peers.trusted":       "✅ Trusted %s. Their bookings are kept.",
+	"peers.blocked":       "🚫 Blocked %s. Their bookings are handled by /set_blocked_peer_action.",
+	"peers.removed":       "✅ Removed %s from your peers.",
+	"peers.not_in_chain":  "Peers are not in your decision policies, so the lists have no effect until you add them with /policies.",
+	"peers.list_empty":    "You have no peers. Trust study group mates with /peers trust <login> to keep their bookings, or block students with /peers block <login>.",
+	"peers.list_title":    "👥 *Your Peers*",
+	"peers.list_trusted":  "Trusted: %s",
+	"peers.list_blocked":  "Blocked: %s",
+	"peers.list_none":     "none",
+	"peers.list_hint":     "Bookings of trusted students are kept, bookings of blocked students are asked about or declined, see /set_blocked_peer_action. A team with a student who is not trusted is left to the next policy.",
+
+	// Presets
+	"preset.usage":           "Usage:\n/preset - list presets\n/preset show <name>\n/preset subscribe <name>\n/preset unsubscribe <name>\n/preset create <name>\n/preset add <name> <family|project|glob|regex> <entry>\n/preset remove <name> <entry>\n/preset delete <name>",
+	"preset.failed":          "Failed to load presets.",
+	"preset.list_title":      "*Whitelist Presets*",
+	"preset.list_item":       "%s - %s, by %s",
+	"preset.list_hint":       "✅ marks presets you subscribe to. Use /preset show <name> to see the entries.",
+	"preset.list_empty":      "There are no presets yet. Create one with /preset create <name>.",
+	"preset.show_title":      "*Preset %s*\nOwner: %s",
+	"preset.show_empty":      "The preset has no entries yet.",
+	"preset.invalid_name":    "Preset names may only contain latin letters, digits, - and _, up to %d characters.",
+	"preset.not_found":       "There is no preset called %s.",
+	"preset.exists":          "A preset called %s already exists.",
+	"preset.created":         "✅ Created preset %s. Add entries with /preset add %s <family|project|glob|regex> <entry>.",
+	"preset.forbidden":       "Only the owner of preset %s or an admin can change it.",
+	"preset.update_failed":   "Failed to update preset: %v",
+	"preset.entry_added":     "✅ Added %s to preset %s.",
+	"preset.entry_removed":   "✅ Removed %s from preset %s.",
+	"preset.entry_not_found": "%s is not in preset %s.",
+	"preset.did_you_mean":    "Did you mean %s?",
+	"preset.deleted":         "✅ Deleted preset %s.",
+	"preset.subscribed":      "✅ Subscribed to preset %s. Its entries now count next to your own whitelist, and your blacklist still wins over them.",
+	"preset.unsubscribed":    "✅ Unsubscribed from preset %s.",
+	"preset.not_subscribed":  "You are not subscribed to preset %s.",
+
+	// Blacklist
+	"blacklist.title":          "🚫 Blacklist:",
+	"blacklist.add_usage":      "Usage: /blacklist_add <family|project|glob|regex> <name> [until <YYYY-MM-DD>|for <duration>]\nBookings of matching projects are cancelled right away, even if they are whitelisted.\n\nExample:\n/blacklist_add family \"C - I\"\n/blacklist_add glob CPP*",
+	"blacklist.add_failed":     "Failed to add to blacklist: %v",
+	"blacklist.added":          "🚫 Added %s to your blacklist. Its bookings are cancelled right away.",
+	"blacklist.pattern_added":  "🚫 Added pattern %s to your blacklist. It is checked against project names and family labels.",
+	"blacklist.did_you_mean":   "Did you mean one of these? Tap to add it to your blacklist.",
+	"blacklist.no_suggestions": "Check the name with /whitelist_add, which lists the known families and projects.",
+	"blacklist.remove_usage":   "Usage: /blacklist_remove <name>\n\nExample: /blacklist_remove \"C - I\"",
+	"blacklist.remove_failed":  "Failed to remove from blacklist: %v",
+	"blacklist.removed":        "✅ Removed %s from your blacklist.",
+	"blacklist.not_found":      "%s is not in your blacklist.",
+
+	// Pause and quiet hours
+	"pause.usage":                   "%s\n\nUsage: /pause [until <YYYY-MM-DD>]",
+	"pause.invalid_args":            "Invalid arguments",
+	"pause.date_in_past":            "The date must be in the future",
+	"pause.failed":                  "Failed to pause: %v",
+	"pause.paused":                  "⏸️ Paused %s.\n\nNew bookings: %s\n\nUse /resume to resume earlier.",
+	"pause.until":                   "until %s",
+	"pause.until_resume":            "until /resume",
+	"pause.state_paused":            "Yes (%s)",
+	"pause.ended":                   "▶️ *Pause Ended*\n\nReview requests are handled as usual again.",
+	"resume.failed":                 "Failed to resume: %v",
+	"resume.resumed":                "▶️ Resumed. Queued reviews are handled on the next run.",
+	"limit_action.usage":            "Usage: /set_limit_action <ask|decline>\n\nask - send bookings that break a cap or the minimum gap for approval\ndecline - cancel them right away",
+	"limit_action.ask":              "Ask me",
+	"limit_action.decline":          "Decline",
+	"pause_policy.usage":            "Usage: /set_pause_policy <decline|whitelisted|queue>\n\ndecline - cancel every new booking\nwhitelisted - keep whitelisted bookings, cancel the rest\nqueue - queue them until you resume",
+	"pause_policy.decline":          "cancel every new booking",
+	"pause_policy.whitelisted_only": "keep whitelisted bookings, cancel the rest",
+	"pause_policy.queue":            "queue them until you resume",
+	"quiet.usage":                   "%s\n\nUsage: /set_quiet_hours <HH:MM-HH:MM|off>\nExample: /set_quiet_hours 23:00-07:00",
+	"quiet.invalid":                 "Invalid quiet hours",
+	"quiet.same_bounds":             "Quiet hours must not start and end at the same time",
+	"quiet.updated":                 "✅ Quiet hours set to %s",
+	"quiet.off":                     "Off",
+	"working_hours.usage":           "%s\n\nUsage: /set_working_hours <days HH:MM-HH:MM[; ...]|off>\nExample: /set_working_hours Mon-Fri 10:00-22:00; Sat 12:00-18:00",
+	"working_hours.current":         "🕘 Working hours: %s\n\nUsage: /set_working_hours <days HH:MM-HH:MM[; ...]|off>\nExample: /set_working_hours Mon-Fri 10:00-22:00; Sat 12:00-18:00\nDays without hours take no reviews; hours may run past midnight, e.g. Fri 20:00-01:00",
+	"working_hours.updated":         "✅ Working hours set to %s",
+	"working_hours.off":             "Off",
+	"outside_hours_action.usage":    "Usage: /set_outside_hours_action <ask|decline>\n\nask - send bookings you would keep for approval when they are outside your working hours\ndecline - cancel bookings outside your working hours right away",
+	"blocked_peer_action.usage":     "Usage: /set_blocked_peer_action <ask|decline>\n\nask - send bookings of students you blocked with /peers for approval\ndecline - cancel them right away",
+	"timeout_action.usage":          "Usage: /set_timeout_action <cancel|approve|whitelisted>\n\ncancel - cancel reviews you did not answer in time\napprove - approve them\nwhitelisted - approve them if the project is whitelisted, cancel the rest",
+	"timeout_action.cancel":         "Cancel",
+	"timeout_action.approve":        "Approve",
+	"timeout_action.whitelisted":    "Approve whitelisted",
+	"non_whitelist_action.usage":    "Usage: /set_non_whitelist_action <cancel|ask>\n\ncancel - cancel bookings of projects outside your whitelist after the cancel delay\nask - send them for approval and cancel them only if you do not answer in time, see /set_non_whitelist_deadline",
+	"non_whitelist_action.cancel":   "Cancel",
+	"non_whitelist_action.ask":      "Ask",
+
+	// Timezone and language
+	"timezone.current":       "Your timezone is %s (now %s).\n\nUsage: /set_timezone <zone|auto>\nExample: /set_timezone Europe/Moscow\nauto uses the timezone of your campus",
+	"timezone.detect_failed": "Failed to detect your campus. Please set the timezone explicitly, e.g. /set_timezone Europe/Moscow",
+	"timezone.usage":         "%s\n\nUsage: /set_timezone <zone|auto>",
+	"timezone.updated":       "✅ Timezone set to %s (now %s)",
+	"timezone.invalid":       "Invalid timezone %s, use an IANA name like Europe/Moscow",
+	"language.name":          "English",
+	"language.current":       "Your language is %s.\n\nUsage: /language <en|ru>",
+	"language.usage":         "Usage: /language <en|ru>",
+	"language.updated":       "✅ Language set to %s",
+
+	// Availability
+	"availability.usage":           "Usage:\n/availability - Show your availability\n/availability add <days> <HH:MM-HH:MM> - Add a weekly window, e.g. Mon-Thu 19:00-21:00\n/availability remove <number> - Remove a window\n/availability holiday <add|remove> <YYYY-MM-DD> - Skip or restore a date",
+	"availability.invalid":         "Invalid availability: %v\n\n%s",
+	"availability.failed":          "Failed to retrieve availability.",
+	"availability.add_failed":      "Failed to add availability: %v",
+	"availability.added":           "✅ Added availability %s %s",
+	"availability.remove_usage":    "Usage: /availability remove <number>\n\nUse /availability to see the numbers.",
+	"availability.not_found":       "No availability with number %d.",
+	"availability.remove_failed":   "Failed to remove availability: %v",
+	"availability.removed":         "✅ Removed availability %s %s",
+	"availability.holiday_usage":   "Usage: /availability holiday <add|remove> <YYYY-MM-DD>",
+	"availability.holiday_failed":  "Failed to update holidays: %v",
+	"availability.holiday_updated": "✅ Holidays updated for %s",
+	"availability.empty":           "You have no availability set.\n\n%s",
+	"availability.holidays_failed": "Failed to retrieve holidays.",
+	"availability.title":           "*Your Availability* (%s)",
+	"availability.holidays":        "🏖️ Holidays:",
+
+	// Day and time input
+	"input.expected_window": "Expected days and a time range, e.g. %s",
+	"input.unknown_day":     "Unknown day %s",
+	"input.no_days":         "No days given",
+	"input.day_twice":       "%s is given twice",
+	"input.expected_range":  "Expected a time range like %s",
+	"input.range_order":     "The end time must be after the start time",
+	"input.granularity":     "Times must be multiples of %d minutes",
+	"input.invalid_time":    "Invalid time %s, expected HH:MM",
+	"input.unknown_date":    "Unknown day %s, use today, tomorrow, a day name or YYYY-MM-DD",
+	"input.expected_slot":   "Expected a day and a time range, e.g. tomorrow 19:00-21:00",
+
+	// Slots
+	"slots.usage":           "Usage: /slots [days]\n\nDays must be between 1 and %d",
+	"slots.failed":          "Failed to retrieve calendar slots.",
+	"slots.empty":           "No slots in the next %s.\n\nUse /openslot <day> <HH:MM-HH:MM> to open one.",
+	"slots.title":           "*Your Slots* (next %s, %s)",
+	"slots.free":            "%d. 🟢 %s free",
+	"slots.booked":          "%d. 📌 %s booked: %s",
+	"slots.review":          "review",
+	"slots.close_button":    "❌ Close %d",
+	"slots.extend_button":   "➕ Extend %d",
+	"slots.calendar_failed": "Failed to get calendar: %v",
+	"slots.not_free":        "Slot is no longer free",
+	"slots.close_failed":    "Failed to close slot: %v",
+	"slots.closed":          "Slot closed",
+	"slots.extend_conflict": "Cannot extend: the next slot starts at %s",
+	"slots.extend_failed":   "Failed to extend slot: %v",
+	"slots.extended":        "Slot extended to %s",
+	"openslot.invalid":      "Invalid slot: %v\n\nUsage: /openslot <day> <HH:MM-HH:MM>\nExample: /openslot tomorrow 19:00-21:00",
+	"openslot.rejected":     "Cannot open slot: %v",
+	"openslot.failed":       "Failed to open slot: %v",
+	"openslot.opened":       "✅ Opened slot %s - %s",
+	"openslot.too_short":    "The slot must be at least %d minutes long",
+	"openslot.too_soon":     "The slot must start at least %d minutes from now",
+	"openslot.overlap":      "The slot overlaps an existing slot (%s - %s)",
+
+	// Status
+	"status.failed":          "Failed to retrieve status.",
+	"status.summary":         "*Status*\n\nUser: %s\nActive Reviews: %d",
+	"status.recent":          "Recent Reviews:",
+	"status.review":          "- %s at %s",
+	"status.unknown_project": "Unknown",
+
+	// Reviews
+	"review.unknown_project":   "Unknown Project",
+	"review.approved":          "✅ *Review Approved*\n\nProject: %s\nTime: %s",
+	"review.approved_answer":   "Review approved!",
+	"review.cancelled":         "❌ *Review Cancelled*\n\nProject: %s\nTime: %s",
+	"review.cancelled_answer":  "Review cancelled",
+	"review.approve_button":    "✅ Approve",
+	"review.decline_button":    "❌ Decline",
+	"notify.review_request":    "*Review Request*\n\nProject: %s\nTime: %s\n\nPlease respond by %s.\n\nUse the buttons below to approve or decline.",
+	"notify.review_request_by": "*Review Request*\n\nProject: %s\nReviewee: %s\nTime: %s\n\nPlease respond by %s.\n\nUse the buttons below to approve or decline.",
+	"notify.non_whitelist":     "❌ *Review Auto-Cancelled*\n\nProject: %s\nTime: %s\n\nThis project is not in your whitelist and was automatically cancelled.",
+	"notify.non_whitelist_ask": "*Review Request*\n\nProject: %s\nTime: %s\n\nThis project is not in your whitelist. It will be cancelled unless you approve it within %s, by %s.\n\nUse the buttons below to approve or decline.",
+	"notify.unasked":           "❌ *Review Auto-Cancelled*\n\nProject: %s\nTime: %s\n\nThis project is not in your whitelist. You were not asked about it while paused or in quiet hours, and the review starts before you could be, so it was cancelled.",
+	"notify.blacklisted":       "🚫 *Review Auto-Cancelled*\n\nProject: %s\nTime: %s\n\nThis project is in your blacklist and was cancelled right away.",
+	"notify.policy_declined":   "🚫 *Review Auto-Cancelled*\n\nProject: %s\nTime: %s\n\nYour decision policy %s declined this booking.",
+	"notify.limit_declined":    "🚫 *Review Auto-Cancelled*\n\nProject: %s\nTime: %s\n\n%s",
+	"notify.peer_declined":     "🚫 *Review Auto-Cancelled*\n\nProject: %s\nTime: %s\n\nYou blocked %s, so the booking was cancelled right away.",
+	"notify.limit_ask":         "🚦 *Review Needs Approval*\n\nProject: %s\nTime: %s\n\n%s\n\nApprove or decline it in the request that follows.",
+	"limit.daily_cap":          "⚠️ Your daily cap of %s is reached on that day.",
+	"limit.weekly_cap":         "⚠️ Your weekly cap of %s is reached in that week.",
+	"limit.working_hours":      "⚠️ It starts outside your working hours (%s).",
+	"limit.min_gap":            "⚠️ It leaves a break of less than %s before or after your review at %s.",
+	"notify.projects_synced":   "🎓 *Completed Projects Whitelisted*\n\nYou have completed %s, so they were added to your whitelist. Turn this off with /set_sync_completed no.",
+	"notify.whitelist_expired": "⌛ *Whitelist Entry Expired*\n\nThe temporary entry %s was removed from your whitelist.",
+	"notify.blacklist_expired": "⌛ *Blacklist Entry Expired*\n\nThe temporary entry %s was removed from your blacklist.",
+	"notify.whitelist_timeout": "⏰ *Review Timeout*\n\nProject: %s\nTime: %s\n\nYou did not respond in time and this review was automatically cancelled.",
+	"notify.timeout_approved":  "⏰ *Review Timeout*\n\nProject: %s\nTime: %s\n\nYou did not respond in time and this review was automatically approved.",
+
+	// Suggestions
+	"suggest.whitelist":      "💡 *Whitelist Suggestion*\n\nYou approved %d of %d reviews of %s by hand. Add it to your whitelist so they are approved for you?",
+	"suggest.blacklist":      "💡 *Blacklist Suggestion*\n\nYou declined %d of %d reviews of %s by hand. Add it to your blacklist so they are cancelled right away?",
+	"suggest.add_whitelist":  "✅ Add to whitelist",
+	"suggest.add_blacklist":  "🚫 Add to blacklist",
+	"suggest.dismiss":        "Don't suggest again",
+	"suggest.dismissed":      "OK, no more suggestions about %s.",
+	"suggest.dismiss_failed": "Failed to dismiss the suggestion: %v",
+
+	// Help
+	"help.step":                        "step %d",
+	"help.arg_reviews":                 "<reviews>",
+	"help.arg_minutes":                 "<minutes>",
+	"help.arg_hours":                   "<hours>",
+	"help.arg_days":                    "<days>",
+	"help.deadline_shift":              "Response deadline shift",
+	"help.cancel_delay":                "Non-whitelist cancel delay",
+	"help.notify_whitelist_timeout":    "Notify on whitelist timeout",
+	"help.notify_non_whitelist_cancel": "Notify on non-whitelist cancel",
+	"help.slot_shift_threshold":        "Slot shift threshold",
+	"help.slot_shift_duration":         "Slot shift duration",
+	"help.cleanup_duration":            "Cleanup duration",
+	"help.slot_housekeeping":           "Tidy partially booked slots",
+	"help.housekeeping_buffer":         "Free time kept next to bookings",
+	"help.availability_days":           "How far ahead availability slots are opened",
+	"help.daily_cap":                   "Most reviews per day, 0 for no cap",
+	"help.weekly_cap":                  "Most reviews per week, 0 for no cap",
+	"help.min_gap":                     "Fewest minutes between the end of one review and the start of the next, 0 for no gap",
+	"help.limit_action":                "What happens to bookings that break a limit",
+	"help.outside_hours_action":        "What happens to bookings outside your working hours",
+	"help.blocked_peer_action":         "What happens to bookings of blocked students",
+	"help.timeout_action":              "What happens to reviews you do not answer in time",
+	"help.non_whitelist_action":        "What happens to bookings of projects outside your whitelist",
+	"help.non_whitelist_deadline":      "How long you have to answer about a booking outside your whitelist",
+	"help.lookahead":                   "How far ahead new bookings are picked up",
+	"help.lookback":                    "How far back new bookings are picked up",
+	"help.sync_completed":              "Whitelist the projects you have completed",
+	"help.pause_policy":                "What happens to new bookings while paused",
+	"help.language":                    "Change the bot language",
+	"help.text": `*Review Slot Guard Bot*
+
+This bot helps you manage your review slots for School 21.
+
+*Commands:*
+
+/start - Start authentication
+/logout - Log out from the bot
+/status - Show your current status and active reviews
+/settings - View and change your settings
+/whitelist - Show your whitelist and blacklist
+/availability - Show your weekly availability
+/pause [until <YYYY-MM-DD>] - Pause approval requests, e.g. for a vacation
+/resume - Resume approval requests
+/slots [days] - Show upcoming calendar slots
+
+*Whitelist Management:*
+/whitelist_add [<family|project> <name> [for 3d]] - Add to whitelist, optionally for a while, or browse families without arguments
+/whitelist_remove <name> - Remove from whitelist
+/whitelist_test <project> - Show which whitelist entry accepts a project
+/blacklist_add <family|project|glob|regex> <name> - Always cancel bookings of a project right away
+/blacklist_remove <name> - Remove from blacklist
+/preset [show|subscribe|unsubscribe <name>] - Browse and subscribe to shared whitelist presets
+/preset <create|delete> <name> - Create or delete a preset
+/preset <add|remove> <name> ... - Change the entries of your preset
+
+*Slots:*
+/openslot <day> <HH:MM-HH:MM> - Open a slot, e.g. tomorrow 19:00-21:00
+
+*Availability:*
+/availability add <days> <HH:MM-HH:MM> - Open slots weekly, e.g. Mon-Thu 19:00-21:00
+/availability remove <number> - Remove a weekly window
+/availability holiday <add|remove> <YYYY-MM-DD> - Skip or restore a date
+
+*Settings:*
+%s
+/override <family|project> <name> <setting> <value> - Use another value for one family or project, see /override
+/rule [add|remove|test] - Decide on bookings with your own conditions, see /rule
+/peers [trust|block|remove <login...>] - Keep bookings of trusted students, hold back blocked ones
+/policies [policy...] - Order the policies that decide on bookings
+/set_quiet_hours <HH:MM-HH:MM|off> - Hold messages and timeouts at night
+/set_working_hours <days HH:MM-HH:MM|off> - Hours you take reviews in, e.g. Mon-Fri 10:00-22:00
+/set_timezone <zone|auto> - Your timezone, e.g. Europe/Moscow; auto uses your campus
+
+Times are shown and entered in your timezone.`,
+}
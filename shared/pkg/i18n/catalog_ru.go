@@ -0,0 +1,536 @@
+package i18n
+
+// ru is the Russian catalog. Plural messages have "one", "few" and "many" forms
+var ru = map[string]string{
+	// Common
+	"common.user_not_found": "Пользователь не найден. Используйте /start для авторизации.",
+	"common.update_failed":  "Не удалось обновить настройку: %v",
+	"common.status_failed":  "Не удалось обновить статус: %v",
+	"common.tokens_failed":  "Не удалось получить токены: %v",
+	"common.unknown_action": "Неизвестное действие",
+	"common.yes":            "Да",
+	"common.no":             "Нет",
+	"common.invalid_date":   "Неверная дата. Используйте формат ГГГГ-ММ-ДД, например %s",
+
+	// Units
+	"unit.minutes.one":   "%d минута",
+	"unit.minutes.few":   "%d минуты",
+	"unit.minutes.many":  "%d минут",
+	"unit.hours.one":     "%d час",
+	"unit.hours.few":     "%d часа",
+	"unit.hours.many":    "%d часов",
+	"unit.days.one":      "%d день",
+	"unit.days.few":      "%d дня",
+	"unit.days.many":     "%d дней",
+	"unit.reviews.one":   "%d ревью",
+	"unit.reviews.few":   "%d ревью",
+	"unit.reviews.many":  "%d ревью",
+	"unit.projects.one":  "%d проект",
+	"unit.projects.few":  "%d проекта",
+	"unit.projects.many": "%d проектов",
+	"unit.entries.one":   "%d запись",
+	"unit.entries.few":   "%d записи",
+	"unit.entries.many":  "%d записей",
+
+	// Dates
+	"time.short": "%[2]d %[1]s %[3]s",
+	"time.day":   "%[1]s, %[3]d %[2]s",
+	"month.1":    "янв",
+	"month.2":    "фев",
+	"month.3":    "мар",
+	"month.4":    "апр",
+	"month.5":    "мая",
+	"month.6":    "июн",
+	"month.7":    "июл",
+	"month.8":    "авг",
+	"month.9":    "сен",
+	"month.10":   "окт",
+	"month.11":   "ноя",
+	"month.12":   "дек",
+	"weekday.0":  "Вс",
+	"weekday.1":  "Пн",
+	"weekday.2":  "Вт",
+	"weekday.3":  "Ср",
+	"weekday.4":  "Чт",
+	"weekday.5":  "Пт",
+	"weekday.6":  "Сб",
+
+	// Authentication
+	"start.welcome_back":        "С возвращением, %s! Вы уже авторизованы.",
+	"start.prompt":              "Для авторизации отправьте данные от School 21 в формате:\n\n`login:password`\n\nДанные надёжно хранятся в YDB.",
+	"auth.invalid_format":       "Неверный формат. Отправьте данные в формате:\n\n`login:password`",
+	"auth.already":              "Вы уже авторизованы как %s.\n\nИспользуйте /logout, чтобы авторизоваться заново.",
+	"auth.failed":               "Не удалось авторизоваться. Проверьте данные и попробуйте ещё раз.",
+	"auth.tokens_failed":        "Авторизация прошла, но токены не удалось сохранить. Обратитесь в поддержку.",
+	"auth.user_failed":          "Авторизация прошла, но пользователя не удалось создать. Обратитесь в поддержку.",
+	"auth.success":              "✅ Вы авторизованы как %s!\n\nТеперь можно пользоваться ботом. Список команд — /help.",
+	"logout.not_authenticated":  "Вы не авторизованы.",
+	"logout.success":            "✅ Вы вышли. Авторизоваться снова можно через /start.",
+	"command.unknown":           "Неизвестная команда: %s\n\nСписок команд — /help.",
+	"callback.invalid":          "Неверные данные кнопки",
+	"callback.review_not_found": "Запрос на ревью не найден",
+	"callback.access_denied":    "Доступ запрещён",
+
+	// Settings
+	"settings.failed":                      "Не удалось получить настройки.",
+	"settings.title":                       "*Ваши настройки*",
+	"settings.deadline_shift":              "📅 Сдвиг срока ответа: %s",
+	"settings.cancel_delay":                "⏱️ Задержка отмены вне белого списка: %s",
+	"settings.notify_whitelist_timeout":    "🔔 Уведомлять об истечении срока: %s",
+	"settings.notify_non_whitelist_cancel": "🔔 Уведомлять об отмене вне белого списка: %s",
+	"settings.slot_shift_threshold":        "🔄 Порог сдвига слота: %s",
+	"settings.slot_shift_duration":         "⬇️ Длительность сдвига слота: %s",
+	"settings.cleanup_duration":            "🧹 Длительность очистки: %s",
+	"settings.housekeeping":                "✂️ Уборка слотов: %s",
+	"settings.housekeeping_buffer":         "↔️ Буфер уборки: %s",
+	"settings.availability_days":           "🗓️ Доступность на дней вперёд: %s",
+	"settings.lookahead":                   "🔭 Горизонт бронирований: %s",
+	"settings.lookback":                    "⏪ Просмотр назад: %s",
+	"settings.sync_completed":              "🎓 Белый список из сданных проектов: %s",
+	"settings.paused":                      "⏸️ Пауза: %s",
+	"settings.pause_policy":                "📥 Политика паузы: %s",
+	"settings.quiet_hours":                 "🌙 Тихие часы: %s",
+	"settings.timezone":                    "🌍 Часовой пояс: %s",
+	"settings.daily_cap":                   "📆 Лимит ревью в день: %s",
+	"settings.weekly_cap":                  "🗓️ Лимит ревью в неделю: %s",
+	"settings.min_gap":                     "↔️ Минимальный перерыв между ревью: %s",
+	"settings.limit_action":                "🚦 При превышении лимита: %s",
+	"settings.working_hours":               "🕘 Рабочие часы: %s",
+	"settings.outside_hours_action":        "🌃 Вне рабочих часов: %s",
+	"settings.blocked_peer_action":         "🙅 Заблокированные студенты: %s",
+	"settings.timeout_action":              "⏰ Без ответа: %s",
+	"settings.non_whitelist_action":        "🚫 Бронирования вне белого списка: %s",
+	"settings.non_whitelist_deadline":      "⏳ Время ответа вне белого списка: %s",
+	"settings.off":                         "Выкл",
+	"settings.language":                    "🗣️ Язык: %s",
+	"settings.policies":                    "⚖️ Политики решений: %s",
+	"settings.overrides":                   "🎯 Переопределения:",
+	"settings.menu_hint":                   "Нажмите на настройку, чтобы изменить её. Пауза, тихие часы, рабочие часы, часовой пояс и политики решений меняются командами /pause, /set_quiet_hours, /set_working_hours, /set_timezone и /policies.",
+	"settings.menu_range":                  "Допустимо: %d - %d",
+	"settings.menu_pick":                   "Выберите значение:",
+	"settings.menu_back":                   "« Назад",
+	"settings.menu_saved":                  "✅ Сохранено",
+	"setting.numeric_usage":                "Использование: /%s <значение>\n\nДопустимо: %d - %d (шаг %d)",
+	"setting.out_of_range":                 "Значение должно быть от %d до %d",
+	"setting.values_usage":                 "Использование: /%s <значение>\n\nДопустимые значения: %s",
+	"setting.not_allowed":                  "Неверное значение. Допустимые значения: %s",
+	"setting.bool_usage":                   "Использование: /%s <on|off>",
+	"setting.step":                         "Значение должно быть кратно %d",
+	"setting.rule_threshold_deadline":      "Порог сдвига слота (%d мин) должен быть больше сдвига срока ответа (%d мин)",
+	"setting.rule_daily_weekly":            "Дневной лимит (%d) не может быть больше недельного (%d)",
+	"setting.rule_duration_threshold":      "Длительность сдвига слота (%d мин) должна быть меньше порога сдвига (%d мин)",
+	"setting.updated":                      "✅ Сохранено. %s",
+	"setting.not_in_chain":                 "Политики %s нет в ваших политиках решений, поэтому настройка не действует, пока вы не добавите её через /policies.",
+	"housekeeping.usage":                   "Использование: /set_slot_housekeeping <off|trim|split>\n\noff - не трогать частично занятые слоты\ntrim - сжимать слоты до бронирований плюс буфер\nsplit - сохранять свободное время, оставляя перерыв размером с буфер вокруг бронирований",
+
+	// Whitelist
+	"whitelist.failed":                     "Не удалось получить белый список.",
+	"whitelist.empty":                      "Ваши белый и чёрный списки пусты.\n\nДобавьте проекты или семейства через /whitelist_add, а те, которые вы никогда не проверяете, - через /blacklist_add.",
+	"whitelist.title":                      "*Ваш белый список*",
+	"whitelist.families":                   "📁 Семейства:",
+	"whitelist.projects":                   "📦 Проекты:",
+	"whitelist.add_usage":                  "Использование: /whitelist_add <family|project|glob|regex> <название> [until <ГГГГ-ММ-ДД>|for <срок>]\nОтправьте /whitelist_add без аргументов, чтобы выбрать из известных семейств и проектов.\n\nПример:\n/whitelist_add family \"C - I\"\n/whitelist_add project \"go-concurrency\"\n/whitelist_add glob CPP*\n/whitelist_add regex ^go-\n/whitelist_add family \"C - I\" for 5d",
+	"whitelist.invalid_type":               "Неверный тип. Используйте 'family', 'project', 'glob' или 'regex'.",
+	"whitelist.add_failed":                 "Не удалось добавить в белый список: %v",
+	"whitelist.added":                      "✅ %s добавлен в белый список.",
+	"whitelist.remove_usage":               "Использование: /whitelist_remove <название>\n\nПример: /whitelist_remove \"C - I\"",
+	"whitelist.remove_failed":              "Не удалось удалить из белого списка: %v",
+	"whitelist.removed":                    "✅ %s удалён из белого списка.",
+	"whitelist.not_found":                  "%s нет в белом списке.",
+	"whitelist.browser_title":              "*Белый список*\n\nВыберите семейство, чтобы увидеть его проекты. ✅ отмечает семейства и проекты из белого списка.",
+	"whitelist.browser_family":             "*%s*\n\n%s. Нажмите на проект, чтобы добавить его в белый список или убрать.",
+	"whitelist.browser_family_whitelisted": "Всё семейство в белом списке, поэтому все его проекты принимаются.",
+	"whitelist.browser_whole_family":       "Всё семейство",
+	"whitelist.browser_back":               "« Семейства",
+	"whitelist.browser_empty":              "Список проектов ещё не загружен. Пока используйте /whitelist_add <family|project> <название>.",
+	"whitelist.browser_failed":             "Не удалось загрузить список проектов.",
+	"whitelist.browser_outdated":           "Список проектов изменился, отправьте /whitelist_add ещё раз.",
+	"whitelist.unknown_family":             "❓ Семейства %s не существует.",
+	"whitelist.unknown_project":            "❓ Проекта %s не существует.",
+	"whitelist.did_you_mean":               "Может быть, вы имели в виду один из этих вариантов? Нажмите, чтобы добавить.",
+	"whitelist.no_suggestions":             "Отправьте /whitelist_add без аргументов, чтобы выбрать из известных названий.",
+	"whitelist.patterns":                   "🔎 Шаблоны:",
+	"whitelist.synced":                     "🎓 Сданные проекты (обновляются сами):",
+	"whitelist.pattern_empty":              "Шаблон пуст.",
+	"whitelist.pattern_too_long":           "Шаблон слишком длинный, предел - %d символов.",
+	"whitelist.invalid_glob":               "%s - неверный glob-шаблон. Используйте * для любого текста, ? для одного символа и [abc] для одного из нескольких.",
+	"whitelist.invalid_regex":              "%s - неверное регулярное выражение: %v",
+	"whitelist.pattern_added":              "✅ Шаблон %s добавлен в белый список. Он проверяется по названиям проектов и семейств.",
+	"whitelist.pattern_matches":            "Сейчас под него подходит %s, например %s.",
+	"whitelist.pattern_matches_none":       "Пока под него не подходит ни один известный проект.",
+	"whitelist.test_usage":                 "Использование: /whitelist_test <проект>\n\nПример: /whitelist_test go-concurrency",
+	"whitelist.test_match":                 "✅ %s в белом списке.\n\nЗапись: %s\nСовпадение: %s",
+	"whitelist.test_blacklisted":           "🚫 %s в чёрном списке, его бронирования отменяются сразу.\n\nЗапись: %s\nСовпадение: %s",
+	"whitelist.test_no_match":              "❌ %s не в белом списке, ни одна запись не подходит ни к проекту, ни к его семейству.",
+	"whitelist.test_by_project":            "название проекта %s",
+	"whitelist.test_by_family":             "семейство %s",
+	"whitelist.test_family":                "Семейство: %s",
+	"whitelist.test_unknown_project":       "Этого проекта нет в списке известных проектов, поэтому его семейство неизвестно и записи семейств не проверялись.",
+	"whitelist.entry_family":               "семейство %s",
+	"whitelist.entry_project":              "проект %s",
+	"whitelist.entry_glob":                 "glob-шаблон %s",
+	"whitelist.entry_regex":                "регулярное выражение %s",
+	"whitelist.presets":                    "📚 Подписки на пресеты:",
+	"whitelist.test_preset":                "Пресет: %s",
+	"whitelist.expires":                    "Запись действует до %s.",
+	"whitelist.until":                      "до %s",
+	"whitelist.invalid_duration":           "%s - неверный срок. Укажите число часов, дней или недель, например 12h, 3d или 2w.",
+	"whitelist.expiry_in_past":             "Срок должен быть в будущем.",
+	"whitelist.expiry_too_far":             "Срок должен быть не больше %d дней.",
+
+	// Overrides
+	"override.usage":           "Использование:\n/override - список переопределений\n/override <family|project> <название> <настройка> <значение>\n/override remove <family|project> <название> [настройка]\n\nНастройки: %s\n\nПример:\n/override family \"C - I\" deadline_shift 45",
+	"override.failed":          "Не удалось загрузить переопределения настроек.",
+	"override.invalid_type":    "Переопределения задаются для 'family' или 'project'.",
+	"override.unknown_setting": "Эту настройку нельзя переопределить. Доступны: %s.",
+	"override.update_failed":   "Не удалось обновить переопределение: %v",
+	"override.set":             "✅ %s — %s",
+	"override.item":            "%s: %s",
+	"override.removed":         "✅ Переопределение %s удалено: %s.",
+	"override.removed_all":     "✅ Все переопределения удалены: %s.",
+	"override.not_found":       "Нет такого переопределения: %s.",
+	"override.list_empty":      "Переопределений нет, настройки действуют для всех проектов. Командой /override <family|project> <название> <настройка> <значение> можно изменить одну из настроек %s для отдельного семейства или проекта.",
+	"override.list_hint":       "Переопределение проекта важнее переопределения семейства, а оно важнее /settings. Удалить: /override remove <family|project> <название> [настройка].",
+
+	// Decision policies
+	"policies.list":          "⚖️ *Политики решений*\n\nВаша цепочка: %s\n\nДоступные политики:\n%s\n\nРешает первая политика, которая вынесла решение по бронированию. Политики рабочих часов и лимитов придерживают бронирования, которые оставила бы политика после них, поэтому если поставить их после whitelist, белый список их обходит. Бронирования, по которым не решила ни одна политика, отменяются после задержки отмены.\n\nИзменить порядок: /policies <политика> [политика...]\nВернуть по умолчанию: /policies reset",
+	"policies.updated":       "✅ Политики решений: %s",
+	"policies.reset":         "✅ Политики решений сброшены: %s.",
+	"policies.update_failed": "Не удалось обновить политики решений: %v",
+	"policies.unknown":       "Неизвестная политика %s. Доступны: %s.",
+	"policies.duplicate":     "Политика %s указана дважды.",
+	"policies.deny_first":    "Политика %s должна идти раньше %s, чтобы проект из обоих списков не оставался.",
+	"policy.deny_list":       "сразу отменять бронирования проектов из чёрного списка",
+	"policy.working_hours":   "спрашивать о бронированиях вне рабочих часов или отклонять их, см. /set_outside_hours_action",
+	"policy.daily_cap":       "придерживать бронирования сверх дневного лимита, см. /set_daily_cap",
+	"policy.weekly_cap":      "придерживать бронирования сверх недельного лимита, см. /set_weekly_cap",
+	"policy.min_gap":         "придерживать бронирования слишком близко к другому ревью, см. /set_min_gap",
+	"policy.peers":           "оставлять бронирования доверенных студентов, спрашивать или отклонять заблокированных",
+	"policy.rules":           "применять первое выполненное правило из /rule",
+	"policy.whitelist":       "оставлять бронирования проектов из белого списка",
+
+	// Decision rules
+	"rule.usage":         "Использование:\n/rule - Список правил\n/rule add <approve|ask|decline> <выражение>\n/rule remove <номер>\n/rule test <проект> <день> <ЧЧ:ММ>\n\nАтрибуты: %s\n\nПример:\n/rule add approve family == \"CPP\" && weekday in [1..4] && hour >= 18",
+	"rule.failed":        "Не удалось загрузить правила.",
+	"rule.invalid":       "Некорректное правило: %v",
+	"rule.add_failed":    "Не удалось добавить правило: %v",
+	"rule.added":         "✅ Правило добавлено: %s",
+	"rule.not_in_chain":  "Правил нет в ваших политиках решений, поэтому правило не действует, пока вы не добавите их через /policies.",
+	"rule.remove_usage":  "Использование: /rule remove <номер>\n\nНомера показывает /rule.",
+	"rule.not_found":     "Нет правила с номером %d.",
+	"rule.remove_failed": "Не удалось удалить правило: %v",
+	"rule.removed":       "✅ Правило удалено: %s",
+	"rule.item":          "%s: `%s`",
+	"rule.list_empty":    "Правил нет. Правила решают по бронированиям с условиями вроде\n`family == \"CPP\" && weekday in [1..4] && hour >= 18`\n\nДобавить: /rule add <approve|ask|decline> <выражение>. Атрибуты: %s.",
+	"rule.list_title":    "📐 *Ваши правила*",
+	"rule.list_hint":     "Решает первое выполненное правило. Атрибуты: %s. Проверить бронирование: /rule test <проект> <день> <ЧЧ:ММ>.",
+	"rule.test_usage":    "Использование: /rule test <проект> <день> <ЧЧ:ММ>\n\nПример: /rule test CPP1_s21_matrix+ tomorrow 19:00",
+	"rule.test_invalid":  "Некорректное время бронирования: %v",
+	"rule.test_title":    "🧪 *Бронирование на %s*\n\n%s",
+	"rule.test_no_rules": "Правил пока нет.",
+	"rule.test_error":    "(%v)",
+	"rule.test_none":     "Ни одно правило не выполнено, решает следующая политика.",
+	"rule.test_decision": "Решение: %s",
+
+	// Rule expression errors
+	"rule_error.empty":           "Правило пустое",
+	"rule_error.too_long":        "Правило длиннее %d символов",
+	"rule_error.not_condition":   "Правило должно быть условием, например hour >= 18",
+	"rule_error.timeout":         "Проверка правила заняла слишком много времени",
+	"rule_error.unterminated":    "Незакрытый текст в позиции %d",
+	"rule_error.unexpected":      "Неожиданное \"%s\" в позиции %d",
+	"rule_error.expected":        "Ожидалось \"%s\" в позиции %d",
+	"rule_error.expected_end":    "Ожидалось \"%s\" в конце",
+	"rule_error.ends_early":      "Правило обрывается слишком рано",
+	"rule_error.not_needs":       "\"!\" в позиции %d нужно условие, а не %s",
+	"rule_error.matches_pattern": "\"matches\" в позиции %d нужен шаблон в кавычках, например project matches \"CPP*\"",
+	"rule_error.matches_text":    "\"matches\" в позиции %d нужен текст, а не %s",
+	"rule_error.bad_pattern":     "Некорректный шаблон \"%s\" в позиции %d",
+	"rule_error.range":           "Диапазону в позиции %d нужны числа, например 1..4",
+	"rule_error.list_kind":       "Элемент списка в позиции %d - %s, а сравнивается с ним %s",
+	"rule_error.too_large":       "Число %s в позиции %d слишком большое",
+	"rule_error.unknown_attr":    "Неизвестный атрибут \"%s\" в позиции %d, используйте один из: %s",
+	"rule_error.joins":           "\"%s\" в позиции %d объединяет условия, например hour >= 18 && weekday <= 5",
+	"rule_error.compare":         "Нельзя сравнить %s и %s в позиции %d",
+	"rule_error.numbers_only":    "\"%s\" в позиции %d сравнивает числа, а не %s",
+	"rule_error.kind_number":     "число",
+	"rule_error.kind_text":       "текст",
+	"rule_error.kind_bool":       "условие",
+
+	// Peers
+	"peers.usage":         "Использование:\n/peers - Список студентов\n/peers trust <логин...> - Оставлять бронирования этих студентов\n/peers block <логин...> - Спрашивать или отклонять их бронирования, см. /set_blocked_peer_action\n/peers remove <логин...>",
+	"peers.failed":        "Не удалось загрузить список студентов.",
+	"peers.invalid_login": "%s - некорректный логин.",
+	"peers.update_failed": "Не удалось обновить список студентов: %v",
+	"peers.trusted":       "✅ Доверенные: %s. Их бронирования остаются.",
+	"peers.blocked":       "🚫 Заблокированы: %s. Их бронирования обрабатываются по /set_blocked_peer_action.",
+	"peers.removed":       "✅ %s удалены из списка студентов.",
+	"peers.not_in_chain":  "Студентов нет в ваших политиках решений, поэтому списки не действуют, пока вы не добавите их через /policies.",
+	"peers.list_empty":    "Список студентов пуст. Доверяйте одногруппникам через /peers trust <логин>, чтобы оставлять их бронирования, или блокируйте студентов через /peers block <логин>.",
+	"peers.list_title":    "👥 *Ваши студенты*",
+	"peers.list_trusted":  "Доверенные: %s",
+	"peers.list_blocked":  "Заблокированные: %s",
+	"peers.list_none":     "нет",
+	"peers.list_hint":     "Бронирования доверенных студентов остаются, по бронированиям заблокированных бот спрашивает или отклоняет их, см. /set_blocked_peer_action. Команда, в которой есть не доверенный студент, остаётся следующей политике.",
+
+	// Presets
+	"preset.usage":           "Использование:\n/preset - список пресетов\n/preset show <название>\n/preset subscribe <название>\n/preset unsubscribe <название>\n/preset create <название>\n/preset add <название> <family|project|glob|regex> <запись>\n/preset remove <название> <запись>\n/preset delete <название>",
+	"preset.failed":          "Не удалось загрузить пресеты.",
+	"preset.list_title":      "*Пресеты белого списка*",
+	"preset.list_item":       "%s - %s, автор %s",
+	"preset.list_hint":       "✅ отмечает пресеты, на которые вы подписаны. Записи пресета покажет /preset show <название>.",
+	"preset.list_empty":      "Пресетов пока нет. Создайте первый через /preset create <название>.",
+	"preset.show_title":      "*Пресет %s*\nАвтор: %s",
+	"preset.show_empty":      "В пресете пока нет записей.",
+	"preset.invalid_name":    "Название пресета может содержать только латинские буквы, цифры, - и _, не длиннее %d символов.",
+	"preset.not_found":       "Пресета %s не существует.",
+	"preset.exists":          "Пресет %s уже существует.",
+	"preset.created":         "✅ Пресет %s создан. Добавьте записи через /preset add %s <family|project|glob|regex> <запись>.",
+	"preset.forbidden":       "Изменять пресет %s может только его автор или администратор.",
+	"preset.update_failed":   "Не удалось изменить пресет: %v",
+	"preset.entry_added":     "✅ %s добавлен в пресет %s.",
+	"preset.entry_removed":   "✅ %s удалён из пресета %s.",
+	"preset.entry_not_found": "%s нет в пресете %s.",
+	"preset.did_you_mean":    "Может быть, вы имели в виду %s?",
+	"preset.deleted":         "✅ Пресет %s удалён.",
+	"preset.subscribed":      "✅ Вы подписались на пресет %s. Его записи действуют вместе с вашим белым списком, а ваш чёрный список по-прежнему важнее.",
+	"preset.unsubscribed":    "✅ Вы отписались от пресета %s.",
+	"preset.not_subscribed":  "Вы не подписаны на пресет %s.",
+
+	// Blacklist
+	"blacklist.title":          "🚫 Чёрный список:",
+	"blacklist.add_usage":      "Использование: /blacklist_add <family|project|glob|regex> <название> [until <ГГГГ-ММ-ДД>|for <срок>]\nБронирования подходящих проектов отменяются сразу, даже если они в белом списке.\n\nПример:\n/blacklist_add family \"C - I\"\n/blacklist_add glob CPP*",
+	"blacklist.add_failed":     "Не удалось добавить в чёрный список: %v",
+	"blacklist.added":          "🚫 %s добавлен в чёрный список. Его бронирования отменяются сразу.",
+	"blacklist.pattern_added":  "🚫 Шаблон %s добавлен в чёрный список. Он проверяется по названиям проектов и семейств.",
+	"blacklist.did_you_mean":   "Может быть, вы имели в виду один из этих вариантов? Нажмите, чтобы добавить в чёрный список.",
+	"blacklist.no_suggestions": "Проверьте название через /whitelist_add - там есть список известных семейств и проектов.",
+	"blacklist.remove_usage":   "Использование: /blacklist_remove <название>\n\nПример: /blacklist_remove \"C - I\"",
+	"blacklist.remove_failed":  "Не удалось удалить из чёрного списка: %v",
+	"blacklist.removed":        "✅ %s удалён из чёрного списка.",
+	"blacklist.not_found":      "%s нет в чёрном списке.",
+
+	// Pause and quiet hours
+	"pause.usage":                   "%s\n\nИспользование: /pause [until <ГГГГ-ММ-ДД>]",
+	"pause.invalid_args":            "Неверные аргументы",
+	"pause.date_in_past":            "Дата должна быть в будущем",
+	"pause.failed":                  "Не удалось поставить на паузу: %v",
+	"pause.paused":                  "⏸️ Пауза %s.\n\nНовые бронирования: %s\n\nИспользуйте /resume, чтобы продолжить раньше.",
+	"pause.until":                   "до %s",
+	"pause.until_resume":            "до /resume",
+	"pause.state_paused":            "Да (%s)",
+	"pause.ended":                   "▶️ *Пауза закончилась*\n\nЗапросы на ревью снова обрабатываются как обычно.",
+	"resume.failed":                 "Не удалось снять паузу: %v",
+	"resume.resumed":                "▶️ Пауза снята. Отложенные ревью обработаются при следующем запуске.",
+	"limit_action.usage":            "Использование: /set_limit_action <ask|decline>\n\nask - отправлять на подтверждение бронирования сверх лимита или с маленьким перерывом\ndecline - сразу их отменять",
+	"limit_action.ask":              "Спрашивать",
+	"limit_action.decline":          "Отклонять",
+	"pause_policy.usage":            "Использование: /set_pause_policy <decline|whitelisted|queue>\n\ndecline - отменять все новые бронирования\nwhitelisted - оставлять бронирования из белого списка, остальные отменять\nqueue - откладывать до снятия паузы",
+	"pause_policy.decline":          "отменять все новые бронирования",
+	"pause_policy.whitelisted_only": "оставлять бронирования из белого списка, остальные отменять",
+	"pause_policy.queue":            "откладывать до снятия паузы",
+	"quiet.usage":                   "%s\n\nИспользование: /set_quiet_hours <ЧЧ:ММ-ЧЧ:ММ|off>\nПример: /set_quiet_hours 23:00-07:00",
+	"quiet.invalid":                 "Неверные тихие часы",
+	"quiet.same_bounds":             "Тихие часы не могут начинаться и заканчиваться в одно время",
+	"quiet.updated":                 "✅ Тихие часы: %s",
+	"quiet.off":                     "Выкл",
+	"working_hours.usage":           "%s\n\nИспользование: /set_working_hours <дни ЧЧ:ММ-ЧЧ:ММ[; ...]|off>\nПример: /set_working_hours Mon-Fri 10:00-22:00; Sat 12:00-18:00",
+	"working_hours.current":         "🕘 Рабочие часы: %s\n\nИспользование: /set_working_hours <дни ЧЧ:ММ-ЧЧ:ММ[; ...]|off>\nПример: /set_working_hours Mon-Fri 10:00-22:00; Sat 12:00-18:00\nВ дни без часов ревью не принимаются; часы могут переходить через полночь, например Fri 20:00-01:00",
+	"working_hours.updated":         "✅ Рабочие часы: %s",
+	"working_hours.off":             "Выкл",
+	"outside_hours_action.usage":    "Использование: /set_outside_hours_action <ask|decline>\n\nask - отправлять на подтверждение бронирования вне рабочих часов, которые иначе были бы оставлены\ndecline - сразу отменять бронирования вне рабочих часов",
+	"blocked_peer_action.usage":     "Использование: /set_blocked_peer_action <ask|decline>\n\nask - отправлять на подтверждение бронирования студентов, заблокированных через /peers\ndecline - сразу их отменять",
+	"timeout_action.usage":          "Использование: /set_timeout_action <cancel|approve|whitelisted>\n\ncancel - отменять ревью, на которые вы не ответили вовремя\napprove - подтверждать их\nwhitelisted - подтверждать, если проект в белом списке, остальные отменять",
+	"timeout_action.cancel":         "Отменять",
+	"timeout_action.approve":        "Подтверждать",
+	"timeout_action.whitelisted":    "Подтверждать из белого списка",
+	"non_whitelist_action.usage":    "Использование: /set_non_whitelist_action <cancel|ask>\n\ncancel - отменять бронирования проектов вне белого списка после задержки отмены\nask - отправлять их на подтверждение и отменять, только если вы не ответили вовремя, см. /set_non_whitelist_deadline",
+	"non_whitelist_action.cancel":   "Отменять",
+	"non_whitelist_action.ask":      "Спрашивать",
+
+	// Timezone and language
+	"timezone.current":       "Ваш часовой пояс: %s (сейчас %s).\n\nИспользование: /set_timezone <пояс|auto>\nПример: /set_timezone Europe/Moscow\nauto берёт часовой пояс вашего кампуса",
+	"timezone.detect_failed": "Не удалось определить кампус. Укажите часовой пояс явно, например /set_timezone Europe/Moscow",
+	"timezone.usage":         "%s\n\nИспользование: /set_timezone <пояс|auto>",
+	"timezone.updated":       "✅ Часовой пояс: %s (сейчас %s)",
+	"timezone.invalid":       "Неверный часовой пояс %s, используйте имя IANA, например Europe/Moscow",
+	"language.name":          "Русский",
+	"language.current":       "Ваш язык: %s.\n\nИспользование: /language <en|ru>",
+	"language.usage":         "Использование: /language <en|ru>",
+	"language.updated":       "✅ Язык: %s",
+
+	// Availability
+	"availability.usage":           "Использование:\n/availability - Показать доступность\n/availability add <дни> <ЧЧ:ММ-ЧЧ:ММ> - Добавить еженедельное окно, например Mon-Thu 19:00-21:00\n/availability remove <номер> - Удалить окно\n/availability holiday <add|remove> <ГГГГ-ММ-ДД> - Пропустить или вернуть дату",
+	"availability.invalid":         "Неверная доступность: %v\n\n%s",
+	"availability.failed":          "Не удалось получить доступность.",
+	"availability.add_failed":      "Не удалось добавить доступность: %v",
+	"availability.added":           "✅ Добавлена доступность %s %s",
+	"availability.remove_usage":    "Использование: /availability remove <номер>\n\nНомера показывает /availability.",
+	"availability.not_found":       "Нет доступности с номером %d.",
+	"availability.remove_failed":   "Не удалось удалить доступность: %v",
+	"availability.removed":         "✅ Удалена доступность %s %s",
+	"availability.holiday_usage":   "Использование: /availability holiday <add|remove> <ГГГГ-ММ-ДД>",
+	"availability.holiday_failed":  "Не удалось обновить выходные: %v",
+	"availability.holiday_updated": "✅ Выходные обновлены для %s",
+	"availability.empty":           "Доступность не задана.\n\n%s",
+	"availability.holidays_failed": "Не удалось получить выходные.",
+	"availability.title":           "*Ваша доступность* (%s)",
+	"availability.holidays":        "🏖️ Выходные:",
+
+	// Day and time input
+	"input.expected_window": "Укажите дни и интервал времени, например %s",
+	"input.unknown_day":     "Неизвестный день %s",
+	"input.no_days":         "Не указаны дни",
+	"input.day_twice":       "%s указан дважды",
+	"input.expected_range":  "Укажите интервал времени, например %s",
+	"input.range_order":     "Время окончания должно быть позже времени начала",
+	"input.granularity":     "Время должно быть кратно %d минутам",
+	"input.invalid_time":    "Неверное время %s, нужен формат ЧЧ:ММ",
+	"input.unknown_date":    "Неизвестный день %s, используйте today, tomorrow, название дня или ГГГГ-ММ-ДД",
+	"input.expected_slot":   "Укажите день и интервал времени, например tomorrow 19:00-21:00",
+
+	// Slots
+	"slots.usage":           "Использование: /slots [дни]\n\nЧисло дней от 1 до %d",
+	"slots.failed":          "Не удалось получить слоты календаря.",
+	"slots.empty":           "Нет слотов на ближайшие %s.\n\nОткрыть слот: /openslot <день> <ЧЧ:ММ-ЧЧ:ММ>",
+	"slots.title":           "*Ваши слоты* (ближайшие %s, %s)",
+	"slots.free":            "%d. 🟢 %s свободен",
+	"slots.booked":          "%d. 📌 %s занят: %s",
+	"slots.review":          "ревью",
+	"slots.close_button":    "❌ Закрыть %d",
+	"slots.extend_button":   "➕ Продлить %d",
+	"slots.calendar_failed": "Не удалось получить календарь: %v",
+	"slots.not_free":        "Слот уже не свободен",
+	"slots.close_failed":    "Не удалось закрыть слот: %v",
+	"slots.closed":          "Слот закрыт",
+	"slots.extend_conflict": "Нельзя продлить: следующий слот начинается в %s",
+	"slots.extend_failed":   "Не удалось продлить слот: %v",
+	"slots.extended":        "Слот продлён до %s",
+	"openslot.invalid":      "Неверный слот: %v\n\nИспользование: /openslot <день> <ЧЧ:ММ-ЧЧ:ММ>\nПример: /openslot tomorrow 19:00-21:00",
+	"openslot.rejected":     "Нельзя открыть слот: %v",
+	"openslot.failed":       "Не удалось открыть слот: %v",
+	"openslot.opened":       "✅ Открыт слот %s - %s",
+	"openslot.too_short":    "Слот должен длиться не меньше %d минут",
+	"openslot.too_soon":     "Слот должен начинаться не раньше чем через %d минут",
+	"openslot.overlap":      "Слот пересекается с существующим слотом (%s - %s)",
+
+	// Status
+	"status.failed":          "Не удалось получить статус.",
+	"status.summary":         "*Статус*\n\nПользователь: %s\nАктивные ревью: %d",
+	"status.recent":          "Ближайшие ревью:",
+	"status.review":          "- %s в %s",
+	"status.unknown_project": "Неизвестно",
+
+	// Reviews
+	"review.unknown_project":   "Неизвестный проект",
+	"review.approved":          "✅ *Ревью подтверждено*\n\nПроект: %s\nВремя: %s",
+	"review.approved_answer":   "Ревью подтверждено!",
+	"review.cancelled":         "❌ *Ревью отменено*\n\nПроект: %s\nВремя: %s",
+	"review.cancelled_answer":  "Ревью отменено",
+	"review.approve_button":    "✅ Подтвердить",
+	"review.decline_button":    "❌ Отклонить",
+	"notify.review_request":    "*Запрос на ревью*\n\nПроект: %s\nВремя: %s\n\nОтветьте до %s.\n\nПодтвердите или отклоните кнопками ниже.",
+	"notify.review_request_by": "*Запрос на ревью*\n\nПроект: %s\nПроверяемый: %s\nВремя: %s\n\nОтветьте до %s.\n\nПодтвердите или отклоните кнопками ниже.",
+	"notify.non_whitelist":     "❌ *Ревью отменено автоматически*\n\nПроект: %s\nВремя: %s\n\nПроекта нет в вашем белом списке, поэтому ревью отменено автоматически.",
+	"notify.non_whitelist_ask": "*Запрос на ревью*\n\nПроект: %s\nВремя: %s\n\nПроекта нет в вашем белом списке. Ревью будет отменено, если вы не подтвердите его в течение %s, до %s.\n\nПодтвердите или отклоните кнопками ниже.",
+	"notify.unasked":           "❌ *Ревью отменено автоматически*\n\nПроект: %s\nВремя: %s\n\nПроекта нет в вашем белом списке. Вопрос о нём был отложен из-за паузы или тихих часов, а ревью начинается раньше, чем его можно отправить, поэтому оно отменено.",
+	"notify.blacklisted":       "🚫 *Ревью отменено автоматически*\n\nПроект: %s\nВремя: %s\n\nПроект в вашем чёрном списке, поэтому ревью отменено сразу.",
+	"notify.policy_declined":   "🚫 *Ревью отменено автоматически*\n\nПроект: %s\nВремя: %s\n\nБронирование отклонила ваша политика решений %s.",
+	"notify.limit_declined":    "🚫 *Ревью отменено автоматически*\n\nПроект: %s\nВремя: %s\n\n%s",
+	"notify.peer_declined":     "🚫 *Ревью отменено автоматически*\n\nПроект: %s\nВремя: %s\n\nВы заблокировали %s, поэтому ревью отменено сразу.",
+	"notify.limit_ask":         "🚦 *Ревью ждёт подтверждения*\n\nПроект: %s\nВремя: %s\n\n%s\n\nПодтвердите или отклоните его в следующем запросе.",
+	"limit.daily_cap":          "⚠️ Дневной лимит (%s) на этот день исчерпан.",
+	"limit.weekly_cap":         "⚠️ Недельный лимит (%s) на эту неделю исчерпан.",
+	"limit.working_hours":      "⚠️ Ревью начинается вне ваших рабочих часов (%s).",
+	"limit.min_gap":            "⚠️ Перерыв до или после вашего ревью в %[2]s меньше %[1]s.",
+	"notify.projects_synced":   "🎓 *Сданные проекты добавлены в белый список*\n\nВы сдали %s, поэтому они добавлены в белый список. Отключить: /set_sync_completed no.",
+	"notify.whitelist_expired": "⌛ *Срок записи белого списка истёк*\n\nВременная запись %s удалена из белого списка.",
+	"notify.blacklist_expired": "⌛ *Срок записи чёрного списка истёк*\n\nВременная запись %s удалена из чёрного списка.",
+	"notify.whitelist_timeout": "⏰ *Время ответа истекло*\n\nПроект: %s\nВремя: %s\n\nВы не ответили вовремя, поэтому ревью отменено автоматически.",
+	"notify.timeout_approved":  "⏰ *Время ответа истекло*\n\nПроект: %s\nВремя: %s\n\nВы не ответили вовремя, поэтому ревью подтверждено автоматически.",
+
+	// Suggestions
+	"suggest.whitelist":      "💡 *Предложение для белого списка*\n\nВы вручную подтвердили %d из %d ревью: %s. Добавить в белый список, чтобы такие ревью подтверждались сами?",
+	"suggest.blacklist":      "💡 *Предложение для чёрного списка*\n\nВы вручную отклонили %d из %d ревью: %s. Добавить в чёрный список, чтобы такие ревью отменялись сразу?",
+	"suggest.add_whitelist":  "✅ В белый список",
+	"suggest.add_blacklist":  "🚫 В чёрный список",
+	"suggest.dismiss":        "Больше не предлагать",
+	"suggest.dismissed":      "Хорошо, больше не буду предлагать %s.",
+	"suggest.dismiss_failed": "Не удалось отклонить предложение: %v",
+
+	// Help
+	"help.step":                        "шаг %d",
+	"help.arg_reviews":                 "<ревью>",
+	"help.arg_minutes":                 "<минуты>",
+	"help.arg_hours":                   "<часы>",
+	"help.arg_days":                    "<дни>",
+	"help.deadline_shift":              "Сдвиг срока ответа",
+	"help.cancel_delay":                "Задержка отмены вне белого списка",
+	"help.notify_whitelist_timeout":    "Уведомлять об истечении срока",
+	"help.notify_non_whitelist_cancel": "Уведомлять об отмене вне белого списка",
+	"help.slot_shift_threshold":        "Порог сдвига слота",
+	"help.slot_shift_duration":         "Длительность сдвига слота",
+	"help.cleanup_duration":            "Длительность очистки",
+	"help.slot_housekeeping":           "Уборка частично занятых слотов",
+	"help.housekeeping_buffer":         "Свободное время рядом с бронированиями",
+	"help.availability_days":           "На сколько дней вперёд открывать слоты доступности",
+	"help.daily_cap":                   "Максимум ревью в день, 0 - без лимита",
+	"help.weekly_cap":                  "Максимум ревью в неделю, 0 - без лимита",
+	"help.min_gap":                     "Минимум минут между концом одного ревью и началом следующего, 0 - без перерыва",
+	"help.limit_action":                "Что делать с бронированиями сверх лимитов",
+	"help.outside_hours_action":        "Что делать с бронированиями вне рабочих часов",
+	"help.blocked_peer_action":         "Что делать с бронированиями заблокированных студентов",
+	"help.timeout_action":              "Что делать с ревью, на которые вы не ответили вовремя",
+	"help.non_whitelist_action":        "Что делать с бронированиями проектов вне белого списка",
+	"help.non_whitelist_deadline":      "Сколько времени есть на ответ о бронировании вне белого списка",
+	"help.lookahead":                   "Насколько вперёд учитывать новые бронирования",
+	"help.lookback":                    "Насколько назад учитывать новые бронирования",
+	"help.sync_completed":              "Добавлять сданные проекты в белый список",
+	"help.pause_policy":                "Что делать с новыми бронированиями на паузе",
+	"help.language":                    "Сменить язык бота",
+	"help.text": `*Review Slot Guard Bot*
+
+Бот помогает управлять слотами на ревью в School 21.
+
+*Команды:*
+
+/start - Авторизация
+/logout - Выйти из бота
+/status - Текущий статус и активные ревью
+/settings - Просмотр и изменение настроек
+/whitelist - Белый и чёрный списки
+/availability - Еженедельная доступность
+/pause [until <ГГГГ-ММ-ДД>] - Поставить запросы на паузу, например на отпуск
+/resume - Снять паузу
+/slots [дни] - Ближайшие слоты календаря
+
+*Белый список:*
+/whitelist_add [<family|project> <название> [for 3d]] - Добавить в белый список, можно на время, без аргументов - выбрать из списка
+/whitelist_remove <название> - Удалить из белого списка
+/whitelist_test <проект> - Показать, какая запись белого списка принимает проект
+/blacklist_add <family|project|glob|regex> <название> - Всегда сразу отменять бронирования проекта
+/blacklist_remove <название> - Удалить из чёрного списка
+/preset [show|subscribe|unsubscribe <название>] - Общие пресеты белого списка и подписка на них
+/preset <create|delete> <название> - Создать или удалить пресет
+/preset <add|remove> <название> ... - Изменить записи своего пресета
+
+*Слоты:*
+/openslot <день> <ЧЧ:ММ-ЧЧ:ММ> - Открыть слот, например tomorrow 19:00-21:00
+
+*Доступность:*
+/availability add <дни> <ЧЧ:ММ-ЧЧ:ММ> - Открывать слоты каждую неделю, например Mon-Thu 19:00-21:00
+/availability remove <номер> - Удалить еженедельное окно
+/availability holiday <add|remove> <ГГГГ-ММ-ДД> - Пропустить или вернуть дату
+
+*Настройки:*
+%s
+/override <family|project> <название> <настройка> <значение> - Другое значение для семейства или проекта, см. /override
+/rule [add|remove|test] - Решать по бронированиям своими условиями, см. /rule
+/peers [trust|block|remove <логин...>] - Оставлять бронирования доверенных студентов, придерживать заблокированных
+/policies [политика...] - Порядок политик, решающих по бронированиям
+/set_quiet_hours <ЧЧ:ММ-ЧЧ:ММ|off> - Откладывать сообщения и таймауты ночью
+/set_working_hours <дни ЧЧ:ММ-ЧЧ:ММ|off> - Часы, в которые вы принимаете ревью, например Mon-Fri 10:00-22:00
+/set_timezone <пояс|auto> - Часовой пояс, например Europe/Moscow; auto берёт пояс кампуса
+
+Время показывается и вводится в вашем часовом поясе.`,
+}
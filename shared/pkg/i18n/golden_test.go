@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var indexedVerbPattern = regexp.MustCompile(`%(?:\[(\d+)\])?[-+# 0]*\d*(?:\.\d+)?([a-zA-Z])`)
+
+// sampleArgs returns arguments for every verb of format. Text arguments contain
+// markup and HTML special characters, so the golden files show that they are escaped
+func sampleArgs(format string) []interface{} {
+	var args []interface{}
+	next := 0
+	for _, m := range indexedVerbPattern.FindAllStringSubmatch(strings.ReplaceAll(format, "%%", ""), -1) {
+		index := next
+		if m[1] != "" {
+			n, _ := strconv.Atoi(m[1])
+			index = n - 1
+		}
+		next = index + 1
+
+		for len(args) <= index {
+			args = append(args, nil)
+		}
+		if m[2] == "d" {
+			args[index] = 10 + index
+		} else {
+			args[index] = fmt.Sprintf("<arg%d & *x*>", index+1)
+		}
+	}
+	return args
+}
+
+// renderCatalog renders every template of lang with sample arguments, sorted by key
+func renderCatalog(lang string) string {
+	catalog := catalogs[lang]
+	keys := make([]string, 0, len(catalog))
+	for key := range catalog {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	p := New(lang)
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, "== %s ==\n%s\n\n", key, p.T(key, sampleArgs(catalog[key])...))
+	}
+	return b.String()
+}
+
+func TestTemplatesGolden(t *testing.T) {
+	for _, lang := range Languages() {
+		t.Run(lang, func(t *testing.T) {
+			path := filepath.Join("testdata", lang+".golden")
+			actual := renderCatalog(lang)
+
+			if *update {
+				require.NoError(t, os.WriteFile(path, []byte(actual), 0o644))
+			}
+
+			expected, err := os.ReadFile(path)
+			require.NoError(t, err, "run go test ./pkg/i18n -update to create the golden files")
+			assert.Equal(t, string(expected), actual)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+)
+
+// Supported languages
+const (
+	English = "en"
+	Russian = "ru"
+)
+
+// Default is used when neither the user nor their Telegram client picks a supported language
+const Default = English
+
+// Plural forms used as key suffixes, e.g. "unit.minutes.few"
+const (
+	FormOne   = "one"
+	FormFew   = "few"
+	FormMany  = "many"
+	FormOther = "other"
+)
+
+// catalogs maps a language to its messages
+var catalogs = map[string]map[string]string{
+	English: en,
+	Russian: ru,
+}
+
+// pluralForms lists the forms every plural message needs in each language
+var pluralForms = map[string][]string{
+	English: {FormOne, FormOther},
+	Russian: {FormOne, FormFew, FormMany},
+}
+
+// Printer renders catalog messages in one language
+type Printer struct {
+	lang string
+}
+
+// New returns a printer for lang, falling back to Default for unsupported languages
+func New(lang string) *Printer {
+	if !IsSupported(lang) {
+		lang = Default
+	}
+	return &Printer{lang: lang}
+}
+
+// IsSupported reports whether there is a catalog for lang
+func IsSupported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Languages returns the supported languages
+func Languages() []string {
+	return []string{English, Russian}
+}
+
+// Resolve picks the language for a user: the stored choice if any, otherwise
+// the Telegram client language (e.g. "ru" or "ru-RU"), otherwise Default
+func Resolve(stored, clientLang string) string {
+	if IsSupported(stored) {
+		return stored
+	}
+	client := strings.ToLower(strings.SplitN(clientLang, "-", 2)[0])
+	if IsSupported(client) {
+		return client
+	}
+	return Default
+}
+
+// PluralForm returns the plural form of n in lang
+func PluralForm(lang string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+
+	switch lang {
+	case Russian:
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return FormOne
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return FormFew
+		default:
+			return FormMany
+		}
+	default:
+		if n == 1 {
+			return FormOne
+		}
+		return FormOther
+	}
+}
+
+// Lang returns the language of the printer
+func (p *Printer) Lang() string {
+	return p.lang
+}
+
+// T renders the message for key with fmt-style args, see render.Format
+func (p *Printer) T(key string, args ...interface{}) render.HTML {
+	return render.Format(p.lookup(key), args...)
+}
+
+// N renders the plural message for key. n is passed as the first format argument
+func (p *Printer) N(key string, n int, args ...interface{}) render.HTML {
+	return render.Format(p.lookup(key+"."+PluralForm(p.lang, n)), append([]interface{}{n}, args...)...)
+}
+
+// Err renders err in the printer's language if it was created with Errorf. Arguments
+// created with Errorf, such as names that need translating, are rendered in it as well
+func (p *Printer) Err(err error) render.HTML {
+	var e *Error
+	if errors.As(err, &e) {
+		args := make([]interface{}, len(e.Args))
+		for i, arg := range e.Args {
+			if nested, ok := arg.(*Error); ok {
+				args[i] = p.Err(nested)
+			} else {
+				args[i] = arg
+			}
+		}
+		return p.T(e.Key, args...)
+	}
+	return render.Escape(err.Error())
+}
+
+// FormatShort formats t in loc like timezone.FormatShort, with localized month names
+func (p *Printer) FormatShort(t time.Time, loc *time.Location) render.HTML {
+	t = t.In(loc)
+	return p.T("time.short", p.month(t.Month()), t.Day(), t.Format("15:04 MST"))
+}
+
+// FormatDay formats the date of t in loc, e.g. "Mon, Jan 2"
+func (p *Printer) FormatDay(t time.Time, loc *time.Location) render.HTML {
+	t = t.In(loc)
+	return p.T("time.day", p.T(fmt.Sprintf("weekday.%d", t.Weekday())), p.month(t.Month()), t.Day())
+}
+
+func (p *Printer) month(m time.Month) render.HTML {
+	return p.T(fmt.Sprintf("month.%d", m))
+}
+
+// lookup returns the message for key, falling back to Default and then to the key itself
+func (p *Printer) lookup(key string) string {
+	if msg, ok := catalogs[p.lang][key]; ok {
+		return msg
+	}
+	if msg, ok := catalogs[Default][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// Error is an error whose text comes from the catalog, so it can be shown in the user's language
+type Error struct {
+	Key  string
+	Args []interface{}
+}
+
+// Errorf creates an Error for a catalog key
+func Errorf(key string, args ...interface{}) error {
+	return &Error{Key: key, Args: args}
+}
+
+// Error renders the error in the default language
+func (e *Error) Error() string {
+	return render.Plain(New(Default).T(e.Key, e.Args...))
+}
@@ -0,0 +1,190 @@
+package i18n
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+)
+
+// verbPattern matches fmt verbs such as %s, %d, %v and %[2]d, but not %%
+var verbPattern = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z]`)
+
+// baseKeys returns every message of every catalog, with plural keys collapsed to their base
+func baseKeys() (messages map[string]bool, plurals map[string]bool) {
+	messages = make(map[string]bool)
+	plurals = make(map[string]bool)
+	for _, catalog := range catalogs {
+		for key := range catalog {
+			if base, ok := pluralBase(key); ok {
+				plurals[base] = true
+				continue
+			}
+			messages[key] = true
+		}
+	}
+	return messages, plurals
+}
+
+func pluralBase(key string) (string, bool) {
+	i := strings.LastIndex(key, ".")
+	if i < 0 {
+		return "", false
+	}
+	switch key[i+1:] {
+	case FormOne, FormFew, FormMany, FormOther:
+		return key[:i], true
+	}
+	return "", false
+}
+
+func countVerbs(format string) int {
+	return len(verbPattern.FindAllString(strings.ReplaceAll(format, "%%", ""), -1))
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	messages, plurals := baseKeys()
+	require.NotEmpty(t, messages)
+
+	for _, lang := range Languages() {
+		catalog, ok := catalogs[lang]
+		require.True(t, ok, "no catalog for %s", lang)
+
+		for key := range messages {
+			assert.Contains(t, catalog, key, "%s is missing %q", lang, key)
+		}
+
+		for base := range plurals {
+			for _, form := range pluralForms[lang] {
+				assert.Contains(t, catalog, base+"."+form, "%s is missing %q", lang, base+"."+form)
+			}
+		}
+	}
+}
+
+func TestCatalogsFormatVerbs(t *testing.T) {
+	for key, format := range catalogs[Default] {
+		base, plural := pluralBase(key)
+
+		for _, lang := range Languages() {
+			translatedKeys := []string{key}
+			if plural {
+				translatedKeys = translatedKeys[:0]
+				for _, form := range pluralForms[lang] {
+					translatedKeys = append(translatedKeys, base+"."+form)
+				}
+			}
+
+			for _, translatedKey := range translatedKeys {
+				translated, ok := catalogs[lang][translatedKey]
+				if !ok {
+					continue // reported by TestCatalogsComplete
+				}
+				assert.Equal(t, countVerbs(format), countVerbs(translated), "%s %q has different arguments", lang, translatedKey)
+			}
+		}
+	}
+}
+
+func TestPluralForm(t *testing.T) {
+	tests := []struct {
+		lang     string
+		n        int
+		expected string
+	}{
+		{English, 0, FormOther},
+		{English, 1, FormOne},
+		{English, 2, FormOther},
+		{English, 21, FormOther},
+		{Russian, 1, FormOne},
+		{Russian, 2, FormFew},
+		{Russian, 4, FormFew},
+		{Russian, 5, FormMany},
+		{Russian, 11, FormMany},
+		{Russian, 12, FormMany},
+		{Russian, 21, FormOne},
+		{Russian, 22, FormFew},
+		{Russian, 25, FormMany},
+		{Russian, 111, FormMany},
+		{Russian, 0, FormMany},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, PluralForm(tt.lang, tt.n), "%s %d", tt.lang, tt.n)
+	}
+}
+
+func TestPrinterN(t *testing.T) {
+	en := New(English)
+	assert.Equal(t, render.HTML("1 minute"), en.N("unit.minutes", 1))
+	assert.Equal(t, render.HTML("30 minutes"), en.N("unit.minutes", 30))
+
+	ru := New(Russian)
+	assert.Equal(t, render.HTML("1 минута"), ru.N("unit.minutes", 1))
+	assert.Equal(t, render.HTML("3 минуты"), ru.N("unit.minutes", 3))
+	assert.Equal(t, render.HTML("15 минут"), ru.N("unit.minutes", 15))
+	assert.Equal(t, render.HTML("21 час"), ru.N("unit.hours", 21))
+	assert.Equal(t, render.HTML("7 дней"), ru.N("unit.days", 7))
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		stored   string
+		client   string
+		expected string
+	}{
+		{"Stored", Russian, "en", Russian},
+		{"Client", "", "ru", Russian},
+		{"ClientRegion", "", "ru-RU", Russian},
+		{"UnsupportedClient", "", "de", Default},
+		{"UnsupportedStored", "de", "ru", Russian},
+		{"Nothing", "", "", Default},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Resolve(tt.stored, tt.client))
+		})
+	}
+}
+
+func TestNewFallsBackToDefault(t *testing.T) {
+	assert.Equal(t, Default, New("de").Lang())
+	assert.Equal(t, Russian, New(Russian).Lang())
+}
+
+func TestLookupFallback(t *testing.T) {
+	p := New(Russian)
+	assert.Equal(t, render.HTML("missing.key"), p.T("missing.key"))
+}
+
+func TestFormatDates(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	tm := time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, render.HTML("Jan 15 17:30 MSK"), New(English).FormatShort(tm, moscow))
+	assert.Equal(t, render.HTML("15 янв 17:30 MSK"), New(Russian).FormatShort(tm, moscow))
+	assert.Equal(t, render.HTML("Thu, Jan 15"), New(English).FormatDay(tm, moscow))
+	assert.Equal(t, render.HTML("Чт, 15 янв"), New(Russian).FormatDay(tm, moscow))
+}
+
+func TestErr(t *testing.T) {
+	err := Errorf("common.invalid_date", "2026-01-20")
+
+	assert.Equal(t, "Invalid date. Use the YYYY-MM-DD format, e.g. 2026-01-20", err.Error())
+	assert.Equal(t, render.HTML("Неверная дата. Используйте формат ГГГГ-ММ-ДД, например 2026-01-20"), New(Russian).Err(err))
+	assert.Equal(t, render.HTML("plain"), New(Russian).Err(errors.New("plain")))
+
+	// Nested errors are rendered in the printer's language too
+	nested := Errorf("rule_error.matches_text", 9, Errorf("rule_error.kind_number"))
+	assert.Equal(t, `"matches" at position 9 needs text, not a number`, nested.Error())
+	assert.Equal(t, render.HTML(`"matches" в позиции 9 нужен текст, а не число`), New(Russian).Err(nested))
+}
@@ -0,0 +1,447 @@
+package policy
+
+import (
+	"strings"
+	"time"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/availability"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/rules"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
+)
+
+// Decision is what a policy wants done with a booking
+type Decision string
+
+const (
+	// Abstain leaves the booking to the next policy of the chain
+	Abstain Decision = ""
+	// Approve keeps the booking without asking
+	Approve Decision = "APPROVE"
+	// Ask sends the user an approval request right away
+	Ask Decision = "ASK"
+	// Decline cancels the booking
+	Decline Decision = "DECLINE"
+	// Defer leaves the booking to the next policies but holds it back: if one of them
+	// approves it, the deferring policy's Fallback is taken instead
+	Defer Decision = "DEFER"
+)
+
+// Built-in policy names, used in the user's chain and recorded with each decision
+const (
+	DenyList     = "deny_list"
+	WorkingHours = "working_hours"
+	DailyCap     = "daily_cap"
+	WeeklyCap    = "weekly_cap"
+	MinGap       = "min_gap"
+	Peers        = "peers"
+	Rules        = "rules"
+	Whitelist    = "whitelist"
+	// Default is recorded when no policy of the chain decided. Its decline waits for the
+	// non-whitelist cancel delay, while declines of other policies cancel right away
+	Default = "default"
+)
+
+// ReviewDuration is how long a review is taken to last. Review requests store the start
+// only, so the minimum gap is measured from this long after the start of a review
+const ReviewDuration = 30 * time.Minute
+
+// Booking is what policies decide on
+type Booking struct {
+	ProjectName string
+	FamilyLabel string // empty if the family is unknown
+	Start       time.Time
+	// Reviewees are the logins of the students to review, empty while unknown
+	Reviewees []string
+	// Accepted holds the start times of the user's other approved and whitelisted reviews
+	Accepted []time.Time
+	// Entries and Presets are the user's effective whitelist and blacklist, see whitelist.Effective
+	Entries []*models.WhitelistEntry
+	Presets map[*models.WhitelistEntry]string
+	// Rules are the user's decision rules, oldest first
+	Rules  []*store.DecisionRule
+	Limits BookingLimits
+	// Peers are the user's trusted and blocked students
+	Peers []*store.Peer
+	// BlockedPeers is taken on bookings of blocked students, Ask or Decline
+	BlockedPeers Decision
+	// WorkingHours are the hours the user takes reviews in, empty for any time
+	WorkingHours availability.WorkingHours
+	// OutsideHours is taken on bookings outside WorkingHours: Ask holds back kept ones,
+	// Decline cancels them all
+	OutsideHours Decision
+	Location     *time.Location
+	Now          time.Time
+}
+
+// Result is the decision on a booking and the policy that made it
+type Result struct {
+	Decision Decision
+	Policy   string
+	// Fallback is what a deferring policy takes instead of a later approval, Ask or Decline
+	Fallback Decision
+	// Match is the entry behind a whitelist or deny list decision
+	Match *whitelist.Match
+	// Rule is the rule behind a rules decision
+	Rule *store.DecisionRule
+	// Limit is the limit behind a decision of the working hours or a limit policy
+	Limit *Limit
+	// Peer is the trusted or blocked student behind a peers decision
+	Peer *store.Peer
+}
+
+// BookingLimits are the user's limits on accepted reviews; zero turns a limit off
+type BookingLimits struct {
+	PerDay, PerWeek int
+	MinGapMinutes   int
+	// Decision is taken instead of keeping bookings that break a limit, Ask or Decline
+	Decision Decision
+}
+
+// Off reports whether no limit is set
+func (l BookingLimits) Off() bool {
+	return l.PerDay == 0 && l.PerWeek == 0 && l.MinGapMinutes == 0
+}
+
+// Limit is a limit a booking breaks
+type Limit struct {
+	Key   string // DailyCap, WeeklyCap, MinGap or WorkingHours
+	Value int    // the limit: reviews for the caps, minutes for the gap
+	// Conflict is the start of the accepted review too close to the booking, for MinGap
+	Conflict time.Time
+	// Hours are the working hours the booking is outside of, for WorkingHours
+	Hours string
+}
+
+// DecisionPolicy decides on bookings, or abstains by returning a Result with Abstain.
+// A policy that defers sets the Fallback of its Result
+type DecisionPolicy interface {
+	Name() string
+	Decide(b *Booking) Result
+}
+
+// builtins lists the policies users can put in their chain
+var builtins = []DecisionPolicy{
+	denyListPolicy{},
+	workingHoursPolicy{},
+	limitPolicy{name: DailyCap, broken: brokenDailyCap},
+	limitPolicy{name: WeeklyCap, broken: brokenWeeklyCap},
+	limitPolicy{name: MinGap, broken: brokenMinGap},
+	peersPolicy{},
+	rulesPolicy{},
+	whitelistPolicy{},
+}
+
+// DefaultChain is used until the user configures a chain. The working hours and limits
+// come before the policies that keep bookings, so they hold back every kept booking
+var DefaultChain = []string{DenyList, WorkingHours, DailyCap, WeeklyCap, MinGap, Peers, Rules, Whitelist}
+
+// Names returns the names of the built-in policies
+func Names() []string {
+	return Chain(builtins).Names()
+}
+
+// Get returns the built-in policy with the given name, or nil
+func Get(name string) DecisionPolicy {
+	for _, p := range builtins {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Chain asks its policies in order; the first that does not abstain decides
+type Chain []DecisionPolicy
+
+// NewChain returns the chain of the named policies, skipping unknown names
+func NewChain(names []string) Chain {
+	var chain Chain
+	for _, name := range names {
+		if p := Get(name); p != nil {
+			chain = append(chain, p)
+		}
+	}
+	return chain
+}
+
+// Names returns the names of the chain's policies
+func (c Chain) Names() []string {
+	names := make([]string, len(c))
+	for i, p := range c {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// Decide returns the decision of the first policy that neither abstains nor defers. If the
+// first policy that defers is followed by an approval, its Fallback is taken instead, so it
+// only holds back bookings the chain keeps. If none decides, the booking is declined by Default
+func (c Chain) Decide(b *Booking) Result {
+	var deferred *Result
+	for _, p := range c {
+		result := p.Decide(b)
+		result.Policy = p.Name()
+		switch {
+		case result.Decision == Abstain:
+			continue
+		case result.Decision == Defer:
+			if deferred == nil {
+				deferred = &result
+			}
+			continue
+		case result.Decision == Approve && deferred != nil:
+			return Result{Decision: deferred.Fallback, Policy: deferred.Policy, Limit: deferred.Limit}
+		}
+		return result
+	}
+	return Result{Decision: Decline, Policy: Default}
+}
+
+// ParseChain parses a chain stored in user_settings or given to /policies: policy names
+// separated by commas or spaces. An empty chain is the DefaultChain. The deny list may not
+// come after the whitelist, so a project on both lists is never kept
+func ParseChain(s string) ([]string, error) {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return r == ',' || r == ' ' })
+	if len(fields) == 0 {
+		return DefaultChain, nil
+	}
+
+	seen := make(map[string]bool, len(fields))
+	for _, name := range fields {
+		if Get(name) == nil {
+			return nil, i18n.Errorf("policies.unknown", name, strings.Join(Names(), ", "))
+		}
+		if seen[name] {
+			return nil, i18n.Errorf("policies.duplicate", name)
+		}
+		if name == DenyList && seen[Whitelist] {
+			return nil, i18n.Errorf("policies.deny_first", DenyList, Whitelist)
+		}
+		seen[name] = true
+	}
+	return fields, nil
+}
+
+// FormatChain returns the chain in the form stored in user_settings
+func FormatChain(names []string) string {
+	return strings.Join(names, ",")
+}
+
+// denyListPolicy declines bookings of blacklisted projects
+type denyListPolicy struct{}
+
+func (denyListPolicy) Name() string { return DenyList }
+
+func (denyListPolicy) Decide(b *Booking) Result {
+	match := whitelist.FindInList(b.Entries, true, b.ProjectName, b.FamilyLabel)
+	if match == nil {
+		return Result{}
+	}
+	match.Preset = b.Presets[match.Entry]
+	return Result{Decision: Decline, Match: match}
+}
+
+// workingHoursPolicy holds back bookings outside the user's working hours, or with
+// OutsideHours set to Decline, declines them whatever the later policies say
+type workingHoursPolicy struct{}
+
+func (workingHoursPolicy) Name() string { return WorkingHours }
+
+func (workingHoursPolicy) Decide(b *Booking) Result {
+	if b.WorkingHours.Contains(b.Start, b.Location) {
+		return Result{}
+	}
+	limit := &Limit{Key: WorkingHours, Hours: availability.FormatWorkingHours(b.WorkingHours)}
+	if b.OutsideHours == Decline {
+		return Result{Decision: Decline, Limit: limit}
+	}
+	return Result{Decision: Defer, Fallback: Ask, Limit: limit}
+}
+
+// limitPolicy holds back bookings breaking one of the user's review limits, asking about
+// them or declining them as the limits say
+type limitPolicy struct {
+	name   string
+	broken func(b *Booking) *Limit
+}
+
+func (p limitPolicy) Name() string { return p.name }
+
+func (p limitPolicy) Decide(b *Booking) Result {
+	limit := p.broken(b)
+	if limit == nil {
+		return Result{}
+	}
+	fallback := Ask
+	if b.Limits.Decision == Decline {
+		fallback = Decline
+	}
+	return Result{Decision: Defer, Fallback: fallback, Limit: limit}
+}
+
+// acceptedInWindow counts the accepted reviews on the booking's day and in its week,
+// Monday to Sunday, both in the user's timezone
+func acceptedInWindow(b *Booking) (sameDay, sameWeek int) {
+	start := b.Start.In(b.Location)
+	year, week := start.ISOWeek()
+	for _, t := range b.Accepted {
+		local := t.In(b.Location)
+		if y, w := local.ISOWeek(); y == year && w == week {
+			sameWeek++
+			if local.YearDay() == start.YearDay() {
+				sameDay++
+			}
+		}
+	}
+	return sameDay, sameWeek
+}
+
+func brokenDailyCap(b *Booking) *Limit {
+	if b.Limits.PerDay == 0 {
+		return nil
+	}
+	if sameDay, _ := acceptedInWindow(b); sameDay < b.Limits.PerDay {
+		return nil
+	}
+	return &Limit{Key: DailyCap, Value: b.Limits.PerDay}
+}
+
+func brokenWeeklyCap(b *Booking) *Limit {
+	if b.Limits.PerWeek == 0 {
+		return nil
+	}
+	if _, sameWeek := acceptedInWindow(b); sameWeek < b.Limits.PerWeek {
+		return nil
+	}
+	return &Limit{Key: WeeklyCap, Value: b.Limits.PerWeek}
+}
+
+// brokenMinGap returns the minimum gap with the closest accepted review that is too close:
+// the break between the end of the earlier review and the start of the later one is shorter
+func brokenMinGap(b *Booking) *Limit {
+	if b.Limits.MinGapMinutes == 0 {
+		return nil
+	}
+	gap := time.Duration(b.Limits.MinGapMinutes)*time.Minute + ReviewDuration
+	var conflict *time.Time
+	for i, t := range b.Accepted {
+		if absDuration(t.Sub(b.Start)) < gap &&
+			(conflict == nil || absDuration(t.Sub(b.Start)) < absDuration(conflict.Sub(b.Start))) {
+			conflict = &b.Accepted[i]
+		}
+	}
+	if conflict == nil {
+		return nil
+	}
+	return &Limit{Key: MinGap, Value: b.Limits.MinGapMinutes, Conflict: *conflict}
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// peersPolicy holds back bookings of blocked students and approves bookings whose students
+// are all trusted. A team with one blocked member is held back
+type peersPolicy struct{}
+
+func (peersPolicy) Name() string { return Peers }
+
+func (peersPolicy) Decide(b *Booking) Result {
+	if len(b.Reviewees) == 0 || len(b.Peers) == 0 {
+		return Result{}
+	}
+
+	var trusted *store.Peer
+	allTrusted := true
+	for _, login := range b.Reviewees {
+		peer := FindPeer(b.Peers, login)
+		switch {
+		case peer == nil:
+			allTrusted = false
+		case peer.Kind == store.PeerBlocked:
+			return Result{Decision: blockedDecision(b), Peer: peer}
+		case trusted == nil:
+			trusted = peer
+		}
+	}
+	if !allTrusted {
+		return Result{}
+	}
+	return Result{Decision: Approve, Peer: trusted}
+}
+
+func blockedDecision(b *Booking) Decision {
+	if b.BlockedPeers == Decline {
+		return Decline
+	}
+	return Ask
+}
+
+// FindPeer returns the peer with the given login, ignoring case, or nil
+func FindPeer(peers []*store.Peer, login string) *store.Peer {
+	for _, peer := range peers {
+		if strings.EqualFold(peer.Login, login) {
+			return peer
+		}
+	}
+	return nil
+}
+
+// ruleDecisions maps rule actions to decisions
+var ruleDecisions = map[string]Decision{
+	rules.ActionApprove: Approve,
+	rules.ActionAsk:     Ask,
+	rules.ActionDecline: Decline,
+}
+
+// RuleAttributes returns the attributes rules see of a booking
+func RuleAttributes(b *Booking) rules.Attributes {
+	return rules.NewAttributes(b.ProjectName, b.FamilyLabel, b.Start, b.Accepted, b.Location, b.Now)
+}
+
+// rulesPolicy applies the action of the first decision rule that holds. Rules are checked
+// when saved, so one that fails to compile or evaluate is skipped
+type rulesPolicy struct{}
+
+func (rulesPolicy) Name() string { return Rules }
+
+func (rulesPolicy) Decide(b *Booking) Result {
+	if len(b.Rules) == 0 {
+		return Result{}
+	}
+	attrs := RuleAttributes(b)
+	for _, rule := range b.Rules {
+		program, err := rules.Compile(rule.Expression)
+		if err != nil {
+			continue
+		}
+		decision, ok := ruleDecisions[rule.Action]
+		if !ok {
+			continue
+		}
+		if holds, err := program.Eval(attrs, rules.EvalTimeout); err == nil && holds {
+			return Result{Decision: decision, Rule: rule}
+		}
+	}
+	return Result{}
+}
+
+// whitelistPolicy approves bookings of whitelisted projects
+type whitelistPolicy struct{}
+
+func (whitelistPolicy) Name() string { return Whitelist }
+
+func (whitelistPolicy) Decide(b *Booking) Result {
+	match := whitelist.FindInList(b.Entries, false, b.ProjectName, b.FamilyLabel)
+	if match == nil {
+		return Result{}
+	}
+	match.Preset = b.Presets[match.Entry]
+	return Result{Decision: Approve, Match: match}
+}
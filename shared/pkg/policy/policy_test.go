@@ -0,0 +1,331 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/availability"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/rules"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/whitelist"
+)
+
+// fixed is a test policy returning the same decision for every booking
+type fixed struct {
+	name     string
+	decision Decision
+}
+
+func (f fixed) Name() string           { return f.name }
+func (f fixed) Decide(*Booking) Result { return Result{Decision: f.decision} }
+
+// deferring is a test policy deferring every booking with the same fallback
+type deferring struct {
+	name     string
+	fallback Decision
+}
+
+func (d deferring) Name() string           { return d.name }
+func (d deferring) Decide(*Booking) Result { return Result{Decision: Defer, Fallback: d.fallback} }
+
+func TestChain_Decide(t *testing.T) {
+	booking := &Booking{ProjectName: "C2_SimpleBashUtils", FamilyLabel: "C - I"}
+
+	t.Run("FirstDecisionWins", func(t *testing.T) {
+		chain := Chain{fixed{"a", Abstain}, fixed{"b", Ask}, fixed{"c", Approve}}
+		assert.Equal(t, Result{Decision: Ask, Policy: "b"}, chain.Decide(booking))
+	})
+
+	t.Run("AllAbstain", func(t *testing.T) {
+		chain := Chain{fixed{"a", Abstain}}
+		assert.Equal(t, Result{Decision: Decline, Policy: Default}, chain.Decide(booking))
+		assert.Equal(t, Result{Decision: Decline, Policy: Default}, Chain(nil).Decide(booking))
+	})
+
+	t.Run("DeferHoldsBackApproval", func(t *testing.T) {
+		chain := Chain{deferring{"a", Ask}, fixed{"b", Abstain}, fixed{"c", Approve}}
+		assert.Equal(t, Result{Decision: Ask, Policy: "a"}, chain.Decide(booking))
+	})
+
+	t.Run("DeferKeepsOtherDecisions", func(t *testing.T) {
+		chain := Chain{deferring{"a", Decline}, fixed{"b", Ask}}
+		assert.Equal(t, Result{Decision: Ask, Policy: "b"}, chain.Decide(booking))
+		chain = Chain{deferring{"a", Ask}, fixed{"b", Decline}}
+		assert.Equal(t, Result{Decision: Decline, Policy: "b"}, chain.Decide(booking))
+		chain = Chain{deferring{"a", Ask}}
+		assert.Equal(t, Result{Decision: Decline, Policy: Default}, chain.Decide(booking), "nothing to hold back")
+	})
+
+	t.Run("FirstDeferralWins", func(t *testing.T) {
+		chain := Chain{deferring{"a", Decline}, deferring{"b", Ask}, fixed{"c", Approve}}
+		assert.Equal(t, Result{Decision: Decline, Policy: "a"}, chain.Decide(booking))
+	})
+
+	t.Run("ApprovalBeforeDeferral", func(t *testing.T) {
+		chain := Chain{fixed{"a", Approve}, deferring{"b", Ask}}
+		assert.Equal(t, Result{Decision: Approve, Policy: "a"}, chain.Decide(booking))
+	})
+}
+
+func TestBuiltins(t *testing.T) {
+	blacklisted := &models.WhitelistEntry{EntryType: whitelist.BlacklistType(models.EntryTypeProject), Name: "C2_SimpleBashUtils"}
+	family := &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "C - I"}
+	booking := &Booking{
+		ProjectName: "C2_SimpleBashUtils",
+		FamilyLabel: "C - I",
+		Entries:     []*models.WhitelistEntry{blacklisted, family},
+		Presets:     map[*models.WhitelistEntry]string{family: "c-team"},
+	}
+
+	t.Run("DenyListFirst", func(t *testing.T) {
+		result := NewChain(DefaultChain).Decide(booking)
+		assert.Equal(t, Decline, result.Decision)
+		assert.Equal(t, DenyList, result.Policy)
+		require.NotNil(t, result.Match)
+		assert.Same(t, blacklisted, result.Match.Entry)
+		assert.True(t, result.Match.Blacklisted)
+	})
+
+	t.Run("WhitelistFirst", func(t *testing.T) {
+		result := NewChain([]string{Whitelist, DenyList}).Decide(booking)
+		assert.Equal(t, Approve, result.Decision)
+		assert.Equal(t, Whitelist, result.Policy)
+		require.NotNil(t, result.Match)
+		assert.True(t, result.Match.ByFamily)
+		assert.Equal(t, "c-team", result.Match.Preset)
+	})
+
+	t.Run("NoEntry", func(t *testing.T) {
+		result := NewChain(DefaultChain).Decide(&Booking{ProjectName: "go-concurrency", FamilyLabel: "Go", Entries: booking.Entries})
+		assert.Equal(t, Result{Decision: Decline, Policy: Default}, result)
+	})
+}
+
+func TestParseChain(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		errKey   string
+	}{
+		{"Empty", " ", DefaultChain, ""},
+		{"Stored", "deny_list,whitelist", []string{DenyList, Whitelist}, ""},
+		{"WithRules", "deny_list,rules,whitelist", []string{DenyList, Rules, Whitelist}, ""},
+		{"RetiredLimits", "deny_list,limits,rules,whitelist", nil, "policies.unknown"},
+		{"WithPeers", "deny_list,peers,rules,whitelist", []string{DenyList, Peers, Rules, Whitelist}, ""},
+		{"Default", "deny_list,working_hours,daily_cap,weekly_cap,min_gap,peers,rules,whitelist", DefaultChain, ""},
+		{"Typed", "Deny_List  whitelist", []string{DenyList, Whitelist}, ""},
+		{"Single", "whitelist", []string{Whitelist}, ""},
+		{"DenyListAfterWhitelist", "whitelist,rules,deny_list", nil, "policies.deny_first"},
+		{"Unknown", "whitelist, coin_flip", nil, "policies.unknown"},
+		{"Duplicate", "whitelist whitelist", nil, "policies.duplicate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, err := ParseChain(tt.input)
+			if tt.errKey != "" {
+				var e *i18n.Error
+				require.ErrorAs(t, err, &e)
+				assert.Equal(t, tt.errKey, e.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, names)
+			assert.Equal(t, names, NewChain(names).Names())
+		})
+	}
+
+	assert.Equal(t, "deny_list,whitelist", FormatChain([]string{DenyList, Whitelist}))
+}
+
+func TestRulesPolicy(t *testing.T) {
+	start := time.Date(2026, 3, 4, 19, 0, 0, 0, time.UTC) // Wednesday
+	evening := &store.DecisionRule{Action: rules.ActionApprove, Expression: `family == "CPP" && weekday in [1..4] && hour >= 18`}
+	busy := &store.DecisionRule{Action: rules.ActionDecline, Expression: "reviews_today >= 2"}
+	broken := &store.DecisionRule{Action: rules.ActionAsk, Expression: "room == 1"}
+	booking := func(family string, accepted int, rs ...*store.DecisionRule) *Booking {
+		b := &Booking{ProjectName: "CPP1_s21_matrix+", FamilyLabel: family, Start: start, Rules: rs, Location: time.UTC, Now: start.Add(-time.Hour)}
+		for i := 0; i < accepted; i++ {
+			b.Accepted = append(b.Accepted, start.Add(-time.Duration(i+2)*time.Hour))
+		}
+		return b
+	}
+	chain := NewChain([]string{Rules})
+
+	tests := []struct {
+		name     string
+		booking  *Booking
+		expected Result
+	}{
+		{"FirstRuleHolds", booking("CPP", 2, evening, busy), Result{Decision: Approve, Policy: Rules, Rule: evening}},
+		{"OrderMatters", booking("CPP", 2, busy, evening), Result{Decision: Decline, Policy: Rules, Rule: busy}},
+		{"SecondRuleHolds", booking("Go", 2, evening, busy), Result{Decision: Decline, Policy: Rules, Rule: busy}},
+		{"BrokenRuleSkipped", booking("CPP", 0, broken, evening), Result{Decision: Approve, Policy: Rules, Rule: evening}},
+		{"NoRuleHolds", booking("Go", 0, evening, busy), Result{Decision: Decline, Policy: Default}},
+		{"NoRules", booking("CPP", 0), Result{Decision: Decline, Policy: Default}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, chain.Decide(tt.booking))
+		})
+	}
+}
+
+func TestLimitPolicies(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+	start := time.Date(2026, 3, 4, 19, 0, 0, 0, kyiv) // Wednesday
+	whitelisted := &models.WhitelistEntry{EntryType: models.EntryTypeProject, Name: "CPP1_s21_matrix+"}
+	booking := func(limits BookingLimits, accepted ...time.Time) *Booking {
+		return &Booking{ProjectName: "CPP1_s21_matrix+", Start: start, Accepted: accepted, Limits: limits,
+			Entries: []*models.WhitelistEntry{whitelisted}, Location: kyiv, Now: start.Add(-time.Hour)}
+	}
+	monday := time.Date(2026, 3, 2, 10, 0, 0, 0, kyiv)
+	previousSunday := time.Date(2026, 3, 1, 23, 0, 0, 0, kyiv)
+	// 00:30 in Kyiv is still the previous day in UTC
+	sameDayEarly := time.Date(2026, 3, 4, 0, 30, 0, 0, kyiv)
+	chain := NewChain(DefaultChain)
+	kept := func(b *Booking) bool { return chain.Decide(b).Policy == Whitelist }
+
+	tests := []struct {
+		name     string
+		booking  *Booking
+		expected Result
+	}{
+		{"DailyCapReached", booking(BookingLimits{PerDay: 1, Decision: Ask}, sameDayEarly),
+			Result{Decision: Ask, Policy: DailyCap, Limit: &Limit{Key: DailyCap, Value: 1}}},
+		{"WeeklyCapReached", booking(BookingLimits{PerDay: 2, PerWeek: 2, Decision: Decline}, monday, sameDayEarly),
+			Result{Decision: Decline, Policy: WeeklyCap, Limit: &Limit{Key: WeeklyCap, Value: 2}}},
+		{"MinGapBefore", booking(BookingLimits{MinGapMinutes: 60}, start.Add(-45*time.Minute)),
+			Result{Decision: Ask, Policy: MinGap, Limit: &Limit{Key: MinGap, Value: 60, Conflict: start.Add(-45 * time.Minute)}}},
+		{"MinGapClosestConflict", booking(BookingLimits{MinGapMinutes: 60}, start.Add(-50*time.Minute), start.Add(30*time.Minute)),
+			Result{Decision: Ask, Policy: MinGap, Limit: &Limit{Key: MinGap, Value: 60, Conflict: start.Add(30 * time.Minute)}}},
+		{"MinGapShorterThanOneReview", booking(BookingLimits{MinGapMinutes: 20}, start.Add(-45*time.Minute)),
+			Result{Decision: Ask, Policy: MinGap, Limit: &Limit{Key: MinGap, Value: 20, Conflict: start.Add(-45 * time.Minute)}}},
+		{"MinGapAfter", booking(BookingLimits{MinGapMinutes: 20}, start.Add(45*time.Minute)),
+			Result{Decision: Ask, Policy: MinGap, Limit: &Limit{Key: MinGap, Value: 20, Conflict: start.Add(45 * time.Minute)}}},
+		{"DailyCapBeforeMinGap", booking(BookingLimits{MinGapMinutes: 60, PerDay: 1}, start.Add(30*time.Minute)),
+			Result{Decision: Ask, Policy: DailyCap, Limit: &Limit{Key: DailyCap, Value: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, chain.Decide(tt.booking))
+		})
+	}
+
+	t.Run("Kept", func(t *testing.T) {
+		assert.True(t, kept(booking(BookingLimits{}, sameDayEarly, start.Add(time.Minute))), "limits off")
+		assert.True(t, kept(booking(BookingLimits{PerDay: 1}, monday, start.AddDate(0, 0, 1))), "days in the user's timezone")
+		assert.True(t, kept(booking(BookingLimits{PerWeek: 1}, previousSunday)), "weeks start on Monday")
+		assert.True(t, kept(booking(BookingLimits{MinGapMinutes: 60}, start.Add(-90*time.Minute), start.Add(90*time.Minute))), "gaps from the end of a review")
+		assert.True(t, kept(booking(BookingLimits{MinGapMinutes: 20}, start.Add(-50*time.Minute))), "a gap of one review and 20 minutes")
+	})
+
+	// Only bookings the chain keeps are held back: a booking no policy kept is still
+	// left to the non-whitelist cancel delay, and other decisions stand
+	t.Run("NotWhitelistedOverDailyCap", func(t *testing.T) {
+		b := booking(BookingLimits{PerDay: 1, Decision: Ask}, sameDayEarly)
+		b.Entries = nil
+		assert.Equal(t, Result{Decision: Decline, Policy: Default}, chain.Decide(b))
+	})
+	t.Run("KeepsOtherDecisions", func(t *testing.T) {
+		b := booking(BookingLimits{PerDay: 1, Decision: Decline}, sameDayEarly)
+		b.Rules = []*store.DecisionRule{{Action: rules.ActionAsk, Expression: "hour >= 18"}}
+		assert.Equal(t, Result{Decision: Ask, Policy: Rules, Rule: b.Rules[0]}, chain.Decide(b))
+	})
+	t.Run("AfterWhitelist", func(t *testing.T) {
+		b := booking(BookingLimits{PerDay: 1, Decision: Decline}, sameDayEarly)
+		result := NewChain([]string{DenyList, Whitelist, DailyCap}).Decide(b)
+		assert.Equal(t, Approve, result.Decision, "whitelisted projects are exempt")
+	})
+}
+
+func TestPeersPolicy(t *testing.T) {
+	friend := &store.Peer{Login: "friendly", Kind: store.PeerTrusted}
+	mate := &store.Peer{Login: "groupmate", Kind: store.PeerTrusted}
+	rival := &store.Peer{Login: "careless", Kind: store.PeerBlocked}
+	peers := []*store.Peer{friend, mate, rival}
+	booking := func(blocked Decision, reviewees ...string) *Booking {
+		return &Booking{ProjectName: "CPP1_s21_matrix+", Reviewees: reviewees, Peers: peers, BlockedPeers: blocked}
+	}
+	chain := NewChain([]string{Peers})
+	byDefault := Result{Decision: Decline, Policy: Default}
+
+	tests := []struct {
+		name     string
+		booking  *Booking
+		expected Result
+	}{
+		{"UnknownReviewee", booking(Ask), byDefault},
+		{"NoPeers", &Booking{Reviewees: []string{"friendly"}}, byDefault},
+		{"Trusted", booking(Ask, "Friendly"), Result{Decision: Approve, Policy: Peers, Peer: friend}},
+		{"TrustedTeam", booking(Ask, "groupmate", "friendly"), Result{Decision: Approve, Policy: Peers, Peer: mate}},
+		{"TeamWithStranger", booking(Ask, "friendly", "stranger"), byDefault},
+		{"BlockedAsk", booking(Ask, "careless"), Result{Decision: Ask, Policy: Peers, Peer: rival}},
+		{"BlockedByDefaultAsks", booking(Abstain, "careless"), Result{Decision: Ask, Policy: Peers, Peer: rival}},
+		{"BlockedDecline", booking(Decline, "careless"), Result{Decision: Decline, Policy: Peers, Peer: rival}},
+		{"BlockedInTeam", booking(Decline, "friendly", "stranger", "careless"), Result{Decision: Decline, Policy: Peers, Peer: rival}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, chain.Decide(tt.booking))
+		})
+	}
+}
+
+func TestWorkingHoursPolicy(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	hours, err := availability.ParseWorkingHours("Mon-Fri 10:00-22:00")
+	require.NoError(t, err)
+
+	inside := time.Date(2026, 3, 6, 21, 45, 0, 0, moscow)    // Friday
+	outside := time.Date(2026, 3, 6, 19, 30, 0, 0, time.UTC) // Friday 22:30 in Moscow
+	blacklisted := &models.WhitelistEntry{EntryType: whitelist.BlacklistType(models.EntryTypeProject), Name: "DO1_Linux"}
+	whitelisted := &models.WhitelistEntry{EntryType: models.EntryTypeProject, Name: "CPP1_s21_matrix+"}
+	asking := &store.DecisionRule{Action: rules.ActionAsk, Expression: `project == "CPP2_s21_containers"`}
+	booking := func(project string, start time.Time, outsideHours Decision) *Booking {
+		return &Booking{ProjectName: project, Start: start, WorkingHours: hours, OutsideHours: outsideHours,
+			Entries: []*models.WhitelistEntry{blacklisted, whitelisted}, Rules: []*store.DecisionRule{asking},
+			Location: moscow, Now: start.Add(-time.Hour)}
+	}
+	limit := &Limit{Key: WorkingHours, Hours: "Mon-Fri 10:00-22:00"}
+	chain := NewChain(DefaultChain)
+
+	tests := []struct {
+		name     string
+		booking  *Booking
+		expected Decision
+		policy   string
+	}{
+		{"Inside", booking("CPP1_s21_matrix+", inside, Decline), Approve, Whitelist},
+		{"NoWorkingHours", &Booking{ProjectName: "CPP1_s21_matrix+", Start: outside, Entries: []*models.WhitelistEntry{whitelisted}, Location: moscow}, Approve, Whitelist},
+		{"AskInsteadOfApprove", booking("CPP1_s21_matrix+", outside, Ask), Ask, WorkingHours},
+		{"AskKeepsAsked", booking("CPP2_s21_containers", outside, Ask), Ask, Rules},
+		{"AskKeepsDefault", booking("CPP3_SmartCalc", outside, Ask), Decline, Default},
+		{"DeclineApproved", booking("CPP1_s21_matrix+", outside, Decline), Decline, WorkingHours},
+		{"DeclineAsked", booking("CPP2_s21_containers", outside, Decline), Decline, WorkingHours},
+		{"DeclineDefaultRightAway", booking("CPP3_SmartCalc", outside, Decline), Decline, WorkingHours},
+		{"DeclineKeepsDenyList", booking("DO1_Linux", outside, Decline), Decline, DenyList},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := chain.Decide(tt.booking)
+			assert.Equal(t, tt.expected, result.Decision)
+			assert.Equal(t, tt.policy, result.Policy)
+			if tt.policy == WorkingHours {
+				assert.Equal(t, limit, result.Limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package projects
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+)
+
+// Family is a project family with its projects, sorted by name
+type Family struct {
+	Label    string
+	Projects []string
+}
+
+// Catalog holds the known families and projects from project_families, sorted by name.
+// Families and projects are added to the table at any time, shifting the positions of
+// later ones, so messages refer to them by ID rather than by position
+type Catalog struct {
+	Families []Family
+}
+
+// New builds a catalog from project_families rows
+func New(rows []*models.ProjectFamily) *Catalog {
+	byLabel := make(map[string]*Family)
+	for _, row := range rows {
+		family, ok := byLabel[row.FamilyLabel]
+		if !ok {
+			family = &Family{Label: row.FamilyLabel}
+			byLabel[row.FamilyLabel] = family
+		}
+		family.Projects = append(family.Projects, row.ProjectName)
+	}
+
+	c := &Catalog{Families: make([]Family, 0, len(byLabel))}
+	for _, family := range byLabel {
+		sort.Strings(family.Projects)
+		c.Families = append(c.Families, *family)
+	}
+	sort.Slice(c.Families, func(i, j int) bool {
+		return c.Families[i].Label < c.Families[j].Label
+	})
+	return c
+}
+
+// Load reads the catalog from project_families
+func Load(ctx context.Context) (*Catalog, error) {
+	rows, err := ydb.GetAllProjectFamilies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load project families: %w", err)
+	}
+	return New(rows), nil
+}
+
+// Empty reports whether no families are known yet
+func (c *Catalog) Empty() bool {
+	return len(c.Families) == 0
+}
+
+// Family returns the family at index i, or nil
+func (c *Catalog) Family(i int) *Family {
+	if i < 0 || i >= len(c.Families) {
+		return nil
+	}
+	return &c.Families[i]
+}
+
+// Project returns the name of project j of family i
+func (c *Catalog) Project(i, j int) (string, bool) {
+	family := c.Family(i)
+	if family == nil || j < 0 || j >= len(family.Projects) {
+		return "", false
+	}
+	return family.Projects[j], true
+}
+
+// ID returns the short identifier of a family label or project name. Callback data is
+// limited to 64 bytes, which not every name fits, and unlike a position in the catalog the
+// ID of a name does not change as families and projects are added
+func ID(name string) string {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return strconv.FormatUint(h.Sum64()&(1<<48-1), 36)
+}
+
+// Lookup returns the position of the family with the given ID, or of its project with
+// projectID unless that is empty. ok is false for names no longer in the catalog
+func (c *Catalog) Lookup(familyID, projectID string) (pos Position, ok bool) {
+	for i, family := range c.Families {
+		if ID(family.Label) != familyID {
+			continue
+		}
+		if projectID == "" {
+			return Position{Family: i, Project: -1}, true
+		}
+		for j, project := range family.Projects {
+			if ID(project) == projectID {
+				return Position{Family: i, Project: j}, true
+			}
+		}
+		return Position{}, false
+	}
+	return Position{}, false
+}
+
+// Position is the place of a family or project in the catalog. Project is -1 for a family
+type Position struct {
+	Family  int
+	Project int
+}
+
+// Find returns the position of a family or project with the given name, ignoring case
+func (c *Catalog) Find(entryType, name string) (Position, bool) {
+	name = strings.TrimSpace(name)
+	for i, family := range c.Families {
+		if entryType == models.EntryTypeFamily {
+			if strings.EqualFold(family.Label, name) {
+				return Position{Family: i, Project: -1}, true
+			}
+			continue
+		}
+		for j, project := range family.Projects {
+			if strings.EqualFold(project, name) {
+				return Position{Family: i, Project: j}, true
+			}
+		}
+	}
+	return Position{}, false
+}
+
+// Name returns the family label or project name at pos
+func (c *Catalog) Name(pos Position) string {
+	if pos.Project < 0 {
+		if family := c.Family(pos.Family); family != nil {
+			return family.Label
+		}
+		return ""
+	}
+	name, _ := c.Project(pos.Family, pos.Project)
+	return name
+}
+
+// IDs returns the IDs of the family at pos and of the project, which is empty for a family
+func (c *Catalog) IDs(pos Position) (family, project string) {
+	f := c.Family(pos.Family)
+	if f == nil {
+		return "", ""
+	}
+	if pos.Project < 0 {
+		return ID(f.Label), ""
+	}
+	return ID(f.Label), ID(c.Name(pos))
+}
+
+// Suggest returns up to limit families or projects with a name close to the given one,
+// closest first. Names containing the given one count as close
+func (c *Catalog) Suggest(entryType, name string, limit int) []Position {
+	type candidate struct {
+		pos      Position
+		distance int
+	}
+
+	query := strings.ToLower(strings.TrimSpace(name))
+	maxDistance := utf8.RuneCountInString(query)/3 + 1
+
+	var candidates []candidate
+	consider := func(pos Position, candidateName string) {
+		lower := strings.ToLower(candidateName)
+		distance := Distance(query, lower)
+		if query != "" && strings.Contains(lower, query) {
+			distance = 0
+		}
+		if distance <= maxDistance {
+			candidates = append(candidates, candidate{pos: pos, distance: distance})
+		}
+	}
+
+	for i, family := range c.Families {
+		if entryType == models.EntryTypeFamily {
+			consider(Position{Family: i, Project: -1}, family.Label)
+			continue
+		}
+		for j, project := range family.Projects {
+			consider(Position{Family: i, Project: j}, project)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	var result []Position
+	for _, cand := range candidates {
+		if len(result) == limit {
+			break
+		}
+		result = append(result, cand.pos)
+	}
+	return result
+}
+
+// Distance returns the Levenshtein distance between a and b, counted in runes
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,170 @@
+package projects
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+)
+
+func testCatalog() *Catalog {
+	return New([]*models.ProjectFamily{
+		{FamilyLabel: "C - I", ProjectName: "C3_SimpleBashUtils"},
+		{FamilyLabel: "Go", ProjectName: "go-concurrency"},
+		{FamilyLabel: "C - I", ProjectName: "C2_s21_stringplus"},
+		{FamilyLabel: "Go", ProjectName: "go-boilerplate"},
+		{FamilyLabel: "DevOps", ProjectName: "DO1_Linux"},
+	})
+}
+
+func TestNew(t *testing.T) {
+	c := testCatalog()
+
+	require.Len(t, c.Families, 3)
+	assert.Equal(t, Family{Label: "C - I", Projects: []string{"C2_s21_stringplus", "C3_SimpleBashUtils"}}, c.Families[0])
+	assert.Equal(t, "DevOps", c.Families[1].Label)
+	assert.Equal(t, []string{"go-boilerplate", "go-concurrency"}, c.Families[2].Projects)
+
+	assert.False(t, c.Empty())
+	assert.True(t, New(nil).Empty())
+}
+
+func TestCatalog_Lookups(t *testing.T) {
+	c := testCatalog()
+
+	assert.Nil(t, c.Family(-1))
+	assert.Nil(t, c.Family(3))
+	assert.Equal(t, "Go", c.Family(2).Label)
+
+	project, ok := c.Project(2, 1)
+	assert.True(t, ok)
+	assert.Equal(t, "go-concurrency", project)
+	_, ok = c.Project(2, 2)
+	assert.False(t, ok)
+
+	assert.Equal(t, "DevOps", c.Name(Position{Family: 1, Project: -1}))
+	assert.Equal(t, "DO1_Linux", c.Name(Position{Family: 1, Project: 0}))
+	assert.Empty(t, c.Name(Position{Family: 5, Project: -1}))
+}
+
+func TestCatalog_Lookup(t *testing.T) {
+	c := testCatalog()
+
+	pos, ok := c.Lookup(ID("Go"), ID("go-concurrency"))
+	assert.True(t, ok)
+	assert.Equal(t, Position{Family: 2, Project: 1}, pos)
+	pos, ok = c.Lookup(ID("DevOps"), "")
+	assert.True(t, ok)
+	assert.Equal(t, Position{Family: 1, Project: -1}, pos)
+
+	familyID, projectID := c.IDs(pos)
+	assert.Equal(t, ID("DevOps"), familyID)
+	assert.Empty(t, projectID)
+	familyID, projectID = c.IDs(Position{Family: 2, Project: 1})
+	assert.Equal(t, ID("Go"), familyID)
+	assert.Equal(t, ID("go-concurrency"), projectID)
+
+	_, ok = c.Lookup(ID("Go"), ID("DO1_Linux"))
+	assert.False(t, ok, "the project belongs to another family")
+	_, ok = c.Lookup(ID("Rust"), "")
+	assert.False(t, ok)
+
+	// Adding a family before Go shifts its position but not its ID
+	grown := New(append([]*models.ProjectFamily{{FamilyLabel: "Fortran", ProjectName: "F1_Hello"}}, allRows(c)...))
+	pos, ok = grown.Lookup(ID("Go"), ID("go-concurrency"))
+	assert.True(t, ok)
+	assert.Equal(t, Position{Family: 3, Project: 1}, pos)
+
+	assert.LessOrEqual(t, len(ID("a project name that is far too long to fit into callback data on its own")), 10)
+}
+
+// allRows returns the project_families rows of a catalog
+func allRows(c *Catalog) []*models.ProjectFamily {
+	var rows []*models.ProjectFamily
+	for _, family := range c.Families {
+		for _, project := range family.Projects {
+			rows = append(rows, &models.ProjectFamily{FamilyLabel: family.Label, ProjectName: project})
+		}
+	}
+	return rows
+}
+
+func TestCatalog_Find(t *testing.T) {
+	c := testCatalog()
+
+	tests := []struct {
+		name      string
+		entryType string
+		query     string
+		expected  Position
+		found     bool
+	}{
+		{"Family", models.EntryTypeFamily, "C - I", Position{Family: 0, Project: -1}, true},
+		{"FamilyIgnoresCase", models.EntryTypeFamily, " devops ", Position{Family: 1, Project: -1}, true},
+		{"Project", models.EntryTypeProject, "GO-CONCURRENCY", Position{Family: 2, Project: 1}, true},
+		{"ProjectIsNotFamily", models.EntryTypeFamily, "go-concurrency", Position{}, false},
+		{"Typo", models.EntryTypeProject, "go-concurency", Position{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos, found := c.Find(tt.entryType, tt.query)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.expected, pos)
+		})
+	}
+}
+
+func TestCatalog_Suggest(t *testing.T) {
+	c := testCatalog()
+
+	tests := []struct {
+		name      string
+		entryType string
+		query     string
+		expected  []string
+	}{
+		{"Typo", models.EntryTypeProject, "go-concurency", []string{"go-concurrency"}},
+		{"Substring", models.EntryTypeProject, "bash", []string{"C3_SimpleBashUtils"}},
+		{"SeveralClosestFirst", models.EntryTypeProject, "go-", []string{"go-boilerplate", "go-concurrency"}},
+		{"FamilyTypo", models.EntryTypeFamily, "Devops", []string{"DevOps"}},
+		{"FamilyWithoutSpaces", models.EntryTypeFamily, "C-I", []string{"C - I"}},
+		{"NothingClose", models.EntryTypeProject, "kubernetes", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, pos := range c.Suggest(tt.entryType, tt.query, 3) {
+				names = append(names, c.Name(pos))
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+
+	t.Run("Limit", func(t *testing.T) {
+		assert.Len(t, c.Suggest(models.EntryTypeProject, "o", 1), 1)
+	})
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"go-concurency", "go-concurrency", 1},
+		{"проект", "прокт", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Distance(tt.a, tt.b))
+			assert.Equal(t, tt.expected, Distance(tt.b, tt.a))
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// ParseMode is the Telegram parse mode every bot message is rendered for
+const ParseMode = tba.ModeHTML
+
+// MaxMessageLength is the Telegram limit for the text of a single message
+const MaxMessageLength = 4096
+
+// HTML is text that is safe to send with ParseMode. Plain strings become HTML
+// only through Escape or Format
+type HTML string
+
+// Sender sends Telegram requests, e.g. *tba.BotAPI
+type Sender interface {
+	Send(c tba.Chattable) (tba.Message, error)
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Markup of message templates: *bold* and `code`, never spanning lines
+var (
+	boldPattern = regexp.MustCompile(`\*([^*\n]+)\*`)
+	codePattern = regexp.MustCompile("`([^`\n]+)`")
+)
+
+// Escape turns plain text into HTML
+func Escape(s string) HTML {
+	return HTML(escaper.Replace(s))
+}
+
+// Format renders a message template. The template is plain text with *bold* and `code`
+// markup and fmt verbs. Strings, errors and fmt.Stringer arguments are escaped,
+// HTML arguments are inserted as they are
+func Format(template string, args ...interface{}) HTML {
+	format := string(Escape(template))
+	format = boldPattern.ReplaceAllString(format, "<b>$1</b>")
+	format = codePattern.ReplaceAllString(format, "<code>$1</code>")
+	if len(args) == 0 {
+		return HTML(format)
+	}
+
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		escaped[i] = escapeArg(arg)
+	}
+	return HTML(fmt.Sprintf(format, escaped...))
+}
+
+func escapeArg(arg interface{}) interface{} {
+	switch v := arg.(type) {
+	case HTML:
+		return v
+	case string:
+		return Escape(v)
+	case error:
+		return Escape(v.Error())
+	case fmt.Stringer:
+		return Escape(v.String())
+	default:
+		return arg
+	}
+}
+
+// Join concatenates parts with a plain text separator
+func Join(parts []HTML, sep string) HTML {
+	strs := make([]string, len(parts))
+	for i, part := range parts {
+		strs[i] = string(part)
+	}
+	return HTML(strings.Join(strs, string(Escape(sep))))
+}
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// Plain turns HTML back into plain text, for texts Telegram shows without a parse
+// mode such as callback answers and button labels
+func Plain(text HTML) string {
+	return html.UnescapeString(tagPattern.ReplaceAllString(string(text), ""))
+}
+
+// Split breaks text into parts of at most limit characters. Parts end at line breaks
+// where possible; longer lines are cut without splitting a tag or an entity
+func Split(text HTML, limit int) []HTML {
+	s := string(text)
+	if utf8.RuneCountInString(s) <= limit {
+		return []HTML{text}
+	}
+
+	var parts []HTML
+	var current strings.Builder
+	currentLen := 0
+
+	flush := func() {
+		if currentLen > 0 {
+			parts = append(parts, HTML(strings.TrimRight(current.String(), "\n")))
+			current.Reset()
+			currentLen = 0
+		}
+	}
+
+	for _, line := range strings.SplitAfter(s, "\n") {
+		lineLen := utf8.RuneCountInString(line)
+		if currentLen+lineLen > limit {
+			flush()
+		}
+		for lineLen > limit {
+			head, tail := cutLine(line, limit)
+			parts = append(parts, HTML(head))
+			line = tail
+			lineLen = utf8.RuneCountInString(line)
+		}
+		current.WriteString(line)
+		currentLen += lineLen
+	}
+	flush()
+
+	return parts
+}
+
+// cutLine cuts line after at most limit runes, moving the cut back out of tags and
+// entities and in front of tags that would be left open
+func cutLine(line string, limit int) (string, string) {
+	cut := 0
+	for i := 0; i < limit; i++ {
+		_, size := utf8.DecodeRuneInString(line[cut:])
+		cut += size
+	}
+
+	head := line[:cut]
+	if open := strings.LastIndexAny(head, "<&"); open > 0 {
+		closing := ">"
+		if head[open] == '&' {
+			closing = ";"
+		}
+		if !strings.Contains(head[open:], closing) {
+			cut = open
+		}
+	}
+
+	var unclosed []int
+	for _, loc := range tagPattern.FindAllStringIndex(line[:cut], -1) {
+		if strings.HasPrefix(line[loc[0]:], "</") {
+			if len(unclosed) > 0 {
+				unclosed = unclosed[:len(unclosed)-1]
+			}
+			continue
+		}
+		unclosed = append(unclosed, loc[0])
+	}
+	if len(unclosed) > 0 && unclosed[0] > 0 {
+		cut = unclosed[0]
+	}
+
+	return line[:cut], line[cut:]
+}
+
+// Send sends text to a chat, split into several messages if it is too long.
+// The keyboard, if any, is attached to the last message, whose ID is returned
+func Send(bot Sender, chatID int64, text HTML, keyboard *tba.InlineKeyboardMarkup) (int, error) {
+	parts := Split(text, MaxMessageLength)
+
+	messageID := 0
+	for i, part := range parts {
+		msg := tba.NewMessage(chatID, string(part))
+		msg.ParseMode = ParseMode
+		if keyboard != nil && i == len(parts)-1 {
+			msg.ReplyMarkup = *keyboard
+		}
+
+		sent, err := bot.Send(msg)
+		if err != nil {
+			return 0, fmt.Errorf("failed to send message: %w", err)
+		}
+		messageID = sent.MessageID
+	}
+
+	return messageID, nil
+}
+
+// Edit replaces the text and keyboard of a message. A message cannot grow into
+// several, so text over the limit keeps only its first part
+func Edit(bot Sender, chatID int64, messageID int, text HTML, keyboard *tba.InlineKeyboardMarkup) error {
+	msg := tba.NewEditMessageText(chatID, messageID, string(Split(text, MaxMessageLength)[0]))
+	msg.ParseMode = ParseMode
+	msg.ReplyMarkup = keyboard
+
+	if _, err := bot.Send(msg); err != nil {
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package render
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	tba "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSender struct {
+	sent []tba.Chattable
+}
+
+func (f *fakeSender) Send(c tba.Chattable) (tba.Message, error) {
+	f.sent = append(f.sent, c)
+	return tba.Message{MessageID: len(f.sent)}, nil
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, HTML("a &lt;b&gt; &amp; c"), Escape("a <b> & c"))
+	assert.Equal(t, HTML("*not bold* `x`"), Escape("*not bold* `x`"))
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		args     []interface{}
+		expected HTML
+	}{
+		{"Plain", "Hello", nil, "Hello"},
+		{"Bold", "*Your Settings*\n\nDone", nil, "<b>Your Settings</b>\n\nDone"},
+		{"Code", "Send `login:password`", nil, "Send <code>login:password</code>"},
+		{"TemplateEscaped", "Usage: /slots <days> & more", nil, "Usage: /slots &lt;days&gt; &amp; more"},
+		{"BoldDoesNotSpanLines", "a * b\nc * d", nil, "a * b\nc * d"},
+		{"StringArg", "Project: %s", []interface{}{"<C - I> & *x*"}, "Project: &lt;C - I&gt; &amp; *x*"},
+		{"ErrorArg", "Failed: %v", []interface{}{errors.New("bad <input>")}, "Failed: bad &lt;input&gt;"},
+		{"StringerArg", "Zone %s", []interface{}{time.UTC}, "Zone UTC"},
+		{"HTMLArg", "%s\n\nUsage", []interface{}{HTML("<b>Error</b>")}, "<b>Error</b>\n\nUsage"},
+		{"NumberArg", "%d. %s", []interface{}{3, "go_concurrency"}, "3. go_concurrency"},
+		{"IndexedArgs", "%[2]d %[1]s", []interface{}{"Jan", 15}, "15 Jan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Format(tt.template, tt.args...))
+		})
+	}
+}
+
+func TestJoin(t *testing.T) {
+	assert.Equal(t, HTML("<b>a</b> &amp; b"), Join([]HTML{"<b>a</b>", "b"}, " & "))
+	assert.Equal(t, HTML(""), Join(nil, "\n"))
+}
+
+func TestPlain(t *testing.T) {
+	assert.Equal(t, "Slot <1> & more", Plain(Format("*Slot* %s", "<1> & more")))
+}
+
+func TestSplit(t *testing.T) {
+	t.Run("Short", func(t *testing.T) {
+		assert.Equal(t, []HTML{"a\nb"}, Split("a\nb", 10))
+	})
+
+	t.Run("AtLineBreaks", func(t *testing.T) {
+		parts := Split("aaaa\nbbbb\ncccc", 10)
+		assert.Equal(t, []HTML{"aaaa\nbbbb", "cccc"}, parts)
+	})
+
+	t.Run("LongLine", func(t *testing.T) {
+		parts := Split(HTML(strings.Repeat("x", 25)), 10)
+		assert.Equal(t, []HTML{"xxxxxxxxxx", "xxxxxxxxxx", "xxxxx"}, parts)
+	})
+
+	t.Run("KeepsEntities", func(t *testing.T) {
+		parts := Split("xxxxxxx&amp;yy", 10)
+		assert.Equal(t, []HTML{"xxxxxxx", "&amp;yy"}, parts)
+	})
+
+	t.Run("KeepsTags", func(t *testing.T) {
+		parts := Split("xxxxxx<b>y</b>", 10)
+		assert.Equal(t, []HTML{"xxxxxx", "<b>y</b>"}, parts)
+	})
+
+	t.Run("CountsRunes", func(t *testing.T) {
+		text := HTML(strings.Repeat("ж", 10))
+		assert.Equal(t, []HTML{text}, Split(text, 10))
+	})
+
+	t.Run("TelegramLimit", func(t *testing.T) {
+		line := strings.Repeat("я", 99) + "\n"
+		parts := Split(HTML(strings.Repeat(line, 100)), MaxMessageLength)
+		require.Len(t, parts, 3)
+		for _, part := range parts {
+			assert.LessOrEqual(t, utf8.RuneCountInString(string(part)), MaxMessageLength)
+		}
+	})
+}
+
+func TestSend(t *testing.T) {
+	keyboard := tba.NewInlineKeyboardMarkup(tba.NewInlineKeyboardRow(tba.NewInlineKeyboardButtonData("OK", "ok")))
+
+	t.Run("Single", func(t *testing.T) {
+		bot := &fakeSender{}
+		id, err := Send(bot, 42, "<b>Hi</b>", &keyboard)
+		require.NoError(t, err)
+		assert.Equal(t, 1, id)
+
+		require.Len(t, bot.sent, 1)
+		msg := bot.sent[0].(tba.MessageConfig)
+		assert.Equal(t, int64(42), msg.ChatID)
+		assert.Equal(t, "<b>Hi</b>", msg.Text)
+		assert.Equal(t, ParseMode, msg.ParseMode)
+		assert.Equal(t, keyboard, msg.ReplyMarkup)
+	})
+
+	t.Run("SplitKeepsKeyboardOnLastMessage", func(t *testing.T) {
+		bot := &fakeSender{}
+		text := HTML(strings.Repeat(strings.Repeat("x", 100)+"\n", 50))
+		id, err := Send(bot, 42, text, &keyboard)
+		require.NoError(t, err)
+
+		require.Len(t, bot.sent, 2)
+		assert.Equal(t, 2, id)
+		assert.Nil(t, bot.sent[0].(tba.MessageConfig).ReplyMarkup)
+		assert.Equal(t, keyboard, bot.sent[1].(tba.MessageConfig).ReplyMarkup)
+	})
+
+	t.Run("NoKeyboard", func(t *testing.T) {
+		bot := &fakeSender{}
+		_, err := Send(bot, 42, "Hi", nil)
+		require.NoError(t, err)
+		assert.Nil(t, bot.sent[0].(tba.MessageConfig).ReplyMarkup)
+	})
+}
+
+func TestEdit(t *testing.T) {
+	bot := &fakeSender{}
+	require.NoError(t, Edit(bot, 42, 7, "<b>Done</b>", nil))
+
+	require.Len(t, bot.sent, 1)
+	msg := bot.sent[0].(tba.EditMessageTextConfig)
+	assert.Equal(t, 7, msg.MessageID)
+	assert.Equal(t, "<b>Done</b>", msg.Text)
+	assert.Equal(t, ParseMode, msg.ParseMode)
+	assert.Nil(t, msg.ReplyMarkup)
+}
@@ -0,0 +1,526 @@
+package rules
+
+import (
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+)
+
+// kind is the type of a rule value
+type kind int
+
+const (
+	kindBool kind = iota
+	kindNumber
+	kindText
+)
+
+// label names the kind in error messages, in the user's language
+func (k kind) label() error {
+	switch k {
+	case kindNumber:
+		return i18n.Errorf("rule_error.kind_number")
+	case kindText:
+		return i18n.Errorf("rule_error.kind_text")
+	default:
+		return i18n.Errorf("rule_error.kind_bool")
+	}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokText
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // byte offset in the source
+}
+
+// operators lists the operator tokens, longest first so "<=" is not read as "<"
+var operators = []string{"==", "!=", "<=", ">=", "&&", "||", "..", "<", ">", "!", "(", ")", "[", "]", ","}
+
+// lex splits a rule into tokens
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+
+		case isLetter(c):
+			j := i
+			for j < len(src) && (isLetter(src[j]) || isDigit(src[j])) {
+				j++
+			}
+			tokens = append(tokens, token{tokIdent, src[i:j], i})
+			i = j
+
+		case isDigit(c):
+			j := i
+			for j < len(src) && isDigit(src[j]) {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, src[i:j], i})
+			i = j
+
+		case c == '"':
+			j := strings.IndexByte(src[i+1:], '"')
+			if j < 0 {
+				return nil, i18n.Errorf("rule_error.unterminated", i+1)
+			}
+			tokens = append(tokens, token{tokText, src[i+1 : i+1+j], i})
+			i += j + 2
+
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				r, _ := utf8.DecodeRuneInString(src[i:])
+				return nil, i18n.Errorf("rule_error.unexpected", string(r), i+1)
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(src)}), nil
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parser is a recursive descent parser over the grammar
+//
+//	or         = and { "||" and }
+//	and        = not { "&&" not }
+//	not        = "!" not | comparison
+//	comparison = primary [ ("==" | "!=" | "<" | "<=" | ">" | ">=") primary
+//	                     | ["not"] "in" list | "matches" text ]
+//	list       = "[" [ item { "," item } ] "]"
+//	item       = primary [ ".." primary ]
+//	primary    = number | text | "true" | "false" | attribute | "(" or ")"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the given operator or keyword
+func (p *parser) accept(text string) bool {
+	tok := p.peek()
+	if (tok.kind == tokOp || tok.kind == tokIdent) && tok.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		tok := p.peek()
+		if tok.kind == tokEOF {
+			return i18n.Errorf("rule_error.expected_end", text)
+		}
+		return i18n.Errorf("rule_error.expected", text, tok.pos+1)
+	}
+	return nil
+}
+
+func (p *parser) unexpected(tok token) error {
+	if tok.kind == tokEOF {
+		return i18n.Errorf("rule_error.ends_early")
+	}
+	return i18n.Errorf("rule_error.unexpected", tok.text, tok.pos+1)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); p.accept("||"); tok = p.peek() {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = newLogic(tok, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); p.accept("&&"); tok = p.peek() {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = newLogic(tok, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if tok := p.peek(); p.accept("!") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if x.kind() != kindBool {
+			return nil, i18n.Errorf("rule_error.not_needs", tok.pos+1, x.kind().label())
+		}
+		return notNode{x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	switch {
+	case tok.kind == tokOp && isComparison(tok.text):
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return newComparison(tok, left, right)
+
+	case tok.kind == tokIdent && (tok.text == "in" || tok.text == "not"):
+		p.next()
+		if tok.text == "not" {
+			if err := p.expect("in"); err != nil {
+				return nil, err
+			}
+		}
+		items, err := p.parseList(left.kind())
+		if err != nil {
+			return nil, err
+		}
+		return inNode{x: left, items: items, negate: tok.text == "not"}, nil
+
+	case tok.kind == tokIdent && tok.text == "matches":
+		p.next()
+		pattern := p.next()
+		if pattern.kind != tokText {
+			return nil, i18n.Errorf("rule_error.matches_pattern", tok.pos+1)
+		}
+		if left.kind() != kindText {
+			return nil, i18n.Errorf("rule_error.matches_text", tok.pos+1, left.kind().label())
+		}
+		if _, err := path.Match(pattern.text, ""); err != nil {
+			return nil, i18n.Errorf("rule_error.bad_pattern", pattern.text, pattern.pos+1)
+		}
+		return matchNode{x: left, pattern: strings.ToLower(pattern.text)}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseList(elem kind) ([]listItem, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	var items []listItem
+	if p.accept("]") {
+		return items, nil
+	}
+
+	for {
+		at := p.peek().pos + 1
+		lo, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		item := listItem{lo: lo}
+		if p.accept("..") {
+			if item.hi, err = p.parsePrimary(); err != nil {
+				return nil, err
+			}
+			if lo.kind() != kindNumber || item.hi.kind() != kindNumber {
+				return nil, i18n.Errorf("rule_error.range", at)
+			}
+		}
+		if lo.kind() != elem {
+			return nil, i18n.Errorf("rule_error.list_kind", at, lo.kind().label(), elem.label())
+		}
+		items = append(items, item)
+
+		if p.accept(",") {
+			continue
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		n, err := strconv.Atoi(tok.text)
+		if err != nil {
+			return nil, i18n.Errorf("rule_error.too_large", tok.text, tok.pos+1)
+		}
+		return literal{n, kindNumber}, nil
+
+	case tokText:
+		return literal{tok.text, kindText}, nil
+
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return literal{true, kindBool}, nil
+		case "false":
+			return literal{false, kindBool}, nil
+		}
+		for _, attr := range attributes {
+			if attr.name == tok.text {
+				return attrNode{attr}, nil
+			}
+		}
+		return nil, i18n.Errorf("rule_error.unknown_attr", tok.text, tok.pos+1, strings.Join(AttributeNames(), ", "))
+
+	case tokOp:
+		if tok.text == "(" {
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+	}
+	return nil, p.unexpected(tok)
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+// node is a checked expression; eval returns a bool, int or string matching its kind
+type node interface {
+	kind() kind
+	eval(e *evaluator) (interface{}, error)
+}
+
+type literal struct {
+	value interface{}
+	k     kind
+}
+
+func (l literal) kind() kind { return l.k }
+
+func (l literal) eval(e *evaluator) (interface{}, error) {
+	return l.value, e.step()
+}
+
+type attrNode struct {
+	attr attribute
+}
+
+func (a attrNode) kind() kind { return a.attr.kind }
+
+func (a attrNode) eval(e *evaluator) (interface{}, error) {
+	return a.attr.get(e.attrs), e.step()
+}
+
+type notNode struct {
+	x node
+}
+
+func (n notNode) kind() kind { return kindBool }
+
+func (n notNode) eval(e *evaluator) (interface{}, error) {
+	v, err := n.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	return !v.(bool), e.step()
+}
+
+type logicNode struct {
+	op          string
+	left, right node
+}
+
+// newLogic joins two conditions with the operator tok
+func newLogic(tok token, left, right node) (node, error) {
+	if left.kind() != kindBool || right.kind() != kindBool {
+		return nil, i18n.Errorf("rule_error.joins", tok.text, tok.pos+1)
+	}
+	return logicNode{tok.text, left, right}, nil
+}
+
+func (n logicNode) kind() kind { return kindBool }
+
+func (n logicNode) eval(e *evaluator) (interface{}, error) {
+	l, err := n.left.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	if l.(bool) == (n.op == "||") {
+		return l, nil
+	}
+	return n.right.eval(e)
+}
+
+type comparison struct {
+	op          string
+	left, right node
+}
+
+// newComparison compares two values with the operator tok
+func newComparison(tok token, left, right node) (node, error) {
+	if left.kind() != right.kind() {
+		return nil, i18n.Errorf("rule_error.compare", left.kind().label(), right.kind().label(), tok.pos+1)
+	}
+	if left.kind() != kindNumber && tok.text != "==" && tok.text != "!=" {
+		return nil, i18n.Errorf("rule_error.numbers_only", tok.text, tok.pos+1, left.kind().label())
+	}
+	return comparison{tok.text, left, right}, nil
+}
+
+func (c comparison) kind() kind { return kindBool }
+
+func (c comparison) eval(e *evaluator) (interface{}, error) {
+	l, err := c.left.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.right.eval(e)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	}
+	a, b := l.(int), r.(int)
+	switch c.op {
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	case ">":
+		return a > b, nil
+	default:
+		return a >= b, nil
+	}
+}
+
+// equal compares two values of the same kind. Text is compared ignoring case, so
+// project == "cpp1_s21_matrix+" holds for CPP1_s21_matrix+ too
+func equal(a, b interface{}) bool {
+	if s, ok := a.(string); ok {
+		return strings.EqualFold(s, b.(string))
+	}
+	return a == b
+}
+
+type listItem struct {
+	lo, hi node // hi is nil unless the item is a range
+}
+
+type inNode struct {
+	x      node
+	items  []listItem
+	negate bool
+}
+
+func (n inNode) kind() kind { return kindBool }
+
+func (n inNode) eval(e *evaluator) (interface{}, error) {
+	v, err := n.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range n.items {
+		lo, err := item.lo.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		if item.hi == nil {
+			if equal(v, lo) {
+				return !n.negate, nil
+			}
+			continue
+		}
+		hi, err := item.hi.eval(e)
+		if err != nil {
+			return nil, err
+		}
+		if v.(int) >= lo.(int) && v.(int) <= hi.(int) {
+			return !n.negate, nil
+		}
+	}
+	return n.negate, nil
+}
+
+type matchNode struct {
+	x       node
+	pattern string // lower case, checked when compiled
+}
+
+func (n matchNode) kind() kind { return kindBool }
+
+func (n matchNode) eval(e *evaluator) (interface{}, error) {
+	v, err := n.x.eval(e)
+	if err != nil {
+		return nil, err
+	}
+	ok, _ := path.Match(n.pattern, strings.ToLower(v.(string)))
+	return ok, e.step()
+}
@@ -0,0 +1,178 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+)
+
+// MaxLength is the longest rule expression accepted, which also bounds evaluation
+const MaxLength = 300
+
+// EvalTimeout is how long a single rule may take to evaluate
+const EvalTimeout = 50 * time.Millisecond
+
+// ErrTimeout is returned when a rule takes longer than its evaluation timeout
+var ErrTimeout = i18n.Errorf("rule_error.timeout")
+
+// Rule actions, applied to a booking when its rule holds
+const (
+	ActionApprove = "approve"
+	ActionAsk     = "ask"
+	ActionDecline = "decline"
+)
+
+// Actions lists the rule actions in the order they are shown to users
+var Actions = []string{ActionApprove, ActionAsk, ActionDecline}
+
+// IsValidAction reports whether action is one of Actions
+func IsValidAction(action string) bool {
+	for _, a := range Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Attributes are the booking attributes a rule is evaluated against
+type Attributes struct {
+	Project      string
+	Family       string // empty if the family is unknown
+	Weekday      int    // 1 for Monday to 7 for Sunday, in the user's timezone
+	Hour         int    // start hour in the user's timezone
+	Minute       int
+	MinutesUntil int // minutes from now until the review starts
+	ReviewsToday int // other approved and whitelisted reviews on the same day
+}
+
+// attribute is a name rules can refer to
+type attribute struct {
+	name string
+	kind kind
+	get  func(a *Attributes) interface{}
+}
+
+// attributes lists the names rules can refer to, in the order they are shown to users
+var attributes = []attribute{
+	{"project", kindText, func(a *Attributes) interface{} { return a.Project }},
+	{"family", kindText, func(a *Attributes) interface{} { return a.Family }},
+	{"weekday", kindNumber, func(a *Attributes) interface{} { return a.Weekday }},
+	{"hour", kindNumber, func(a *Attributes) interface{} { return a.Hour }},
+	{"minute", kindNumber, func(a *Attributes) interface{} { return a.Minute }},
+	{"minutes_until", kindNumber, func(a *Attributes) interface{} { return a.MinutesUntil }},
+	{"reviews_today", kindNumber, func(a *Attributes) interface{} { return a.ReviewsToday }},
+}
+
+// AttributeNames returns the names rules can refer to
+func AttributeNames() []string {
+	names := make([]string, len(attributes))
+	for i, attr := range attributes {
+		names[i] = attr.name
+	}
+	return names
+}
+
+// FormatAttributes renders the attributes as a rule would see them, one per line
+func FormatAttributes(a Attributes) string {
+	lines := make([]string, len(attributes))
+	for i, attr := range attributes {
+		value := attr.get(&a)
+		if attr.kind == kindText {
+			value = fmt.Sprintf("%q", value)
+		}
+		lines[i] = fmt.Sprintf("%s = %v", attr.name, value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NewAttributes returns the attributes of a review starting at start, with times in loc.
+// accepted holds the start times of the user's other approved and whitelisted reviews
+func NewAttributes(project, family string, start time.Time, accepted []time.Time, loc *time.Location, now time.Time) Attributes {
+	local := start.In(loc)
+	weekday := int(local.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+
+	today := 0
+	year, month, day := local.Date()
+	for _, t := range accepted {
+		y, m, d := t.In(loc).Date()
+		if y == year && m == month && d == day {
+			today++
+		}
+	}
+
+	return Attributes{
+		Project:      project,
+		Family:       family,
+		Weekday:      weekday,
+		Hour:         local.Hour(),
+		Minute:       local.Minute(),
+		MinutesUntil: int(start.Sub(now).Minutes()),
+		ReviewsToday: today,
+	}
+}
+
+// Program is a compiled rule expression
+type Program struct {
+	root node
+}
+
+// Compile parses and type checks a rule expression such as
+// `family == "CPP" && weekday in [1..4] && hour >= 18`. Rules cannot loop or call
+// anything, so a compiled rule only reads the attributes it is evaluated against
+func Compile(source string) (*Program, error) {
+	source = strings.NewReplacer("“", `"`, "”", `"`, "„", `"`).Replace(strings.TrimSpace(source))
+	if source == "" {
+		return nil, i18n.Errorf("rule_error.empty")
+	}
+	if len(source) > MaxLength {
+		return nil, i18n.Errorf("rule_error.too_long", MaxLength)
+	}
+
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.unexpected(tok)
+	}
+	if root.kind() != kindBool {
+		return nil, i18n.Errorf("rule_error.not_condition")
+	}
+	return &Program{root: root}, nil
+}
+
+// Eval reports whether the rule holds for the attributes. It gives up with ErrTimeout
+// once timeout has passed
+func (p *Program) Eval(a Attributes, timeout time.Duration) (bool, error) {
+	e := &evaluator{attrs: &a, deadline: time.Now().Add(timeout)}
+	v, err := p.root.eval(e)
+	if err != nil {
+		return false, err
+	}
+	return v.(bool), nil
+}
+
+// evaluator holds the state of a single evaluation
+type evaluator struct {
+	attrs    *Attributes
+	deadline time.Time
+}
+
+// step is called for every node evaluated and stops the evaluation after its deadline
+func (e *evaluator) step() error {
+	if !time.Now().Before(e.deadline) {
+		return ErrTimeout
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+)
+
+func TestCompile_Eval(t *testing.T) {
+	attrs := Attributes{
+		Project:      "CPP1_s21_matrix+",
+		Family:       "CPP",
+		Weekday:      3,
+		Hour:         19,
+		Minute:       30,
+		MinutesUntil: 240,
+		ReviewsToday: 1,
+	}
+
+	tests := []struct {
+		name     string
+		rule     string
+		expected bool
+	}{
+		{"Example", `family == "CPP" && weekday in [1..4] && hour >= 18`, true},
+		{"FamilyIgnoresCase", `family == "cpp"`, true},
+		{"SmartQuotes", `family == “CPP”`, true},
+		{"Or", `hour < 9 || reviews_today == 1`, true},
+		{"Not", `!(weekday in [6, 7])`, true},
+		{"NotIn", `weekday not in [1..2, 4..7]`, true},
+		{"TextList", `family in ["C - I", "Go"]`, false},
+		{"Matches", `project matches "cpp*"`, true},
+		{"MatchesNoMatch", `project matches "C?_*"`, false},
+		{"Precedence", `false && true || true`, true},
+		{"EmptyList", `hour in []`, false},
+		{"MinutesUntil", `minutes_until > 60 && minute != 0`, true},
+		{"BoolEquality", `(hour > 18) == true`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := Compile(tt.rule)
+			require.NoError(t, err)
+			ok, err := program.Eval(attrs, EvalTimeout)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ok)
+		})
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		message string
+	}{
+		{"Empty", "  ", "The rule is empty"},
+		{"TooLong", "hour == 1" + string(make([]byte, MaxLength)), "longer than"},
+		{"NotCondition", "hour", "must be a condition"},
+		{"UnknownAttribute", "room == 5", `Unknown attribute "room" at position 1`},
+		{"TypeMismatch", `hour == "18"`, "Cannot compare a number with text at position 6"},
+		{"TextOrdering", `family > "C"`, `">" at position 8 compares numbers, not text`},
+		{"AndNeedsConditions", "hour && weekday", `"&&" at position 6 joins conditions`},
+		{"ListKind", `weekday in ["Mon"]`, "The list item at position 13 is text, but is compared with a number"},
+		{"TextRange", `family in ["A".."C"]`, "The range at position 12 needs numbers"},
+		{"Unterminated", `family == "CPP`, "Unterminated text at position 11"},
+		{"BadCharacter", "hour >= 18 ; weekday == 1", `Unexpected ";" at position 12`},
+		{"TrailingTokens", "hour >= 18 19", `Unexpected "19" at position 12`},
+		{"EndsEarly", "hour >=", "ends too early"},
+		{"MissingParen", "(hour >= 18", `Expected ")" at the end`},
+		{"MatchesNeedsPattern", "project matches family", `"matches" at position 9 needs a pattern in quotes`},
+		{"BadPattern", `project matches "[C"`, `Invalid pattern "[C" at position 17`},
+		{"NotNeedsCondition", "!hour", `"!" at position 1 needs a condition, not a number`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.rule)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+
+	t.Run("Russian", func(t *testing.T) {
+		_, err := Compile(`hour == "18"`)
+		assert.Equal(t, "Нельзя сравнить число и текст в позиции 6", render.Plain(i18n.New(i18n.Russian).Err(err)))
+	})
+}
+
+func TestEval_Timeout(t *testing.T) {
+	program, err := Compile("hour >= 18")
+	require.NoError(t, err)
+	_, err = program.Eval(Attributes{}, 0)
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func TestNewAttributes(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*3600)
+	// Sunday 22:30 UTC is Monday 01:30 in Moscow
+	start := time.Date(2026, 3, 1, 22, 30, 0, 0, time.UTC)
+	accepted := []time.Time{
+		time.Date(2026, 3, 1, 21, 30, 0, 0, time.UTC), // Monday 00:30 in Moscow
+		time.Date(2026, 3, 1, 20, 30, 0, 0, time.UTC), // Sunday 23:30 in Moscow
+	}
+	now := start.Add(-90 * time.Minute)
+
+	attrs := NewAttributes("C2_SimpleBashUtils", "C - I", start, accepted, moscow, now)
+	assert.Equal(t, 1, attrs.Weekday)
+	assert.Equal(t, 1, attrs.Hour)
+	assert.Equal(t, 30, attrs.Minute)
+	assert.Equal(t, 90, attrs.MinutesUntil)
+	assert.Equal(t, 1, attrs.ReviewsToday)
+
+	attrs = NewAttributes("C2_SimpleBashUtils", "C - I", start, accepted, time.UTC, now)
+	assert.Equal(t, 7, attrs.Weekday, "Sunday")
+	assert.Equal(t, 2, attrs.ReviewsToday)
+}
+
+func TestFormatAttributes(t *testing.T) {
+	text := FormatAttributes(Attributes{Project: "DO1_Linux", Weekday: 2, Hour: 9})
+	assert.Contains(t, text, `project = "DO1_Linux"`)
+	assert.Contains(t, text, `family = ""`)
+	assert.Contains(t, text, "weekday = 2\nhour = 9")
+}
+
+func TestIsValidAction(t *testing.T) {
+	assert.True(t, IsValidAction(ActionAsk))
+	assert.False(t, IsValidAction("maybe"))
+}
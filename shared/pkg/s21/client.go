@@ -0,0 +1,110 @@
+package s21
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	s21client "github.com/arseniisemenow/s21auto-client-go"
+	"github.com/arseniisemenow/s21auto-client-go/requests"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+)
+
+// tokenAuth implements s21client.AuthProvider using a stored access token
+type tokenAuth struct {
+	accessToken string
+}
+
+// GetAuthCredentials implements the AuthProvider interface
+func (a *tokenAuth) GetAuthCredentials(ctx context.Context) (s21client.AuthCredentials, error) {
+	return s21client.AuthCredentials{Token: a.accessToken}, nil
+}
+
+// newClient creates an s21 client authenticated with the user's stored tokens
+func newClient(ctx context.Context, reviewerLogin string) (*s21client.Client, error) {
+	tokens, err := ydb.GetUserTokens(ctx, reviewerLogin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user tokens: %w", err)
+	}
+
+	return s21client.New(&tokenAuth{accessToken: tokens.AccessToken}), nil
+}
+
+// AddEventSlot opens a free review slot in the user's calendar and returns the created event ID
+func AddEventSlot(ctx context.Context, reviewerLogin string, start, end time.Time) (string, error) {
+	client, err := newClient(ctx, reviewerLogin)
+	if err != nil {
+		return "", err
+	}
+
+	vars := requests.CalendarAddEvent_Variables{
+		Start: start.UTC(),
+		End:   end.UTC(),
+	}
+
+	resp, err := client.R().SetContext(ctx).CalendarAddEvent(vars)
+	if err != nil {
+		return "", fmt.Errorf("failed to add event slot: %w", err)
+	}
+
+	events := resp.Student.AddEventToTimetable
+	if len(events) == 0 {
+		return "", fmt.Errorf("failed to add event slot: empty response")
+	}
+
+	return events[0].ID, nil
+}
+
+// GetCampusName returns the short name of the school the user studies at, e.g. "21 Moscow"
+func GetCampusName(ctx context.Context, reviewerLogin string) (string, error) {
+	client, err := newClient(ctx, reviewerLogin)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.R().SetContext(ctx).UserRoleLoaderGetRoles(requests.UserRoleLoaderGetRoles_Variables{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get user roles: %w", err)
+	}
+
+	for _, role := range resp.User.GetCurrentUser.StudentRoles {
+		if role.School.ShortName != nil && *role.School.ShortName != "" {
+			return *role.School.ShortName, nil
+		}
+	}
+
+	return "", fmt.Errorf("no campus found for %s", reviewerLogin)
+}
+
+// GetCompletedProjects returns the names of the projects the user has passed.
+// The project graph has no progress, so this reads the finished projects of the profile
+func GetCompletedProjects(ctx context.Context, reviewerLogin string) ([]string, error) {
+	client, err := newClient(ctx, reviewerLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := client.R().SetContext(ctx).UserRoleLoaderGetRoles(requests.UserRoleLoaderGetRoles_Variables{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user roles: %w", err)
+	}
+
+	vars := requests.GetStudentFinishedProjects_Variables{
+		UserID: roles.User.GetCurrentUser.ID,
+	}
+
+	resp, err := client.R().SetContext(ctx).GetStudentFinishedProjects(vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get finished projects: %w", err)
+	}
+
+	var names []string
+	for _, project := range resp.Student.GetStudentFinishedProjects {
+		// Failed attempts are listed too, with no score
+		if project.Name != "" && project.FinalPercentage > 0 {
+			names = append(names, project.Name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,66 @@
+package settings
+
+import (
+	"strings"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+)
+
+// Override replaces a setting for the bookings of one family or project
+type Override struct {
+	EntryType string `db:"entry_type"` // FAMILY or PROJECT
+	Name      string `db:"name"`
+	Key       string `db:"setting_key"`
+	Value     string `db:"value"` // in the form returned by Parse
+}
+
+// Overridable returns the settings that can be overridden per family or project
+func Overridable() []*Setting {
+	var result []*Setting
+	for _, s := range registry {
+		if s.Overridable {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// Resolve returns the values in effect for a booking of the given project and family:
+// project overrides win over family overrides, which win over the user's own values.
+// Names are compared ignoring case, and overrides of unknown settings are skipped
+func Resolve(values Values, overrides []*Override, projectName, familyLabel string) Values {
+	result := values
+	for _, entryType := range []string{models.EntryTypeFamily, models.EntryTypeProject} {
+		name := familyLabel
+		if entryType == models.EntryTypeProject {
+			name = projectName
+		}
+		if name == "" {
+			continue
+		}
+		for _, o := range overrides {
+			if o.EntryType != entryType || !strings.EqualFold(o.Name, name) {
+				continue
+			}
+			if s := Get(o.Key); s == nil || !s.Overridable {
+				continue
+			}
+			result = result.With(o.Key, o.Value)
+		}
+	}
+	return result
+}
+
+// ApplyCore returns a copy of s with the base user_settings columns taken from values,
+// the inverse of CoreValues
+func ApplyCore(s *models.UserSettings, values Values) *models.UserSettings {
+	result := *s
+	result.ResponseDeadlineShiftMinutes = int32(values.Int(DeadlineShift))
+	result.NonWhitelistCancelDelayMinutes = int32(values.Int(CancelDelay))
+	result.NotifyWhitelistTimeout = values.Bool(NotifyWhitelistTimeout)
+	result.NotifyNonWhitelistCancel = values.Bool(NotifyNonWhitelistCancel)
+	result.SlotShiftThresholdMinutes = int32(values.Int(SlotShiftThreshold))
+	result.SlotShiftDurationMinutes = int32(values.Int(SlotShiftDuration))
+	result.CleanupDurationsMinutes = int32(values.Int(CleanupDuration))
+	return &result
+}
@@ -0,0 +1,71 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+)
+
+func TestOverridable(t *testing.T) {
+	var keys []string
+	for _, s := range Overridable() {
+		keys = append(keys, s.Key)
+	}
+	assert.Equal(t, []string{DeadlineShift, CancelDelay, SlotShiftThreshold, SlotShiftDuration, CleanupDuration, TimeoutAction}, keys)
+}
+
+func TestResolve(t *testing.T) {
+	values := Defaults()
+	overrides := []*Override{
+		{EntryType: models.EntryTypeFamily, Name: "C - I", Key: DeadlineShift, Value: "40"},
+		{EntryType: models.EntryTypeFamily, Name: "C - I", Key: CancelDelay, Value: "10"},
+		{EntryType: models.EntryTypeProject, Name: "C2_SimpleBashUtils", Key: DeadlineShift, Value: "60"},
+		{EntryType: models.EntryTypeProject, Name: "DO1_Linux", Key: Language, Value: "ru"},
+	}
+
+	tests := []struct {
+		name        string
+		project     string
+		family      string
+		deadline    string
+		cancelDelay string
+	}{
+		{"NoMatch", "go-concurrency", "Go", "20", "5"},
+		{"Family", "C3_s21_string+", "C - I", "40", "10"},
+		{"FamilyIgnoresCase", "C3_s21_string+", "c - i", "40", "10"},
+		{"ProjectWinsOverFamily", "C2_SimpleBashUtils", "C - I", "60", "10"},
+		{"ProjectWithoutFamily", "C2_SimpleBashUtils", "", "60", "5"},
+		{"NotOverridableSkipped", "DO1_Linux", "", "20", "5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved := Resolve(values, overrides, tt.project, tt.family)
+			assert.Equal(t, tt.deadline, resolved[DeadlineShift])
+			assert.Equal(t, tt.cancelDelay, resolved[CancelDelay])
+			assert.Empty(t, resolved[Language], "not overridable")
+		})
+	}
+
+	assert.Equal(t, "20", values[DeadlineShift], "values are not changed")
+
+	t.Run("Enum", func(t *testing.T) {
+		withTimeout := append(overrides, &Override{EntryType: models.EntryTypeFamily, Name: "C - I", Key: TimeoutAction, Value: TimeoutApproveWhitelisted})
+		assert.Equal(t, TimeoutApproveWhitelisted, Resolve(values, withTimeout, "C3_s21_string+", "C - I")[TimeoutAction])
+		assert.Equal(t, TimeoutCancel, Resolve(values, withTimeout, "go-concurrency", "Go")[TimeoutAction])
+	})
+}
+
+func TestApplyCore(t *testing.T) {
+	base := DefaultUserSettings("alice")
+	values := CoreValues(base).With(DeadlineShift, "45").With(CleanupDuration, "30")
+
+	applied := ApplyCore(base, values)
+	assert.Equal(t, int32(45), applied.ResponseDeadlineShiftMinutes)
+	assert.Equal(t, int32(30), applied.CleanupDurationsMinutes)
+	assert.Equal(t, "alice", applied.ReviewerLogin)
+	assert.Equal(t, CoreValues(applied), values)
+	assert.Equal(t, int32(20), base.ResponseDeadlineShiftMinutes, "the base settings are not changed")
+}
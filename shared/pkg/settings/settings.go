@@ -0,0 +1,515 @@
+package settings
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+)
+
+// Type is the type of a setting value
+type Type int
+
+const (
+	Int Type = iota
+	Bool
+	Enum
+)
+
+// Setting keys, used in commands, callback data and Values
+const (
+	DeadlineShift            = "deadline_shift"
+	CancelDelay              = "cancel_delay"
+	NotifyWhitelistTimeout   = "notify_whitelist_timeout"
+	NotifyNonWhitelistCancel = "notify_non_whitelist_cancel"
+	SlotShiftThreshold       = "slot_shift_threshold"
+	SlotShiftDuration        = "slot_shift_duration"
+	CleanupDuration          = "cleanup_duration"
+	SlotHousekeeping         = "slot_housekeeping"
+	HousekeepingBuffer       = "housekeeping_buffer"
+	AvailabilityDays         = "availability_days"
+	Lookahead                = "lookahead"
+	Lookback                 = "lookback"
+	SyncCompleted            = "sync_completed"
+	PausePolicy              = "pause_policy"
+	DailyCap                 = "daily_cap"
+	WeeklyCap                = "weekly_cap"
+	MinGap                   = "min_gap"
+	LimitAction              = "limit_action"
+	OutsideHoursAction       = "outside_hours_action"
+	BlockedPeerAction        = "blocked_peer_action"
+	TimeoutAction            = "timeout_action"
+	NonWhitelistAction       = "non_whitelist_action"
+	NonWhitelistDeadline     = "non_whitelist_deadline"
+	Language                 = "language"
+)
+
+// Slot housekeeping modes
+const (
+	HousekeepingOff   = "OFF"
+	HousekeepingTrim  = "TRIM"
+	HousekeepingSplit = "SPLIT"
+)
+
+// Pause policies decide what happens to new bookings while the user is paused
+const (
+	PausePolicyDecline         = "DECLINE"
+	PausePolicyWhitelistedOnly = "WHITELISTED_ONLY"
+	PausePolicyQueue           = "QUEUE"
+)
+
+// Limit actions decide what happens to bookings that break a daily or weekly cap or the minimum gap
+const (
+	LimitActionAsk     = "ASK"
+	LimitActionDecline = "DECLINE"
+)
+
+// Outside hours actions decide what happens to bookings outside the user's working hours
+// that would have been kept
+const (
+	OutsideHoursAsk     = "ASK"
+	OutsideHoursDecline = "DECLINE"
+)
+
+// Blocked peer actions decide what happens to bookings of blocked students
+const (
+	BlockedPeerAsk     = "ASK"
+	BlockedPeerDecline = "DECLINE"
+)
+
+// Timeout actions decide what happens to an approval request nobody answered in time
+const (
+	TimeoutCancel             = "CANCEL"
+	TimeoutApprove            = "APPROVE"
+	TimeoutApproveWhitelisted = "APPROVE_WHITELISTED"
+)
+
+// Non-whitelist actions decide whether bookings no policy kept are cancelled after the
+// cancel delay or sent for approval first
+const (
+	NonWhitelistCancel = "CANCEL"
+	NonWhitelistAsk    = "ASK"
+)
+
+// Setting describes a user setting stored in a user_settings column
+type Setting struct {
+	Key     string
+	Command string // command that changes the setting, without the slash
+	Column  string // user_settings column
+	Type    Type
+	Core    bool // the column belongs to the base schema read by ydb.GetUserSettings
+	// Overridable settings can be set per family or project with /override
+	Overridable bool
+
+	// Int: values from Min to Max that are multiples of Step, or one of Values
+	Min, Max, Step int
+	Values         []int
+	Unit           string // "minutes", "hours", "days" or "reviews"
+	Off            bool   // 0 turns the setting off
+
+	// Enum: one of Options, also accepted in lower case or as one of Aliases
+	Options []string
+	Aliases map[string]string
+	Usage   string // catalog key of the message shown for an unknown option
+
+	Default     string // in the form returned by Parse; empty for an Enum means unset
+	Label       string // catalog key of the /settings line
+	Description string // catalog key of the /help line
+}
+
+var registry = []*Setting{
+	{
+		Key: DeadlineShift, Command: "set_deadline_shift", Column: "response_deadline_shift_minutes", Type: Int, Core: true, Overridable: true,
+		Min: 20, Max: 60, Step: 1, Unit: "minutes", Default: "20",
+		Label: "settings.deadline_shift", Description: "help.deadline_shift",
+	},
+	{
+		Key: CancelDelay, Command: "set_cancel_delay", Column: "non_whitelist_cancel_delay_minutes", Type: Int, Core: true, Overridable: true,
+		Min: 5, Max: 10, Step: 1, Unit: "minutes", Default: "5",
+		Label: "settings.cancel_delay", Description: "help.cancel_delay",
+	},
+	{
+		Key: NotifyWhitelistTimeout, Command: "set_notify_whitelist_timeout", Column: "notify_whitelist_timeout", Type: Bool, Core: true,
+		Default: "true",
+		Label:   "settings.notify_whitelist_timeout", Description: "help.notify_whitelist_timeout",
+	},
+	{
+		Key: NotifyNonWhitelistCancel, Command: "set_notify_non_whitelist_cancel", Column: "notify_non_whitelist_cancel", Type: Bool, Core: true,
+		Default: "true",
+		Label:   "settings.notify_non_whitelist_cancel", Description: "help.notify_non_whitelist_cancel",
+	},
+	{
+		Key: SlotShiftThreshold, Command: "set_slot_shift_threshold", Column: "slot_shift_threshold_minutes", Type: Int, Core: true, Overridable: true,
+		Min: 20, Max: 60, Step: 5, Unit: "minutes", Default: "25",
+		Label: "settings.slot_shift_threshold", Description: "help.slot_shift_threshold",
+	},
+	{
+		Key: SlotShiftDuration, Command: "set_slot_shift_duration", Column: "slot_shift_duration_minutes", Type: Int, Core: true, Overridable: true,
+		Min: 15, Max: 60, Step: 15, Unit: "minutes", Default: "15",
+		Label: "settings.slot_shift_duration", Description: "help.slot_shift_duration",
+	},
+	{
+		Key: CleanupDuration, Command: "set_cleanup_duration", Column: "cleanup_durations_minutes", Type: Int, Core: true, Overridable: true,
+		Values: []int{15, 30, 45, 60}, Unit: "minutes", Default: "15",
+		Label: "settings.cleanup_duration", Description: "help.cleanup_duration",
+	},
+	{
+		Key: SlotHousekeeping, Command: "set_slot_housekeeping", Column: "slot_housekeeping_mode", Type: Enum,
+		Options: []string{HousekeepingOff, HousekeepingTrim, HousekeepingSplit}, Usage: "housekeeping.usage",
+		Default: HousekeepingOff,
+		Label:   "settings.housekeeping", Description: "help.slot_housekeeping",
+	},
+	{
+		Key: HousekeepingBuffer, Command: "set_housekeeping_buffer", Column: "slot_housekeeping_buffer_minutes", Type: Int,
+		Min: 0, Max: 60, Step: 5, Unit: "minutes", Default: "15",
+		Label: "settings.housekeeping_buffer", Description: "help.housekeeping_buffer",
+	},
+	{
+		Key: AvailabilityDays, Command: "set_availability_days", Column: "availability_days_ahead", Type: Int,
+		Min: 1, Max: 14, Step: 1, Unit: "days", Default: "7",
+		Label: "settings.availability_days", Description: "help.availability_days",
+	},
+	{
+		Key: Lookahead, Command: "set_lookahead", Column: "booking_lookahead_hours", Type: Int,
+		Min: 12, Max: 336, Step: 1, Unit: "hours", Default: "24",
+		Label: "settings.lookahead", Description: "help.lookahead",
+	},
+	{
+		Key: Lookback, Command: "set_lookback", Column: "booking_lookback_hours", Type: Int,
+		Min: 0, Max: 24, Step: 1, Unit: "hours", Default: "2",
+		Label: "settings.lookback", Description: "help.lookback",
+	},
+	{
+		Key: SyncCompleted, Command: "set_sync_completed", Column: "sync_completed_projects", Type: Bool,
+		Default: "false",
+		Label:   "settings.sync_completed", Description: "help.sync_completed",
+	},
+	{
+		Key: PausePolicy, Command: "set_pause_policy", Column: "pause_policy", Type: Enum,
+		Options: []string{PausePolicyDecline, PausePolicyWhitelistedOnly, PausePolicyQueue},
+		Aliases: map[string]string{"whitelisted": PausePolicyWhitelistedOnly}, Usage: "pause_policy.usage",
+		Default: PausePolicyQueue,
+		Label:   "settings.pause_policy", Description: "help.pause_policy",
+	},
+	{
+		Key: DailyCap, Command: "set_daily_cap", Column: "max_reviews_per_day", Type: Int,
+		Min: 0, Max: 20, Step: 1, Unit: "reviews", Off: true, Default: "0",
+		Label: "settings.daily_cap", Description: "help.daily_cap",
+	},
+	{
+		Key: WeeklyCap, Command: "set_weekly_cap", Column: "max_reviews_per_week", Type: Int,
+		Min: 0, Max: 100, Step: 1, Unit: "reviews", Off: true, Default: "0",
+		Label: "settings.weekly_cap", Description: "help.weekly_cap",
+	},
+	{
+		Key: MinGap, Command: "set_min_gap", Column: "min_review_gap_minutes", Type: Int,
+		Min: 0, Max: 240, Step: 5, Unit: "minutes", Off: true, Default: "0",
+		Label: "settings.min_gap", Description: "help.min_gap",
+	},
+	{
+		Key: LimitAction, Command: "set_limit_action", Column: "limit_action", Type: Enum,
+		Options: []string{LimitActionAsk, LimitActionDecline}, Usage: "limit_action.usage",
+		Default: LimitActionAsk,
+		Label:   "settings.limit_action", Description: "help.limit_action",
+	},
+	{
+		Key: OutsideHoursAction, Command: "set_outside_hours_action", Column: "outside_hours_action", Type: Enum,
+		Options: []string{OutsideHoursAsk, OutsideHoursDecline}, Usage: "outside_hours_action.usage",
+		Default: OutsideHoursAsk,
+		Label:   "settings.outside_hours_action", Description: "help.outside_hours_action",
+	},
+	{
+		Key: BlockedPeerAction, Command: "set_blocked_peer_action", Column: "blocked_peer_action", Type: Enum,
+		Options: []string{BlockedPeerAsk, BlockedPeerDecline}, Usage: "blocked_peer_action.usage",
+		Default: BlockedPeerAsk,
+		Label:   "settings.blocked_peer_action", Description: "help.blocked_peer_action",
+	},
+	{
+		Key: TimeoutAction, Command: "set_timeout_action", Column: "timeout_action", Type: Enum, Overridable: true,
+		Options: []string{TimeoutCancel, TimeoutApprove, TimeoutApproveWhitelisted},
+		Aliases: map[string]string{"whitelisted": TimeoutApproveWhitelisted}, Usage: "timeout_action.usage",
+		Default: TimeoutCancel,
+		Label:   "settings.timeout_action", Description: "help.timeout_action",
+	},
+	{
+		Key: NonWhitelistAction, Command: "set_non_whitelist_action", Column: "non_whitelist_action", Type: Enum,
+		Options: []string{NonWhitelistCancel, NonWhitelistAsk}, Usage: "non_whitelist_action.usage",
+		Default: NonWhitelistCancel,
+		Label:   "settings.non_whitelist_action", Description: "help.non_whitelist_action",
+	},
+	{
+		Key: NonWhitelistDeadline, Command: "set_non_whitelist_deadline", Column: "non_whitelist_deadline_minutes", Type: Int,
+		Min: 5, Max: 120, Step: 5, Unit: "minutes", Default: "30",
+		Label: "settings.non_whitelist_deadline", Description: "help.non_whitelist_deadline",
+	},
+	{
+		// Unset until chosen, the Telegram client language is used meanwhile
+		Key: Language, Command: "language", Column: "language", Type: Enum,
+		Options: i18n.Languages(), Usage: "language.usage",
+		Label: "settings.language", Description: "help.language",
+	},
+}
+
+// All returns every setting in display order
+func All() []*Setting {
+	return registry
+}
+
+// Get returns the setting with the given key, or nil
+func Get(key string) *Setting {
+	for _, s := range registry {
+		if s.Key == key {
+			return s
+		}
+	}
+	return nil
+}
+
+// ByColumn returns the setting stored in the given column, or nil
+func ByColumn(column string) *Setting {
+	for _, s := range registry {
+		if s.Column == column {
+			return s
+		}
+	}
+	return nil
+}
+
+// Parse validates a value given to the setting's command or picked in the menu
+// and returns it in canonical form: a decimal number, "true"/"false" or an option
+func (s *Setting) Parse(arg string) (string, error) {
+	arg = strings.TrimSpace(arg)
+
+	switch s.Type {
+	case Bool:
+		switch strings.ToLower(arg) {
+		case "true", "yes", "on", "1":
+			return "true", nil
+		case "false", "no", "off", "0":
+			return "false", nil
+		default:
+			return "", i18n.Errorf("setting.bool_usage", s.Command)
+		}
+
+	case Enum:
+		if option, ok := s.option(arg); ok {
+			return option, nil
+		}
+		return "", i18n.Errorf(s.Usage)
+
+	default:
+		value, err := strconv.Atoi(arg)
+		if len(s.Values) > 0 {
+			if err != nil {
+				return "", i18n.Errorf("setting.values_usage", s.Command, s.FormatValues())
+			}
+			for _, v := range s.Values {
+				if v == value {
+					return strconv.Itoa(value), nil
+				}
+			}
+			return "", i18n.Errorf("setting.not_allowed", s.FormatValues())
+		}
+
+		if err != nil {
+			return "", i18n.Errorf("setting.numeric_usage", s.Command, s.Min, s.Max, s.Step)
+		}
+		if value < s.Min || value > s.Max {
+			return "", i18n.Errorf("setting.out_of_range", s.Min, s.Max)
+		}
+		if value%s.Step != 0 {
+			return "", i18n.Errorf("setting.step", s.Step)
+		}
+		return strconv.Itoa(value), nil
+	}
+}
+
+func (s *Setting) option(arg string) (string, bool) {
+	for _, option := range s.Options {
+		if strings.EqualFold(arg, option) {
+			return option, true
+		}
+	}
+	option, ok := s.Aliases[strings.ToLower(arg)]
+	return option, ok
+}
+
+// HasOption reports whether value is one of the options of an Enum setting
+func (s *Setting) HasOption(value string) bool {
+	for _, option := range s.Options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Typed converts a value returned by Parse to the type it is stored as: int, bool or string
+func (s *Setting) Typed(value string) interface{} {
+	switch s.Type {
+	case Int:
+		n, _ := strconv.Atoi(value)
+		return n
+	case Bool:
+		return value == "true"
+	default:
+		return value
+	}
+}
+
+// YDBType returns the type of the setting's column
+func (s *Setting) YDBType() string {
+	switch s.Type {
+	case Int:
+		return "Int32"
+	case Bool:
+		return "Bool"
+	default:
+		return "Utf8"
+	}
+}
+
+// FormatValues lists the allowed values of an Int setting with Values, e.g. "15, 30, 45, 60"
+func (s *Setting) FormatValues() string {
+	strs := make([]string, len(s.Values))
+	for i, v := range s.Values {
+		strs[i] = strconv.Itoa(v)
+	}
+	return strings.Join(strs, ", ")
+}
+
+// Values holds setting values by key, in the form returned by Parse
+type Values map[string]string
+
+// Int returns an Int setting value
+func (v Values) Int(key string) int {
+	n, _ := strconv.Atoi(v[key])
+	return n
+}
+
+// Bool returns a Bool setting value
+func (v Values) Bool(key string) bool {
+	return v[key] == "true"
+}
+
+// With returns a copy of v with key set to value
+func (v Values) With(key, value string) Values {
+	result := make(Values, len(v)+1)
+	for k, val := range v {
+		result[k] = val
+	}
+	result[key] = value
+	return result
+}
+
+// Defaults returns the default value of every setting
+func Defaults() Values {
+	values := make(Values, len(registry))
+	for _, s := range registry {
+		values[s.Key] = s.Default
+	}
+	return values
+}
+
+// DefaultInt returns the default of an Int setting
+func DefaultInt(key string) int {
+	return Defaults().Int(key)
+}
+
+// DefaultUserSettings returns the defaults of the base user_settings columns
+func DefaultUserSettings(reviewerLogin string) *models.UserSettings {
+	values := Defaults()
+	return &models.UserSettings{
+		ReviewerLogin:                  reviewerLogin,
+		ResponseDeadlineShiftMinutes:   int32(values.Int(DeadlineShift)),
+		NonWhitelistCancelDelayMinutes: int32(values.Int(CancelDelay)),
+		NotifyWhitelistTimeout:         values.Bool(NotifyWhitelistTimeout),
+		NotifyNonWhitelistCancel:       values.Bool(NotifyNonWhitelistCancel),
+		SlotShiftThresholdMinutes:      int32(values.Int(SlotShiftThreshold)),
+		SlotShiftDurationMinutes:       int32(values.Int(SlotShiftDuration)),
+		CleanupDurationsMinutes:        int32(values.Int(CleanupDuration)),
+	}
+}
+
+// CoreValues returns the values of the base user_settings columns
+func CoreValues(s *models.UserSettings) Values {
+	return Values{
+		DeadlineShift:            strconv.Itoa(int(s.ResponseDeadlineShiftMinutes)),
+		CancelDelay:              strconv.Itoa(int(s.NonWhitelistCancelDelayMinutes)),
+		NotifyWhitelistTimeout:   strconv.FormatBool(s.NotifyWhitelistTimeout),
+		NotifyNonWhitelistCancel: strconv.FormatBool(s.NotifyNonWhitelistCancel),
+		SlotShiftThreshold:       strconv.Itoa(int(s.SlotShiftThresholdMinutes)),
+		SlotShiftDuration:        strconv.Itoa(int(s.SlotShiftDurationMinutes)),
+		CleanupDuration:          strconv.Itoa(int(s.CleanupDurationsMinutes)),
+	}
+}
+
+// rule is a constraint between settings, checked whenever one of its keys changes
+type rule struct {
+	keys  []string
+	check func(v Values) error
+}
+
+var rules = []rule{
+	{
+		// Otherwise the slot could be shifted after the question was due
+		keys: []string{SlotShiftThreshold, DeadlineShift},
+		check: func(v Values) error {
+			if v.Int(SlotShiftThreshold) <= v.Int(DeadlineShift) {
+				return i18n.Errorf("setting.rule_threshold_deadline", v.Int(SlotShiftThreshold), v.Int(DeadlineShift))
+			}
+			return nil
+		},
+	},
+	{
+		// A shift as long as the threshold would move the review into the past
+		keys: []string{SlotShiftDuration, SlotShiftThreshold},
+		check: func(v Values) error {
+			if v.Int(SlotShiftDuration) >= v.Int(SlotShiftThreshold) {
+				return i18n.Errorf("setting.rule_duration_threshold", v.Int(SlotShiftDuration), v.Int(SlotShiftThreshold))
+			}
+			return nil
+		},
+	},
+	{
+		// The daily cap could never be reached
+		keys: []string{DailyCap, WeeklyCap},
+		check: func(v Values) error {
+			daily, weekly := v.Int(DailyCap), v.Int(WeeklyCap)
+			if daily > 0 && weekly > 0 && daily > weekly {
+				return i18n.Errorf("setting.rule_daily_weekly", daily, weekly)
+			}
+			return nil
+		},
+	},
+}
+
+// Check parses arg for the setting and checks the rules between it and the other
+// current values. It returns the value to store
+func Check(s *Setting, arg string, current Values) (string, error) {
+	value, err := s.Parse(arg)
+	if err != nil {
+		return "", err
+	}
+
+	values := current.With(s.Key, value)
+	for _, r := range rules {
+		if !contains(r.keys, s.Key) {
+			continue
+		}
+		if err := r.check(values); err != nil {
+			return "", err
+		}
+	}
+	return value, nil
+}
+
+func contains(items []string, item string) bool {
+	for _, it := range items {
+		if it == item {
+			return true
+		}
+	}
+	return false
+}
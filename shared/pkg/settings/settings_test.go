@@ -0,0 +1,205 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+)
+
+func TestSetting_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		arg      string
+		expected string
+		errKey   string
+	}{
+		{"IntInRange", DeadlineShift, " 35 ", "35", ""},
+		{"IntAtMin", Lookback, "0", "0", ""},
+		{"IntBelowMin", DeadlineShift, "19", "", "setting.out_of_range"},
+		{"IntAboveMax", Lookahead, "337", "", "setting.out_of_range"},
+		{"IntNotANumber", CancelDelay, "soon", "", "setting.numeric_usage"},
+		{"IntOnStep", SlotShiftDuration, "45", "45", ""},
+		{"IntOffStep", SlotShiftDuration, "20", "", "setting.step"},
+		{"IntOffStepBuffer", HousekeepingBuffer, "12", "", "setting.step"},
+		{"ValueAllowed", CleanupDuration, "45", "45", ""},
+		{"ValueNotAllowed", CleanupDuration, "20", "", "setting.not_allowed"},
+		{"ValueNotANumber", CleanupDuration, "", "", "setting.values_usage"},
+		{"BoolOff", NotifyWhitelistTimeout, "off", "false", ""},
+		{"BoolFalse", NotifyWhitelistTimeout, "false", "false", ""},
+		{"BoolYes", NotifyNonWhitelistCancel, "Yes", "true", ""},
+		{"BoolMissing", NotifyNonWhitelistCancel, "", "", "setting.bool_usage"},
+		{"BoolInvalid", NotifyNonWhitelistCancel, "maybe", "", "setting.bool_usage"},
+		{"EnumLowerCase", SlotHousekeeping, "trim", HousekeepingTrim, ""},
+		{"EnumInvalid", SlotHousekeeping, "shrink", "", "housekeeping.usage"},
+		{"EnumAlias", PausePolicy, "Whitelisted", PausePolicyWhitelistedOnly, ""},
+		{"EnumCanonical", PausePolicy, "whitelisted_only", PausePolicyWhitelistedOnly, ""},
+		{"EnumTrimmed", PausePolicy, " queue ", PausePolicyQueue, ""},
+		{"EnumMissing", PausePolicy, "", "", "pause_policy.usage"},
+		{"Language", Language, "RU", i18n.Russian, ""},
+		{"LanguageInvalid", Language, "de", "", "language.usage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Get(tt.key)
+			require.NotNil(t, s)
+
+			value, err := s.Parse(tt.arg)
+			if tt.errKey != "" {
+				var i18nErr *i18n.Error
+				require.ErrorAs(t, err, &i18nErr)
+				assert.Equal(t, tt.errKey, i18nErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestSetting_ParseUsageNamesCommand(t *testing.T) {
+	_, err := Get(DeadlineShift).Parse("x")
+	assert.Contains(t, err.Error(), "/set_deadline_shift <value>")
+
+	_, err = Get(NotifyWhitelistTimeout).Parse("x")
+	assert.Contains(t, err.Error(), "/set_notify_whitelist_timeout <on|off>")
+}
+
+func TestSetting_Typed(t *testing.T) {
+	assert.Equal(t, 35, Get(DeadlineShift).Typed("35"))
+	assert.Equal(t, false, Get(NotifyWhitelistTimeout).Typed("false"))
+	assert.Equal(t, PausePolicyQueue, Get(PausePolicy).Typed(PausePolicyQueue))
+}
+
+func TestRegistry(t *testing.T) {
+	en := i18n.New(i18n.English)
+	keys := make(map[string]bool)
+	columns := make(map[string]bool)
+	commands := make(map[string]bool)
+
+	for _, s := range All() {
+		t.Run(s.Key, func(t *testing.T) {
+			assert.False(t, keys[s.Key], "duplicate key")
+			assert.False(t, columns[s.Column], "duplicate column")
+			assert.False(t, commands[s.Command], "duplicate command")
+			keys[s.Key], columns[s.Column], commands[s.Command] = true, true, true
+
+			assert.Same(t, s, ByColumn(s.Column))
+
+			switch s.Type {
+			case Int:
+				assert.Contains(t, []string{"minutes", "hours", "days", "reviews"}, s.Unit)
+				if s.Off {
+					assert.Zero(t, s.Min, "0 must be allowed to turn the setting off")
+				}
+				if len(s.Values) == 0 {
+					assert.Greater(t, s.Step, 0)
+					assert.Less(t, s.Min, s.Max)
+					assert.Zero(t, s.Min%s.Step, "min must be on the step")
+				}
+			case Enum:
+				assert.NotEmpty(t, s.Options)
+				assert.NotEqual(t, s.Usage, string(en.T(s.Usage)), "usage %s is missing from the catalog", s.Usage)
+				for _, option := range s.Options {
+					value, err := s.Parse(option)
+					assert.NoError(t, err, option)
+					assert.Equal(t, option, value)
+				}
+			}
+
+			// An empty Enum default means unset, everything else must be a valid value
+			if s.Type == Enum && s.Default == "" {
+				return
+			}
+			value, err := s.Parse(s.Default)
+			require.NoError(t, err)
+			assert.Equal(t, s.Default, value)
+		})
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	t.Run("MatchBaseSchema", func(t *testing.T) {
+		assert.Equal(t, models.DefaultUserSettings("testuser"), DefaultUserSettings("testuser"))
+	})
+
+	t.Run("FollowRules", func(t *testing.T) {
+		defaults := Defaults()
+		for _, r := range rules {
+			assert.NoError(t, r.check(defaults))
+		}
+	})
+
+	t.Run("CoreValuesRoundTrip", func(t *testing.T) {
+		core := CoreValues(DefaultUserSettings("testuser"))
+		for key, value := range core {
+			s := Get(key)
+			require.NotNil(t, s, key)
+			assert.True(t, s.Core, key)
+			assert.Equal(t, s.Default, value, key)
+		}
+	})
+}
+
+func TestCheck(t *testing.T) {
+	current := Defaults() // deadline 20, threshold 25, duration 15
+
+	tests := []struct {
+		name     string
+		key      string
+		arg      string
+		expected string
+		errKey   string
+	}{
+		{"DeadlineBelowThreshold", DeadlineShift, "24", "24", ""},
+		{"DeadlineAtThreshold", DeadlineShift, "25", "", "setting.rule_threshold_deadline"},
+		{"ThresholdAboveDeadline", SlotShiftThreshold, "30", "30", ""},
+		{"ThresholdAtDeadline", SlotShiftThreshold, "20", "", "setting.rule_threshold_deadline"},
+		{"DurationBelowThreshold", SlotShiftDuration, "15", "15", ""},
+		{"DurationAtThreshold", SlotShiftDuration, "30", "", "setting.rule_duration_threshold"},
+		{"UnrelatedSetting", Lookback, "6", "6", ""},
+		{"DailyCapWithoutWeeklyCap", DailyCap, "5", "5", ""},
+		{"ParseErrorFirst", DeadlineShift, "70", "", "setting.out_of_range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := Check(Get(tt.key), tt.arg, current)
+			if tt.errKey != "" {
+				var i18nErr *i18n.Error
+				require.ErrorAs(t, err, &i18nErr)
+				assert.Equal(t, tt.errKey, i18nErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+
+	t.Run("DailyCapAboveWeeklyCap", func(t *testing.T) {
+		withWeekly := current.With(WeeklyCap, "4")
+		_, err := Check(Get(DailyCap), "5", withWeekly)
+		var i18nErr *i18n.Error
+		require.ErrorAs(t, err, &i18nErr)
+		assert.Equal(t, "setting.rule_daily_weekly", i18nErr.Key)
+
+		value, err := Check(Get(WeeklyCap), "0", current.With(DailyCap, "5"))
+		require.NoError(t, err, "a weekly cap of 0 is off")
+		assert.Equal(t, "0", value)
+	})
+
+	t.Run("RuleMessageShowsBothValues", func(t *testing.T) {
+		_, err := Check(Get(SlotShiftThreshold), "20", current)
+		assert.Equal(t, "Slot shift threshold (20 min) must be greater than the response deadline shift (20 min)", err.Error())
+	})
+
+	t.Run("DoesNotChangeCurrent", func(t *testing.T) {
+		_, _ = Check(Get(DeadlineShift), "24", current)
+		assert.Equal(t, "20", current[DeadlineShift])
+	})
+}
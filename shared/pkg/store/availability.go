@@ -0,0 +1,252 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/result/named"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/types"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+)
+
+// AvailabilityTemplate is a weekly availability window, e.g. Mon-Thu 19:00-21:00
+type AvailabilityTemplate struct {
+	ReviewerLogin string `db:"reviewer_login"`
+	ID            string `db:"id"`
+	WeekdayMask   int32  `db:"weekday_mask"` // bit N set means time.Weekday(N)
+	StartMinute   int32  `db:"start_minute"` // minutes after midnight
+	EndMinute     int32  `db:"end_minute"`   // minutes after midnight
+	CreatedAt     int64  `db:"created_at"`
+}
+
+// GeneratedSlot records a calendar slot the bot opened from an availability template
+type GeneratedSlot struct {
+	ReviewerLogin string `db:"reviewer_login"`
+	OccurrenceKey string `db:"occurrence_key"`
+	EventID       string `db:"event_id"`
+	SlotStart     int64  `db:"slot_start"`
+	SlotEnd       int64  `db:"slot_end"`
+	CreatedAt     int64  `db:"created_at"`
+}
+
+// GetAvailabilityTemplates retrieves all availability templates for a user, oldest first
+func GetAvailabilityTemplates(ctx context.Context, reviewerLogin string) ([]*AvailabilityTemplate, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+
+		SELECT reviewer_login, id, weekday_mask, start_minute, end_minute, created_at
+		FROM availability_templates
+		WHERE reviewer_login = $reviewer_login
+		ORDER BY created_at, id;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query availability templates for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	var templates []*AvailabilityTemplate
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var tmpl AvailabilityTemplate
+			err = res.ScanNamed(
+				named.Required("reviewer_login", &tmpl.ReviewerLogin),
+				named.Required("id", &tmpl.ID),
+				named.Required("weekday_mask", &tmpl.WeekdayMask),
+				named.Required("start_minute", &tmpl.StartMinute),
+				named.Required("end_minute", &tmpl.EndMinute),
+				named.Required("created_at", &tmpl.CreatedAt),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan availability template: %w", err)
+			}
+			templates = append(templates, &tmpl)
+		}
+	}
+
+	return templates, nil
+}
+
+// AddAvailabilityTemplate stores a new availability template
+func AddAvailabilityTemplate(ctx context.Context, tmpl *AvailabilityTemplate) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $id AS Utf8;
+		DECLARE $weekday_mask AS Int32;
+		DECLARE $start_minute AS Int32;
+		DECLARE $end_minute AS Int32;
+		DECLARE $created_at AS Datetime;
+
+		UPSERT INTO availability_templates (reviewer_login, id, weekday_mask, start_minute, end_minute, created_at)
+		VALUES ($reviewer_login, $id, $weekday_mask, $start_minute, $end_minute, $created_at);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(tmpl.ReviewerLogin)),
+		table.ValueParam("$id", types.TextValue(tmpl.ID)),
+		table.ValueParam("$weekday_mask", types.Int32Value(tmpl.WeekdayMask)),
+		table.ValueParam("$start_minute", types.Int32Value(tmpl.StartMinute)),
+		table.ValueParam("$end_minute", types.Int32Value(tmpl.EndMinute)),
+		table.ValueParam("$created_at", datetimeValueFromUnix(tmpl.CreatedAt)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// RemoveAvailabilityTemplate removes an availability template
+func RemoveAvailabilityTemplate(ctx context.Context, reviewerLogin, id string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $id AS Utf8;
+
+		DELETE FROM availability_templates
+		WHERE reviewer_login = $reviewer_login AND id = $id;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$id", types.TextValue(id)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// GetHolidays retrieves the dates (YYYY-MM-DD) a user marked as holidays
+func GetHolidays(ctx context.Context, reviewerLogin string) ([]string, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+
+		SELECT holiday_date
+		FROM user_holidays
+		WHERE reviewer_login = $reviewer_login
+		ORDER BY holiday_date;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holidays for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	var dates []string
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var date string
+			if err := res.ScanNamed(named.Required("holiday_date", &date)); err != nil {
+				return nil, fmt.Errorf("failed to scan holiday: %w", err)
+			}
+			dates = append(dates, date)
+		}
+	}
+
+	return dates, nil
+}
+
+// AddHoliday marks a date (YYYY-MM-DD) as a holiday
+func AddHoliday(ctx context.Context, reviewerLogin, date string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $holiday_date AS Utf8;
+
+		UPSERT INTO user_holidays (reviewer_login, holiday_date)
+		VALUES ($reviewer_login, $holiday_date);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$holiday_date", types.TextValue(date)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// RemoveHoliday unmarks a holiday date
+func RemoveHoliday(ctx context.Context, reviewerLogin, date string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $holiday_date AS Utf8;
+
+		DELETE FROM user_holidays
+		WHERE reviewer_login = $reviewer_login AND holiday_date = $holiday_date;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$holiday_date", types.TextValue(date)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// GetGeneratedOccurrenceKeys returns the occurrence keys of slots generated for a user since a given time
+func GetGeneratedOccurrenceKeys(ctx context.Context, reviewerLogin string, since int64) (map[string]bool, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $since AS Datetime;
+
+		SELECT occurrence_key
+		FROM availability_slots
+		WHERE reviewer_login = $reviewer_login AND slot_start >= $since;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$since", datetimeValueFromUnix(since)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query generated slots for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	keys := make(map[string]bool)
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var key string
+			if err := res.ScanNamed(named.Required("occurrence_key", &key)); err != nil {
+				return nil, fmt.Errorf("failed to scan generated slot: %w", err)
+			}
+			keys[key] = true
+		}
+	}
+
+	return keys, nil
+}
+
+// RecordGeneratedSlot stores a slot opened from an availability template
+func RecordGeneratedSlot(ctx context.Context, slot *GeneratedSlot) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $occurrence_key AS Utf8;
+		DECLARE $event_id AS Utf8;
+		DECLARE $slot_start AS Datetime;
+		DECLARE $slot_end AS Datetime;
+		DECLARE $created_at AS Datetime;
+
+		UPSERT INTO availability_slots (reviewer_login, occurrence_key, event_id, slot_start, slot_end, created_at)
+		VALUES ($reviewer_login, $occurrence_key, $event_id, $slot_start, $slot_end, $created_at);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(slot.ReviewerLogin)),
+		table.ValueParam("$occurrence_key", types.TextValue(slot.OccurrenceKey)),
+		table.ValueParam("$event_id", types.TextValue(slot.EventID)),
+		table.ValueParam("$slot_start", datetimeValueFromUnix(slot.SlotStart)),
+		table.ValueParam("$slot_end", datetimeValueFromUnix(slot.SlotEnd)),
+		table.ValueParam("$created_at", datetimeValueFromUnix(slot.CreatedAt)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
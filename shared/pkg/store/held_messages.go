@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/result/named"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/types"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+)
+
+// HeldMessage is a Telegram message held back during quiet hours
+type HeldMessage struct {
+	ReviewerLogin string `db:"reviewer_login"`
+	ID            string `db:"id"`
+	Text          string `db:"text"` // rendered HTML, see package render
+	CreatedAt     int64  `db:"created_at"`
+}
+
+// HoldMessage stores a message to be delivered once quiet hours are over
+func HoldMessage(ctx context.Context, msg *HeldMessage) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $id AS Utf8;
+		DECLARE $text AS Utf8;
+		DECLARE $created_at AS Datetime;
+
+		UPSERT INTO held_messages (reviewer_login, id, text, created_at)
+		VALUES ($reviewer_login, $id, $text, $created_at);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(msg.ReviewerLogin)),
+		table.ValueParam("$id", types.TextValue(msg.ID)),
+		table.ValueParam("$text", types.TextValue(msg.Text)),
+		table.ValueParam("$created_at", datetimeValueFromUnix(msg.CreatedAt)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// GetHeldMessages retrieves the held messages of a user, oldest first
+func GetHeldMessages(ctx context.Context, reviewerLogin string) ([]*HeldMessage, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+
+		SELECT reviewer_login, id, text, created_at
+		FROM held_messages
+		WHERE reviewer_login = $reviewer_login
+		ORDER BY created_at, id;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query held messages for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	var messages []*HeldMessage
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var msg HeldMessage
+			err = res.ScanNamed(
+				named.Required("reviewer_login", &msg.ReviewerLogin),
+				named.Required("id", &msg.ID),
+				named.Required("text", &msg.Text),
+				named.Required("created_at", &msg.CreatedAt),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan held message: %w", err)
+			}
+			messages = append(messages, &msg)
+		}
+	}
+
+	return messages, nil
+}
+
+// DeleteHeldMessage removes a held message once it has been delivered
+func DeleteHeldMessage(ctx context.Context, reviewerLogin, id string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $id AS Utf8;
+
+		DELETE FROM held_messages
+		WHERE reviewer_login = $reviewer_login AND id = $id;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$id", types.TextValue(id)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
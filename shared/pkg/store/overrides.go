@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/result/named"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/types"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/settings"
+)
+
+// SetSettingOverride stores a setting override of a family or project, replacing the previous value
+func SetSettingOverride(ctx context.Context, reviewerLogin string, o *settings.Override) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $entry_type AS Utf8;
+		DECLARE $name AS Utf8;
+		DECLARE $setting_key AS Utf8;
+		DECLARE $value AS Utf8;
+
+		UPSERT INTO setting_overrides (reviewer_login, entry_type, name, setting_key, value)
+		VALUES ($reviewer_login, $entry_type, $name, $setting_key, $value);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$entry_type", types.TextValue(o.EntryType)),
+		table.ValueParam("$name", types.TextValue(o.Name)),
+		table.ValueParam("$setting_key", types.TextValue(o.Key)),
+		table.ValueParam("$value", types.TextValue(o.Value)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// RemoveSettingOverride deletes a setting override of a family or project
+func RemoveSettingOverride(ctx context.Context, reviewerLogin, entryType, name, key string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $entry_type AS Utf8;
+		DECLARE $name AS Utf8;
+		DECLARE $setting_key AS Utf8;
+
+		DELETE FROM setting_overrides
+		WHERE reviewer_login = $reviewer_login AND entry_type = $entry_type
+		  AND name = $name AND setting_key = $setting_key;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$entry_type", types.TextValue(entryType)),
+		table.ValueParam("$name", types.TextValue(name)),
+		table.ValueParam("$setting_key", types.TextValue(key)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// GetSettingOverrides retrieves the user's setting overrides, ordered by family or project
+func GetSettingOverrides(ctx context.Context, reviewerLogin string) ([]*settings.Override, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+
+		SELECT entry_type, name, setting_key, value
+		FROM setting_overrides
+		WHERE reviewer_login = $reviewer_login
+		ORDER BY entry_type, name, setting_key;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query setting overrides for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	var overrides []*settings.Override
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var o settings.Override
+			err = res.ScanNamed(
+				named.Required("entry_type", &o.EntryType),
+				named.Required("name", &o.Name),
+				named.Required("setting_key", &o.Key),
+				named.Required("value", &o.Value),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan setting override: %w", err)
+			}
+			overrides = append(overrides, &o)
+		}
+	}
+
+	return overrides, nil
+}
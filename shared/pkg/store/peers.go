@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/result/named"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/types"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+)
+
+// Peer kinds
+const (
+	PeerTrusted = "TRUSTED"
+	PeerBlocked = "BLOCKED"
+)
+
+// Peer is a student whose reviews are approved without asking, or always held back
+type Peer struct {
+	ReviewerLogin string `db:"reviewer_login"`
+	Login         string `db:"peer_login"` // lower case
+	Kind          string `db:"kind"`       // PeerTrusted or PeerBlocked
+	CreatedAt     int64  `db:"created_at"`
+}
+
+// GetPeers retrieves the trusted and blocked peers of a user, ordered by login
+func GetPeers(ctx context.Context, reviewerLogin string) ([]*Peer, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+
+		SELECT reviewer_login, peer_login, kind, created_at
+		FROM peers
+		WHERE reviewer_login = $reviewer_login
+		ORDER BY peer_login;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query peers for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	var peers []*Peer
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var peer Peer
+			err = res.ScanNamed(
+				named.Required("reviewer_login", &peer.ReviewerLogin),
+				named.Required("peer_login", &peer.Login),
+				named.Required("kind", &peer.Kind),
+				named.Required("created_at", &peer.CreatedAt),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan peer: %w", err)
+			}
+			peers = append(peers, &peer)
+		}
+	}
+
+	return peers, nil
+}
+
+// UpsertPeer stores a peer, replacing the kind of a peer already listed
+func UpsertPeer(ctx context.Context, peer *Peer) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $peer_login AS Utf8;
+		DECLARE $kind AS Utf8;
+		DECLARE $created_at AS Datetime;
+
+		UPSERT INTO peers (reviewer_login, peer_login, kind, created_at)
+		VALUES ($reviewer_login, $peer_login, $kind, $created_at);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(peer.ReviewerLogin)),
+		table.ValueParam("$peer_login", types.TextValue(peer.Login)),
+		table.ValueParam("$kind", types.TextValue(peer.Kind)),
+		table.ValueParam("$created_at", datetimeValueFromUnix(peer.CreatedAt)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// RemovePeer removes a peer from the trusted or blocked list
+func RemovePeer(ctx context.Context, reviewerLogin, login string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $peer_login AS Utf8;
+
+		DELETE FROM peers
+		WHERE reviewer_login = $reviewer_login AND peer_login = $peer_login;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$peer_login", types.TextValue(login)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
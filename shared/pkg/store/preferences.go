@@ -0,0 +1,343 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/result/named"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/types"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/settings"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/timezone"
+)
+
+// Slot housekeeping modes
+const (
+	HousekeepingOff   = settings.HousekeepingOff
+	HousekeepingTrim  = settings.HousekeepingTrim
+	HousekeepingSplit = settings.HousekeepingSplit
+)
+
+// Pause policies decide what happens to new bookings while the user is paused
+const (
+	PausePolicyDecline         = settings.PausePolicyDecline
+	PausePolicyWhitelistedOnly = settings.PausePolicyWhitelistedOnly
+	PausePolicyQueue           = settings.PausePolicyQueue
+)
+
+// UserPreferences holds the settings stored in user_settings next to
+// the columns covered by models.UserSettings
+type UserPreferences struct {
+	ReviewerLogin                 string `db:"reviewer_login"`
+	SlotHousekeepingMode          string `db:"slot_housekeeping_mode"`
+	SlotHousekeepingBufferMinutes int32  `db:"slot_housekeeping_buffer_minutes"`
+	AvailabilityDaysAhead         int32  `db:"availability_days_ahead"`
+	BookingLookaheadHours         int32  `db:"booking_lookahead_hours"`
+	BookingLookbackHours          int32  `db:"booking_lookback_hours"`
+	SyncCompletedProjects         bool   `db:"sync_completed_projects"`
+	CompletedSyncedAt             *int64 `db:"completed_synced_at"` // nil until the first sync, and again after opting out
+	Paused                        bool   `db:"paused"`
+	PausedUntil                   *int64 `db:"paused_until"` // nil means until /resume
+	PausePolicy                   string `db:"pause_policy"`
+	MaxReviewsPerDay              int32  `db:"max_reviews_per_day"` // 0 means no cap
+	MaxReviewsPerWeek             int32  `db:"max_reviews_per_week"`
+	MinReviewGapMinutes           int32  `db:"min_review_gap_minutes"`
+	LimitAction                   string `db:"limit_action"`
+	WorkingHours                  string `db:"working_hours"` // e.g. "Mon-Fri 10:00-22:00", empty for no working hours
+	OutsideHoursAction            string `db:"outside_hours_action"`
+	BlockedPeerAction             string `db:"blocked_peer_action"`
+	TimeoutAction                 string `db:"timeout_action"`
+	NonWhitelistAction            string `db:"non_whitelist_action"`
+	NonWhitelistDeadlineMinutes   int32  `db:"non_whitelist_deadline_minutes"`
+	QuietHoursStartMinute         int32  `db:"quiet_hours_start_minute"` // equal start and end disable quiet hours
+	QuietHoursEndMinute           int32  `db:"quiet_hours_end_minute"`
+	Timezone                      string `db:"timezone"`          // IANA zone, empty until detected from the campus
+	Language                      string `db:"language"`          // empty until chosen, the Telegram client language is used meanwhile
+	DecisionPolicies              string `db:"decision_policies"` // comma-separated policy chain, empty for the default chain
+}
+
+// DefaultUserPreferences returns default user preferences, taken from the settings registry
+func DefaultUserPreferences(reviewerLogin string) *UserPreferences {
+	defaults := settings.Defaults()
+	return &UserPreferences{
+		ReviewerLogin:                 reviewerLogin,
+		SlotHousekeepingMode:          defaults[settings.SlotHousekeeping],
+		SlotHousekeepingBufferMinutes: int32(defaults.Int(settings.HousekeepingBuffer)),
+		AvailabilityDaysAhead:         int32(defaults.Int(settings.AvailabilityDays)),
+		BookingLookaheadHours:         int32(defaults.Int(settings.Lookahead)),
+		BookingLookbackHours:          int32(defaults.Int(settings.Lookback)),
+		SyncCompletedProjects:         defaults.Bool(settings.SyncCompleted),
+		PausePolicy:                   defaults[settings.PausePolicy],
+		MaxReviewsPerDay:              int32(defaults.Int(settings.DailyCap)),
+		MaxReviewsPerWeek:             int32(defaults.Int(settings.WeeklyCap)),
+		MinReviewGapMinutes:           int32(defaults.Int(settings.MinGap)),
+		LimitAction:                   defaults[settings.LimitAction],
+		OutsideHoursAction:            defaults[settings.OutsideHoursAction],
+		BlockedPeerAction:             defaults[settings.BlockedPeerAction],
+		TimeoutAction:                 defaults[settings.TimeoutAction],
+		NonWhitelistAction:            defaults[settings.NonWhitelistAction],
+		NonWhitelistDeadlineMinutes:   int32(defaults.Int(settings.NonWhitelistDeadline)),
+		Language:                      defaults[settings.Language],
+	}
+}
+
+// IsValidHousekeepingMode checks if a slot housekeeping mode is valid
+func IsValidHousekeepingMode(mode string) bool {
+	return settings.Get(settings.SlotHousekeeping).HasOption(mode)
+}
+
+// IsValidPausePolicy checks if a pause policy is valid
+func IsValidPausePolicy(policy string) bool {
+	return settings.Get(settings.PausePolicy).HasOption(policy)
+}
+
+// IsPaused reports whether the user is paused at the given time
+func (p *UserPreferences) IsPaused(now time.Time) bool {
+	if !p.Paused {
+		return false
+	}
+	return p.PausedUntil == nil || now.Unix() < *p.PausedUntil
+}
+
+// HasQuietHours reports whether the user has set quiet hours
+func (p *UserPreferences) HasQuietHours() bool {
+	return p.QuietHoursStartMinute != p.QuietHoursEndMinute
+}
+
+// Location returns the user's timezone, falling back to timezone.DefaultZone while it is unknown
+func (p *UserPreferences) Location() *time.Location {
+	return timezone.Load(p.Timezone)
+}
+
+// Printer returns a printer for the user's language. clientLang is the Telegram
+// language_code, used while the user has not chosen a language
+func (p *UserPreferences) Printer(clientLang string) *i18n.Printer {
+	return i18n.New(i18n.Resolve(p.Language, clientLang))
+}
+
+// GetUserPreferences retrieves preferences for a user.
+// Columns that were never set fall back to DefaultUserPreferences
+func GetUserPreferences(ctx context.Context, reviewerLogin string) (*UserPreferences, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+
+		SELECT reviewer_login, slot_housekeeping_mode, slot_housekeeping_buffer_minutes,
+		       availability_days_ahead, booking_lookahead_hours, booking_lookback_hours,
+		       sync_completed_projects, completed_synced_at,
+		       paused, paused_until, pause_policy, quiet_hours_start_minute, quiet_hours_end_minute,
+		       timezone, language, decision_policies,
+		       max_reviews_per_day, max_reviews_per_week, min_review_gap_minutes, limit_action,
+		       working_hours, outside_hours_action, blocked_peer_action, timeout_action,
+		       non_whitelist_action, non_whitelist_deadline_minutes
+		FROM user_settings
+		WHERE reviewer_login = $reviewer_login;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user preferences for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	prefs := DefaultUserPreferences(reviewerLogin)
+	if res.NextResultSet(ctx) && res.NextRow() {
+		var mode, policy, zone, lang, chain, limitAction, workingHours, outsideAction, peerAction, timeoutAction, nonWhitelistAction *string
+		var buffer, daysAhead, lookahead, lookback, quietStart, quietEnd, dailyCap, weeklyCap, minGap, nonWhitelistDeadline *int32
+		var paused, syncCompleted *bool
+		var pausedUntil, syncedAt *int64
+		err = res.ScanNamed(
+			named.Optional("slot_housekeeping_mode", &mode),
+			named.Optional("slot_housekeeping_buffer_minutes", &buffer),
+			named.Optional("availability_days_ahead", &daysAhead),
+			named.Optional("booking_lookahead_hours", &lookahead),
+			named.Optional("booking_lookback_hours", &lookback),
+			named.Optional("sync_completed_projects", &syncCompleted),
+			named.Optional("completed_synced_at", &syncedAt),
+			named.Optional("paused", &paused),
+			named.Optional("paused_until", &pausedUntil),
+			named.Optional("pause_policy", &policy),
+			named.Optional("quiet_hours_start_minute", &quietStart),
+			named.Optional("quiet_hours_end_minute", &quietEnd),
+			named.Optional("timezone", &zone),
+			named.Optional("language", &lang),
+			named.Optional("decision_policies", &chain),
+			named.Optional("max_reviews_per_day", &dailyCap),
+			named.Optional("max_reviews_per_week", &weeklyCap),
+			named.Optional("min_review_gap_minutes", &minGap),
+			named.Optional("limit_action", &limitAction),
+			named.Optional("working_hours", &workingHours),
+			named.Optional("outside_hours_action", &outsideAction),
+			named.Optional("blocked_peer_action", &peerAction),
+			named.Optional("timeout_action", &timeoutAction),
+			named.Optional("non_whitelist_action", &nonWhitelistAction),
+			named.Optional("non_whitelist_deadline_minutes", &nonWhitelistDeadline),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user preferences: %w", err)
+		}
+		if mode != nil && IsValidHousekeepingMode(*mode) {
+			prefs.SlotHousekeepingMode = *mode
+		}
+		if buffer != nil {
+			prefs.SlotHousekeepingBufferMinutes = *buffer
+		}
+		if daysAhead != nil {
+			prefs.AvailabilityDaysAhead = *daysAhead
+		}
+		if lookahead != nil {
+			prefs.BookingLookaheadHours = *lookahead
+		}
+		if lookback != nil {
+			prefs.BookingLookbackHours = *lookback
+		}
+		if syncCompleted != nil {
+			prefs.SyncCompletedProjects = *syncCompleted
+		}
+		prefs.CompletedSyncedAt = syncedAt
+		if paused != nil {
+			prefs.Paused = *paused
+		}
+		prefs.PausedUntil = pausedUntil
+		if policy != nil && IsValidPausePolicy(*policy) {
+			prefs.PausePolicy = *policy
+		}
+		if quietStart != nil && quietEnd != nil {
+			prefs.QuietHoursStartMinute = *quietStart
+			prefs.QuietHoursEndMinute = *quietEnd
+		}
+		if zone != nil {
+			prefs.Timezone = *zone
+		}
+		if lang != nil && i18n.IsSupported(*lang) {
+			prefs.Language = *lang
+		}
+		if chain != nil {
+			prefs.DecisionPolicies = *chain
+		}
+		if dailyCap != nil {
+			prefs.MaxReviewsPerDay = *dailyCap
+		}
+		if weeklyCap != nil {
+			prefs.MaxReviewsPerWeek = *weeklyCap
+		}
+		if minGap != nil {
+			prefs.MinReviewGapMinutes = *minGap
+		}
+		if limitAction != nil && settings.Get(settings.LimitAction).HasOption(*limitAction) {
+			prefs.LimitAction = *limitAction
+		}
+		if workingHours != nil {
+			prefs.WorkingHours = *workingHours
+		}
+		if outsideAction != nil && settings.Get(settings.OutsideHoursAction).HasOption(*outsideAction) {
+			prefs.OutsideHoursAction = *outsideAction
+		}
+		if peerAction != nil && settings.Get(settings.BlockedPeerAction).HasOption(*peerAction) {
+			prefs.BlockedPeerAction = *peerAction
+		}
+		if timeoutAction != nil && settings.Get(settings.TimeoutAction).HasOption(*timeoutAction) {
+			prefs.TimeoutAction = *timeoutAction
+		}
+		if nonWhitelistAction != nil && settings.Get(settings.NonWhitelistAction).HasOption(*nonWhitelistAction) {
+			prefs.NonWhitelistAction = *nonWhitelistAction
+		}
+		if nonWhitelistDeadline != nil {
+			prefs.NonWhitelistDeadlineMinutes = *nonWhitelistDeadline
+		}
+	}
+
+	return prefs, nil
+}
+
+// UpdateTextSetting updates a single Utf8 user setting field
+func UpdateTextSetting(ctx context.Context, reviewerLogin, field, value string) error {
+	if !isSettingsColumn(field, "Utf8") {
+		return fmt.Errorf("unknown Utf8 user_settings column %q", field)
+	}
+
+	sql := fmt.Sprintf(ydb.TablePathPrefix("")+`
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $value AS Utf8;
+
+		UPDATE user_settings
+		SET %s = $value
+		WHERE reviewer_login = $reviewer_login;
+	`, field)
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$value", types.TextValue(value)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// SetPause pauses or resumes the approval flow. until is a Unix time, nil pauses until resumed
+func SetPause(ctx context.Context, reviewerLogin string, paused bool, until *int64) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $paused AS Bool;
+		DECLARE $paused_until AS Optional<Datetime>;
+
+		UPDATE user_settings
+		SET paused = $paused, paused_until = $paused_until
+		WHERE reviewer_login = $reviewer_login;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$paused", types.BoolValue(paused)),
+		table.ValueParam("$paused_until", optionalDatetimeValue(until)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// SetCompletedSyncedAt records when completed projects were last synced to the whitelist.
+// nil makes the next run sync again, or marks the managed entries as removed after opting out
+func SetCompletedSyncedAt(ctx context.Context, reviewerLogin string, syncedAt *int64) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $synced_at AS Optional<Datetime>;
+
+		UPDATE user_settings
+		SET completed_synced_at = $synced_at
+		WHERE reviewer_login = $reviewer_login;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$synced_at", optionalDatetimeValue(syncedAt)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// SetQuietHours sets daily quiet hours in minutes after midnight. Equal values disable them
+func SetQuietHours(ctx context.Context, reviewerLogin string, startMinute, endMinute int32) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $start_minute AS Int32;
+		DECLARE $end_minute AS Int32;
+
+		UPDATE user_settings
+		SET quiet_hours_start_minute = $start_minute, quiet_hours_end_minute = $end_minute
+		WHERE reviewer_login = $reviewer_login;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$start_minute", types.Int32Value(startMinute)),
+		table.ValueParam("$end_minute", types.Int32Value(endMinute)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
@@ -0,0 +1,169 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/settings"
+)
+
+func TestDefaultUserPreferences(t *testing.T) {
+	prefs := DefaultUserPreferences("testuser")
+
+	assert.Equal(t, "testuser", prefs.ReviewerLogin)
+	assert.Equal(t, HousekeepingOff, prefs.SlotHousekeepingMode)
+	assert.Equal(t, int32(15), prefs.SlotHousekeepingBufferMinutes)
+	assert.Equal(t, int32(7), prefs.AvailabilityDaysAhead)
+	assert.Equal(t, int32(24), prefs.BookingLookaheadHours)
+	assert.Equal(t, int32(2), prefs.BookingLookbackHours)
+	assert.Equal(t, PausePolicyQueue, prefs.PausePolicy)
+	assert.Equal(t, int32(0), prefs.MaxReviewsPerDay)
+	assert.Equal(t, int32(0), prefs.MinReviewGapMinutes)
+	assert.Equal(t, settings.LimitActionAsk, prefs.LimitAction)
+	assert.Empty(t, prefs.WorkingHours)
+	assert.Equal(t, settings.OutsideHoursAsk, prefs.OutsideHoursAction)
+	assert.Equal(t, settings.BlockedPeerAsk, prefs.BlockedPeerAction)
+	assert.Equal(t, settings.TimeoutCancel, prefs.TimeoutAction)
+	assert.Equal(t, settings.NonWhitelistCancel, prefs.NonWhitelistAction)
+	assert.Equal(t, int32(30), prefs.NonWhitelistDeadlineMinutes)
+	assert.Empty(t, prefs.Timezone)
+	assert.Empty(t, prefs.Language)
+	assert.False(t, prefs.Paused)
+	assert.False(t, prefs.HasQuietHours())
+}
+
+func TestIsValidHousekeepingMode(t *testing.T) {
+	tests := []struct {
+		mode     string
+		expected bool
+	}{
+		{HousekeepingOff, true},
+		{HousekeepingTrim, true},
+		{HousekeepingSplit, true},
+		{"trim", false},
+		{"", false},
+		{"SHRINK", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsValidHousekeepingMode(tt.mode))
+		})
+	}
+}
+
+func TestIsValidPausePolicy(t *testing.T) {
+	assert.True(t, IsValidPausePolicy(PausePolicyDecline))
+	assert.True(t, IsValidPausePolicy(PausePolicyWhitelistedOnly))
+	assert.True(t, IsValidPausePolicy(PausePolicyQueue))
+	assert.False(t, IsValidPausePolicy("queue"))
+	assert.False(t, IsValidPausePolicy(""))
+}
+
+func TestUserPreferences_IsPaused(t *testing.T) {
+	now := time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC)
+	future := now.Add(24 * time.Hour).Unix()
+	past := now.Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name     string
+		paused   bool
+		until    *int64
+		expected bool
+	}{
+		{"NotPaused", false, nil, false},
+		{"Indefinitely", true, nil, true},
+		{"UntilFuture", true, &future, true},
+		{"UntilPast", true, &past, false},
+		{"NotPausedWithStaleUntil", false, &future, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefs := DefaultUserPreferences("testuser")
+			prefs.Paused = tt.paused
+			prefs.PausedUntil = tt.until
+			assert.Equal(t, tt.expected, prefs.IsPaused(now))
+		})
+	}
+}
+
+func TestUserPreferences_Printer(t *testing.T) {
+	prefs := DefaultUserPreferences("testuser")
+	assert.Equal(t, i18n.Default, prefs.Printer("").Lang())
+	assert.Equal(t, i18n.Russian, prefs.Printer("ru").Lang())
+
+	prefs.Language = i18n.English
+	assert.Equal(t, i18n.English, prefs.Printer("ru").Lang())
+}
+
+func TestMissingColumns(t *testing.T) {
+	columns := []settingsColumn{
+		{name: "a", ydbTyp: "Utf8"},
+		{name: "b", ydbTyp: "Int32"},
+		{name: "c", ydbTyp: "Bool"},
+	}
+
+	t.Run("all present", func(t *testing.T) {
+		existing := map[string]bool{"a": true, "b": true, "c": true}
+		assert.Empty(t, missingColumns(existing, columns))
+	})
+
+	t.Run("some missing", func(t *testing.T) {
+		existing := map[string]bool{"b": true}
+		missing := missingColumns(existing, columns)
+		assert.Equal(t, []settingsColumn{columns[0], columns[2]}, missing)
+	})
+
+	t.Run("settings columns are unique", func(t *testing.T) {
+		seen := make(map[string]bool)
+		for _, col := range settingsColumns {
+			assert.False(t, seen[col.name], "duplicate column %s", col.name)
+			seen[col.name] = true
+		}
+	})
+}
+
+func TestIsSettingsColumn(t *testing.T) {
+	tests := []struct {
+		column   string
+		ydbTyp   string
+		expected bool
+	}{
+		{"pause_policy", "Utf8", true},
+		{"language", "Utf8", true},
+		{"timezone", "Utf8", true},
+		{"booking_lookback_hours", "Int32", true},
+		{"booking_lookback_hours", "Utf8", false},
+		{"response_deadline_shift_minutes", "Int32", true},
+		{"reviewer_login", "Utf8", false},
+		{"nope", "Utf8", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.column+"/"+tt.ydbTyp, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isSettingsColumn(tt.column, tt.ydbTyp))
+		})
+	}
+
+	t.Run("registry columns are created", func(t *testing.T) {
+		for _, s := range settings.All() {
+			if !s.Core {
+				assert.Contains(t, settingsColumns, settingsColumn{name: s.Column, ydbTyp: s.YDBType()})
+			}
+		}
+	})
+}
+
+func TestSettingValues(t *testing.T) {
+	prefs := DefaultUserPreferences("testuser")
+	prefs.BookingLookbackHours = 6
+	prefs.Language = i18n.Russian
+
+	values := SettingValues(settings.DefaultUserSettings("testuser"), prefs)
+
+	assert.Equal(t, settings.Defaults().With(settings.Lookback, "6").With(settings.Language, i18n.Russian), values)
+}
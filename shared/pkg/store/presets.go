@@ -0,0 +1,282 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/result/named"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/types"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+)
+
+// WhitelistPreset is a named set of whitelist entries users can subscribe to
+type WhitelistPreset struct {
+	Name       string `db:"name"`
+	OwnerLogin string `db:"owner_login"`
+	CreatedAt  int64  `db:"created_at"`
+}
+
+// PresetEntry is a whitelist entry of a preset
+type PresetEntry struct {
+	PresetName string `db:"preset_name"`
+	EntryType  string `db:"entry_type"`
+	Name       string `db:"name"`
+}
+
+// CreatePreset stores a new preset
+func CreatePreset(ctx context.Context, preset *WhitelistPreset) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $name AS Utf8;
+		DECLARE $owner_login AS Utf8;
+		DECLARE $created_at AS Datetime;
+
+		INSERT INTO whitelist_presets (name, owner_login, created_at)
+		VALUES ($name, $owner_login, $created_at);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$name", types.TextValue(preset.Name)),
+		table.ValueParam("$owner_login", types.TextValue(preset.OwnerLogin)),
+		table.ValueParam("$created_at", datetimeValueFromUnix(preset.CreatedAt)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// GetPresets retrieves all presets, ordered by name
+func GetPresets(ctx context.Context) ([]*WhitelistPreset, error) {
+	sql := ydb.TablePathPrefix("") + `
+		SELECT name, owner_login, created_at
+		FROM whitelist_presets
+		ORDER BY name;
+	`
+
+	res, err := ydb.Query(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query presets: %w", err)
+	}
+	defer res.Close()
+
+	var presets []*WhitelistPreset
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var preset WhitelistPreset
+			err = res.ScanNamed(
+				named.Required("name", &preset.Name),
+				named.Required("owner_login", &preset.OwnerLogin),
+				named.Required("created_at", &preset.CreatedAt),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan preset: %w", err)
+			}
+			presets = append(presets, &preset)
+		}
+	}
+
+	return presets, nil
+}
+
+// GetPreset retrieves a preset by name, or nil if there is none
+func GetPreset(ctx context.Context, name string) (*WhitelistPreset, error) {
+	presets, err := GetPresets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, preset := range presets {
+		if preset.Name == name {
+			return preset, nil
+		}
+	}
+	return nil, nil
+}
+
+// DeletePreset removes a preset with its entries and subscriptions
+func DeletePreset(ctx context.Context, name string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $name AS Utf8;
+
+		DELETE FROM whitelist_preset_subscriptions WHERE preset_name = $name;
+		DELETE FROM whitelist_preset_entries WHERE preset_name = $name;
+		DELETE FROM whitelist_presets WHERE name = $name;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$name", types.TextValue(name)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// AddPresetEntry adds an entry to a preset
+func AddPresetEntry(ctx context.Context, entry *PresetEntry) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $preset_name AS Utf8;
+		DECLARE $entry_type AS Utf8;
+		DECLARE $name AS Utf8;
+
+		UPSERT INTO whitelist_preset_entries (preset_name, entry_type, name)
+		VALUES ($preset_name, $entry_type, $name);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$preset_name", types.TextValue(entry.PresetName)),
+		table.ValueParam("$entry_type", types.TextValue(entry.EntryType)),
+		table.ValueParam("$name", types.TextValue(entry.Name)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// RemovePresetEntry removes the entries called name from a preset
+func RemovePresetEntry(ctx context.Context, presetName, name string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $preset_name AS Utf8;
+		DECLARE $name AS Utf8;
+
+		DELETE FROM whitelist_preset_entries
+		WHERE preset_name = $preset_name AND name = $name;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$preset_name", types.TextValue(presetName)),
+		table.ValueParam("$name", types.TextValue(name)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// GetPresetEntries retrieves the entries of a preset
+func GetPresetEntries(ctx context.Context, presetName string) ([]*PresetEntry, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $preset_name AS Utf8;
+
+		SELECT preset_name, entry_type, name
+		FROM whitelist_preset_entries
+		WHERE preset_name = $preset_name
+		ORDER BY entry_type, name;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$preset_name", types.TextValue(presetName)),
+	}
+
+	return queryPresetEntries(ctx, sql, params...)
+}
+
+// GetSubscribedPresetEntries retrieves the entries of all presets a user subscribes to
+func GetSubscribedPresetEntries(ctx context.Context, reviewerLogin string) ([]*PresetEntry, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+
+		SELECT e.preset_name AS preset_name, e.entry_type AS entry_type, e.name AS name
+		FROM whitelist_preset_subscriptions AS s
+		JOIN whitelist_preset_entries AS e ON s.preset_name = e.preset_name
+		WHERE s.reviewer_login = $reviewer_login
+		ORDER BY preset_name, entry_type, name;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+	}
+
+	return queryPresetEntries(ctx, sql, params...)
+}
+
+func queryPresetEntries(ctx context.Context, sql string, params ...table.ParameterOption) ([]*PresetEntry, error) {
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query preset entries: %w", err)
+	}
+	defer res.Close()
+
+	var entries []*PresetEntry
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var entry PresetEntry
+			err = res.ScanNamed(
+				named.Required("preset_name", &entry.PresetName),
+				named.Required("entry_type", &entry.EntryType),
+				named.Required("name", &entry.Name),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan preset entry: %w", err)
+			}
+			entries = append(entries, &entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// SubscribePreset subscribes a user to a preset
+func SubscribePreset(ctx context.Context, reviewerLogin, presetName string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $preset_name AS Utf8;
+
+		UPSERT INTO whitelist_preset_subscriptions (reviewer_login, preset_name)
+		VALUES ($reviewer_login, $preset_name);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$preset_name", types.TextValue(presetName)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// UnsubscribePreset unsubscribes a user from a preset
+func UnsubscribePreset(ctx context.Context, reviewerLogin, presetName string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $preset_name AS Utf8;
+
+		DELETE FROM whitelist_preset_subscriptions
+		WHERE reviewer_login = $reviewer_login AND preset_name = $preset_name;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$preset_name", types.TextValue(presetName)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// GetSubscriptions retrieves the names of the presets a user subscribes to
+func GetSubscriptions(ctx context.Context, reviewerLogin string) ([]string, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+
+		SELECT preset_name
+		FROM whitelist_preset_subscriptions
+		WHERE reviewer_login = $reviewer_login
+		ORDER BY preset_name;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query preset subscriptions for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	var names []string
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var name string
+			if err := res.ScanNamed(named.Required("preset_name", &name)); err != nil {
+				return nil, fmt.Errorf("failed to scan preset subscription: %w", err)
+			}
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
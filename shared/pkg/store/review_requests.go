@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/result/named"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/types"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+)
+
+// SetDecisionPolicy records the policy that decided on a review request
+func SetDecisionPolicy(ctx context.Context, reviewRequestID, policy string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $id AS Utf8;
+		DECLARE $decision_policy AS Utf8;
+
+		UPDATE review_requests
+		SET decision_policy = $decision_policy
+		WHERE id = $id;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$id", types.TextValue(reviewRequestID)),
+		table.ValueParam("$decision_policy", types.TextValue(policy)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// SetReviewees records the logins of the students a review request is for
+func SetReviewees(ctx context.Context, reviewRequestID string, logins []string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $id AS Utf8;
+		DECLARE $reviewee_login AS Utf8;
+
+		UPDATE review_requests
+		SET reviewee_login = $reviewee_login
+		WHERE id = $id;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$id", types.TextValue(reviewRequestID)),
+		table.ValueParam("$reviewee_login", types.TextValue(strings.Join(logins, ","))),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// GetReviewees returns the logins of the students a review request is for, none while unknown
+func GetReviewees(ctx context.Context, reviewRequestID string) ([]string, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $id AS Utf8;
+
+		SELECT reviewee_login
+		FROM review_requests
+		WHERE id = $id;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$id", types.TextValue(reviewRequestID)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reviewees of review request %s: %w", reviewRequestID, err)
+	}
+	defer res.Close()
+
+	var logins *string
+	if res.NextResultSet(ctx) && res.NextRow() {
+		if err := res.ScanNamed(named.Optional("reviewee_login", &logins)); err != nil {
+			return nil, fmt.Errorf("failed to scan reviewees: %w", err)
+		}
+	}
+	if logins == nil || *logins == "" {
+		return nil, nil
+	}
+	return strings.Split(*logins, ","), nil
+}
+
+// HoldNonWhitelistAsk records that the question about a booking outside the whitelist was held
+// back while paused or during quiet hours. Only the first hold is kept
+func HoldNonWhitelistAsk(ctx context.Context, reviewRequestID string, heldAt int64) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $id AS Utf8;
+		DECLARE $held_at AS Datetime;
+
+		UPDATE review_requests
+		SET non_whitelist_ask_held_at = $held_at
+		WHERE id = $id AND non_whitelist_ask_held_at IS NULL;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$id", types.TextValue(reviewRequestID)),
+		table.ValueParam("$held_at", datetimeValueFromUnix(heldAt)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// NonWhitelistAskHeld reports whether the question about a booking outside the whitelist was held back
+func NonWhitelistAskHeld(ctx context.Context, reviewRequestID string) (bool, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $id AS Utf8;
+
+		SELECT non_whitelist_ask_held_at
+		FROM review_requests
+		WHERE id = $id;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$id", types.TextValue(reviewRequestID)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return false, fmt.Errorf("failed to query held question of review request %s: %w", reviewRequestID, err)
+	}
+	defer res.Close()
+
+	var heldAt *int64
+	if res.NextResultSet(ctx) && res.NextRow() {
+		if err := res.ScanNamed(named.Optional("non_whitelist_ask_held_at", &heldAt)); err != nil {
+			return false, fmt.Errorf("failed to scan held question: %w", err)
+		}
+	}
+	return heldAt != nil, nil
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/result/named"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/types"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+)
+
+// DecisionRule is a rule expression and the action taken on bookings it holds for
+type DecisionRule struct {
+	ReviewerLogin string `db:"reviewer_login"`
+	ID            string `db:"id"`
+	Action        string `db:"action"` // see rules.Actions
+	Expression    string `db:"expression"`
+	CreatedAt     int64  `db:"created_at"`
+}
+
+// GetDecisionRules retrieves all decision rules of a user, oldest first
+func GetDecisionRules(ctx context.Context, reviewerLogin string) ([]*DecisionRule, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+
+		SELECT reviewer_login, id, action, expression, created_at
+		FROM decision_rules
+		WHERE reviewer_login = $reviewer_login
+		ORDER BY created_at, id;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query decision rules for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	var rules []*DecisionRule
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var rule DecisionRule
+			err = res.ScanNamed(
+				named.Required("reviewer_login", &rule.ReviewerLogin),
+				named.Required("id", &rule.ID),
+				named.Required("action", &rule.Action),
+				named.Required("expression", &rule.Expression),
+				named.Required("created_at", &rule.CreatedAt),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan decision rule: %w", err)
+			}
+			rules = append(rules, &rule)
+		}
+	}
+
+	return rules, nil
+}
+
+// AddDecisionRule stores a new decision rule
+func AddDecisionRule(ctx context.Context, rule *DecisionRule) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $id AS Utf8;
+		DECLARE $action AS Utf8;
+		DECLARE $expression AS Utf8;
+		DECLARE $created_at AS Datetime;
+
+		UPSERT INTO decision_rules (reviewer_login, id, action, expression, created_at)
+		VALUES ($reviewer_login, $id, $action, $expression, $created_at);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(rule.ReviewerLogin)),
+		table.ValueParam("$id", types.TextValue(rule.ID)),
+		table.ValueParam("$action", types.TextValue(rule.Action)),
+		table.ValueParam("$expression", types.TextValue(rule.Expression)),
+		table.ValueParam("$created_at", datetimeValueFromUnix(rule.CreatedAt)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// RemoveDecisionRule removes a decision rule
+func RemoveDecisionRule(ctx context.Context, reviewerLogin, id string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $id AS Utf8;
+
+		DELETE FROM decision_rules
+		WHERE reviewer_login = $reviewer_login AND id = $id;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$id", types.TextValue(id)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
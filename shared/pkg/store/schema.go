@@ -0,0 +1,355 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	ydbsdk "github.com/ydb-platform/ydb-go-sdk/v3"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/options"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/settings"
+)
+
+var (
+	initOnce sync.Once
+)
+
+// settingsColumn is a column added to user_settings on top of the base schema
+type settingsColumn struct {
+	name   string
+	ydbTyp string
+}
+
+// settingsColumns lists user_settings columns owned by this module: the registry settings
+// outside the base schema, and the state kept next to them.
+// They are nullable, so rows created before a column existed read back as defaults.
+var settingsColumns = append(registryColumns(), []settingsColumn{
+	{name: "paused", ydbTyp: "Bool"},
+	{name: "paused_until", ydbTyp: "Datetime"},
+	{name: "quiet_hours_start_minute", ydbTyp: "Int32"},
+	{name: "quiet_hours_end_minute", ydbTyp: "Int32"},
+	{name: "timezone", ydbTyp: "Utf8"},
+	{name: "completed_synced_at", ydbTyp: "Datetime"},
+	{name: "decision_policies", ydbTyp: "Utf8"},
+	{name: "working_hours", ydbTyp: "Utf8"}, // see availability.ParseWorkingHours, empty for none
+}...)
+
+// whitelistColumns lists columns added to user_project_whitelist by this module
+var whitelistColumns = []settingsColumn{
+	{name: "expires_at", ydbTyp: "Datetime"}, // NULL for permanent entries
+	{name: "managed", ydbTyp: "Bool"},        // true for entries synced from completed projects
+}
+
+// reviewRequestColumns lists columns added to review_requests by this module
+var reviewRequestColumns = []settingsColumn{
+	{name: "decision_policy", ydbTyp: "Utf8"},               // the policy that decided, NULL until decided
+	{name: "reviewee_login", ydbTyp: "Utf8"},                // comma-separated for teams, NULL while unknown
+	{name: "non_whitelist_ask_held_at", ydbTyp: "Datetime"}, // when asking about a non-whitelisted booking was first held back
+}
+
+// registryColumns returns the columns of registry settings that are not in the base schema
+func registryColumns() []settingsColumn {
+	var columns []settingsColumn
+	for _, s := range settings.All() {
+		if !s.Core {
+			columns = append(columns, settingsColumn{name: s.Column, ydbTyp: s.YDBType()})
+		}
+	}
+	return columns
+}
+
+// isSettingsColumn reports whether column is a user_settings column of the given type
+func isSettingsColumn(column, ydbTyp string) bool {
+	if s := settings.ByColumn(column); s != nil {
+		return s.YDBType() == ydbTyp
+	}
+	for _, col := range settingsColumns {
+		if col.name == column {
+			return col.ydbTyp == ydbTyp
+		}
+	}
+	return false
+}
+
+// tables lists tables owned by this module
+var tables = []struct {
+	name   string
+	schema string
+}{
+	{
+		name: "slot_change_log",
+		schema: `
+			CREATE TABLE slot_change_log (
+				id Utf8,
+				reviewer_login Utf8,
+				slot_id Utf8,
+				action Utf8,
+				reason Utf8,
+				old_start Datetime,
+				old_end Datetime,
+				new_start Datetime,
+				new_end Datetime,
+				created_at Datetime,
+				PRIMARY KEY (id)
+			)
+		`,
+	},
+	{
+		name: "held_messages",
+		schema: `
+			CREATE TABLE held_messages (
+				reviewer_login Utf8,
+				id Utf8,
+				text Utf8,
+				created_at Datetime,
+				PRIMARY KEY (reviewer_login, id)
+			)
+		`,
+	},
+	{
+		name: "availability_templates",
+		schema: `
+			CREATE TABLE availability_templates (
+				reviewer_login Utf8,
+				id Utf8,
+				weekday_mask Int32,
+				start_minute Int32,
+				end_minute Int32,
+				created_at Datetime,
+				PRIMARY KEY (reviewer_login, id)
+			)
+		`,
+	},
+	{
+		name: "user_holidays",
+		schema: `
+			CREATE TABLE user_holidays (
+				reviewer_login Utf8,
+				holiday_date Utf8,
+				PRIMARY KEY (reviewer_login, holiday_date)
+			)
+		`,
+	},
+	{
+		name: "availability_slots",
+		schema: `
+			CREATE TABLE availability_slots (
+				reviewer_login Utf8,
+				occurrence_key Utf8,
+				event_id Utf8,
+				slot_start Datetime,
+				slot_end Datetime,
+				created_at Datetime,
+				PRIMARY KEY (reviewer_login, occurrence_key)
+			)
+		`,
+	},
+	{
+		name: "whitelist_presets",
+		schema: `
+			CREATE TABLE whitelist_presets (
+				name Utf8,
+				owner_login Utf8,
+				created_at Datetime,
+				PRIMARY KEY (name)
+			)
+		`,
+	},
+	{
+		name: "whitelist_preset_entries",
+		schema: `
+			CREATE TABLE whitelist_preset_entries (
+				preset_name Utf8,
+				entry_type Utf8,
+				name Utf8,
+				PRIMARY KEY (preset_name, entry_type, name)
+			)
+		`,
+	},
+	{
+		name: "whitelist_preset_subscriptions",
+		schema: `
+			CREATE TABLE whitelist_preset_subscriptions (
+				reviewer_login Utf8,
+				preset_name Utf8,
+				PRIMARY KEY (reviewer_login, preset_name)
+			)
+		`,
+	},
+	{
+		name: "whitelist_suggestions",
+		schema: `
+			CREATE TABLE whitelist_suggestions (
+				reviewer_login Utf8,
+				entry_type Utf8,
+				name Utf8,
+				suggested_at Datetime,
+				dismissed Bool,
+				PRIMARY KEY (reviewer_login, entry_type, name)
+			)
+		`,
+	},
+	{
+		name: "setting_overrides",
+		schema: `
+			CREATE TABLE setting_overrides (
+				reviewer_login Utf8,
+				entry_type Utf8,
+				name Utf8,
+				setting_key Utf8,
+				value Utf8,
+				PRIMARY KEY (reviewer_login, entry_type, name, setting_key)
+			)
+		`,
+	},
+	{
+		name: "decision_rules",
+		schema: `
+			CREATE TABLE decision_rules (
+				reviewer_login Utf8,
+				id Utf8,
+				action Utf8,
+				expression Utf8,
+				created_at Datetime,
+				PRIMARY KEY (reviewer_login, id)
+			)
+		`,
+	},
+	{
+		name: "peers",
+		schema: `
+			CREATE TABLE peers (
+				reviewer_login Utf8,
+				peer_login Utf8,
+				kind Utf8,
+				created_at Datetime,
+				PRIMARY KEY (reviewer_login, peer_login)
+			)
+		`,
+	},
+}
+
+// InitSchema creates the tables and the user_settings, user_project_whitelist and
+// review_requests columns owned by this module.
+// Should be called once at application startup, after ydb.InitSchema
+func InitSchema(ctx context.Context) error {
+	var initErr error
+	initOnce.Do(func() {
+		initErr = migrate(ctx)
+	})
+	return initErr
+}
+
+// migrate creates missing tables and adds missing columns
+func migrate(ctx context.Context) error {
+	database := os.Getenv("YDB_DATABASE")
+	if database == "" {
+		return fmt.Errorf("YDB_DATABASE environment variable not set")
+	}
+
+	// Ensure database path starts with /
+	if !strings.HasPrefix(database, "/") {
+		database = "/" + database
+	}
+
+	logger := log.New(os.Stdout, "[STORE_SCHEMA] ", log.LstdFlags)
+
+	driver, err := ydb.GetConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get YDB connection: %w", err)
+	}
+
+	for _, tbl := range tables {
+		tablePath := database + "/" + tbl.name
+		if err := createTableIfNotExists(ctx, driver, tablePath, tbl.schema, database, logger); err != nil {
+			return fmt.Errorf("failed to create table %s: %w", tbl.name, err)
+		}
+	}
+
+	if err := addMissingColumns(ctx, driver, database+"/user_settings", "user_settings", settingsColumns, database, logger); err != nil {
+		return fmt.Errorf("failed to migrate user_settings: %w", err)
+	}
+
+	if err := addMissingColumns(ctx, driver, database+"/user_project_whitelist", "user_project_whitelist", whitelistColumns, database, logger); err != nil {
+		return fmt.Errorf("failed to migrate user_project_whitelist: %w", err)
+	}
+
+	if err := addMissingColumns(ctx, driver, database+"/review_requests", "review_requests", reviewRequestColumns, database, logger); err != nil {
+		return fmt.Errorf("failed to migrate review_requests: %w", err)
+	}
+
+	logger.Println("Store schema initialized successfully")
+	return nil
+}
+
+// createTableIfNotExists creates a table if it doesn't already exist
+func createTableIfNotExists(ctx context.Context, driver *ydbsdk.Driver, tablePath, schema, database string, logger *log.Logger) error {
+	err := driver.Table().Do(ctx, func(ctx context.Context, s table.Session) error {
+		_, err := s.DescribeTable(ctx, tablePath)
+		return err
+	})
+	if err == nil {
+		return nil
+	}
+
+	logger.Printf("Creating table: %s", tablePath)
+
+	query := fmt.Sprintf("PRAGMA TablePathPrefix(\"%s\");\n%s", database, schema)
+	err = driver.Table().Do(ctx, func(ctx context.Context, s table.Session) error {
+		return s.ExecuteSchemeQuery(ctx, query)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to execute schema query: %w", err)
+	}
+
+	return nil
+}
+
+// addMissingColumns adds columns that the table doesn't have yet
+func addMissingColumns(ctx context.Context, driver *ydbsdk.Driver, tablePath, tableName string, columns []settingsColumn, database string, logger *log.Logger) error {
+	var desc options.Description
+	err := driver.Table().Do(ctx, func(ctx context.Context, s table.Session) error {
+		var err error
+		desc, err = s.DescribeTable(ctx, tablePath)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe table %s: %w", tableName, err)
+	}
+
+	existing := make(map[string]bool, len(desc.Columns))
+	for _, col := range desc.Columns {
+		existing[col.Name] = true
+	}
+
+	for _, col := range missingColumns(existing, columns) {
+		logger.Printf("Adding column %s.%s", tableName, col.name)
+
+		query := fmt.Sprintf("PRAGMA TablePathPrefix(\"%s\");\nALTER TABLE %s ADD COLUMN %s %s;", database, tableName, col.name, col.ydbTyp)
+		err := driver.Table().Do(ctx, func(ctx context.Context, s table.Session) error {
+			return s.ExecuteSchemeQuery(ctx, query)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add column %s: %w", col.name, err)
+		}
+	}
+
+	return nil
+}
+
+// missingColumns returns the columns not present in existing, preserving order
+func missingColumns(existing map[string]bool, columns []settingsColumn) []settingsColumn {
+	var missing []settingsColumn
+	for _, col := range columns {
+		if !existing[col.name] {
+			missing = append(missing, col)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/settings"
+)
+
+// SettingValues returns the current value of every registry setting
+func SettingValues(userSettings *models.UserSettings, prefs *UserPreferences) settings.Values {
+	values := settings.CoreValues(userSettings)
+	values[settings.SlotHousekeeping] = prefs.SlotHousekeepingMode
+	values[settings.HousekeepingBuffer] = strconv.Itoa(int(prefs.SlotHousekeepingBufferMinutes))
+	values[settings.AvailabilityDays] = strconv.Itoa(int(prefs.AvailabilityDaysAhead))
+	values[settings.Lookahead] = strconv.Itoa(int(prefs.BookingLookaheadHours))
+	values[settings.Lookback] = strconv.Itoa(int(prefs.BookingLookbackHours))
+	values[settings.SyncCompleted] = strconv.FormatBool(prefs.SyncCompletedProjects)
+	values[settings.PausePolicy] = prefs.PausePolicy
+	values[settings.DailyCap] = strconv.Itoa(int(prefs.MaxReviewsPerDay))
+	values[settings.WeeklyCap] = strconv.Itoa(int(prefs.MaxReviewsPerWeek))
+	values[settings.MinGap] = strconv.Itoa(int(prefs.MinReviewGapMinutes))
+	values[settings.LimitAction] = prefs.LimitAction
+	values[settings.OutsideHoursAction] = prefs.OutsideHoursAction
+	values[settings.BlockedPeerAction] = prefs.BlockedPeerAction
+	values[settings.TimeoutAction] = prefs.TimeoutAction
+	values[settings.NonWhitelistAction] = prefs.NonWhitelistAction
+	values[settings.NonWhitelistDeadline] = strconv.Itoa(int(prefs.NonWhitelistDeadlineMinutes))
+	values[settings.Language] = prefs.Language
+	return values
+}
+
+// UpdateSetting stores a registry setting. value must be in the form returned by settings.Parse
+func UpdateSetting(ctx context.Context, reviewerLogin, key, value string) error {
+	s := settings.Get(key)
+	if s == nil {
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	if _, err := s.Parse(value); err != nil {
+		return fmt.Errorf("invalid value %q for setting %s", value, key)
+	}
+
+	if s.Type == settings.Enum {
+		return UpdateTextSetting(ctx, reviewerLogin, s.Column, value)
+	}
+	return ydb.UpdateUserSetting(ctx, reviewerLogin, s.Column, s.Typed(value))
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/types"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+)
+
+// Slot change actions
+const (
+	SlotActionChange = "CHANGE"
+	SlotActionCancel = "CANCEL"
+)
+
+// SlotChange records a change the bot made to a calendar slot
+type SlotChange struct {
+	ID            string `db:"id"`
+	ReviewerLogin string `db:"reviewer_login"`
+	SlotID        string `db:"slot_id"`
+	Action        string `db:"action"`
+	Reason        string `db:"reason"`
+	OldStart      int64  `db:"old_start"`
+	OldEnd        int64  `db:"old_end"`
+	NewStart      *int64 `db:"new_start"`
+	NewEnd        *int64 `db:"new_end"`
+	CreatedAt     int64  `db:"created_at"`
+}
+
+// RecordSlotChange stores a slot change in slot_change_log
+func RecordSlotChange(ctx context.Context, change *SlotChange) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $id AS Utf8;
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $slot_id AS Utf8;
+		DECLARE $action AS Utf8;
+		DECLARE $reason AS Utf8;
+		DECLARE $old_start AS Datetime;
+		DECLARE $old_end AS Datetime;
+		DECLARE $new_start AS Optional<Datetime>;
+		DECLARE $new_end AS Optional<Datetime>;
+		DECLARE $created_at AS Datetime;
+
+		UPSERT INTO slot_change_log (id, reviewer_login, slot_id, action, reason, old_start, old_end, new_start, new_end, created_at)
+		VALUES ($id, $reviewer_login, $slot_id, $action, $reason, $old_start, $old_end, $new_start, $new_end, $created_at);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$id", types.TextValue(change.ID)),
+		table.ValueParam("$reviewer_login", types.TextValue(change.ReviewerLogin)),
+		table.ValueParam("$slot_id", types.TextValue(change.SlotID)),
+		table.ValueParam("$action", types.TextValue(change.Action)),
+		table.ValueParam("$reason", types.TextValue(change.Reason)),
+		table.ValueParam("$old_start", datetimeValueFromUnix(change.OldStart)),
+		table.ValueParam("$old_end", datetimeValueFromUnix(change.OldEnd)),
+		table.ValueParam("$new_start", optionalDatetimeValue(change.NewStart)),
+		table.ValueParam("$new_end", optionalDatetimeValue(change.NewEnd)),
+		table.ValueParam("$created_at", datetimeValueFromUnix(change.CreatedAt)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// datetimeValueFromUnix converts a Unix timestamp to a YDB datetime value
+func datetimeValueFromUnix(ts int64) types.Value {
+	return types.DatetimeValue(uint32(ts))
+}
+
+// optionalDatetimeValue creates an optional datetime value
+func optionalDatetimeValue(ts *int64) types.Value {
+	if ts == nil {
+		return types.NullValue(types.TypeDatetime)
+	}
+	return types.OptionalValue(types.DatetimeValue(uint32(*ts)))
+}
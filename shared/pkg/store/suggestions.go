@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/result/named"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/types"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+)
+
+// Decision is a review the user approved or declined from a WAITING_FOR_APPROVE message
+type Decision struct {
+	ProjectName string `db:"project_name"`
+	FamilyLabel string `db:"family_label"` // empty if the family is unknown
+	Status      string `db:"status"`       // APPROVED or CANCELLED
+}
+
+// GetDecisions retrieves the user's approvals and declines decided since the given Unix time
+func GetDecisions(ctx context.Context, reviewerLogin string, since int64) ([]*Decision, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $approved AS Utf8;
+		DECLARE $cancelled AS Utf8;
+		DECLARE $since AS Datetime;
+
+		SELECT project_name, family_label, status
+		FROM review_requests
+		WHERE reviewer_login = $reviewer_login
+		  AND (status = $approved OR status = $cancelled)
+		  AND project_name IS NOT NULL
+		  AND decided_at >= $since;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$approved", types.TextValue(models.StatusApproved)),
+		table.ValueParam("$cancelled", types.TextValue(models.StatusCancelled)),
+		table.ValueParam("$since", datetimeValueFromUnix(since)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query decisions for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	var decisions []*Decision
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var decision Decision
+			var projectName, familyLabel *string
+			err = res.ScanNamed(
+				named.Optional("project_name", &projectName),
+				named.Optional("family_label", &familyLabel),
+				named.Required("status", &decision.Status),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan decision: %w", err)
+			}
+			if projectName == nil || *projectName == "" {
+				continue
+			}
+			decision.ProjectName = *projectName
+			if familyLabel != nil {
+				decision.FamilyLabel = *familyLabel
+			}
+			decisions = append(decisions, &decision)
+		}
+	}
+
+	return decisions, nil
+}
+
+// SuggestionState records a whitelist suggestion sent to the user
+type SuggestionState struct {
+	EntryType   string `db:"entry_type"` // FAMILY or PROJECT, for suggestions of either list
+	Name        string `db:"name"`
+	SuggestedAt int64  `db:"suggested_at"`
+	Dismissed   bool   `db:"dismissed"`
+}
+
+// MarkSuggested records that a suggestion about a family or project was sent
+func MarkSuggested(ctx context.Context, reviewerLogin, entryType, name string, suggestedAt int64) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $entry_type AS Utf8;
+		DECLARE $name AS Utf8;
+		DECLARE $suggested_at AS Datetime;
+
+		UPSERT INTO whitelist_suggestions (reviewer_login, entry_type, name, suggested_at)
+		VALUES ($reviewer_login, $entry_type, $name, $suggested_at);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$entry_type", types.TextValue(entryType)),
+		table.ValueParam("$name", types.TextValue(name)),
+		table.ValueParam("$suggested_at", datetimeValueFromUnix(suggestedAt)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// DismissSuggestion records that the user does not want suggestions about a family or project
+func DismissSuggestion(ctx context.Context, reviewerLogin, entryType, name string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $entry_type AS Utf8;
+		DECLARE $name AS Utf8;
+
+		UPSERT INTO whitelist_suggestions (reviewer_login, entry_type, name, dismissed)
+		VALUES ($reviewer_login, $entry_type, $name, true);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$entry_type", types.TextValue(entryType)),
+		table.ValueParam("$name", types.TextValue(name)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// GetSuggestionStates retrieves the suggestions sent to the user
+func GetSuggestionStates(ctx context.Context, reviewerLogin string) ([]*SuggestionState, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+
+		SELECT entry_type, name, suggested_at, dismissed
+		FROM whitelist_suggestions
+		WHERE reviewer_login = $reviewer_login;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query suggestions for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	var states []*SuggestionState
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var state SuggestionState
+			var suggestedAt *int64
+			var dismissed *bool
+			err = res.ScanNamed(
+				named.Required("entry_type", &state.EntryType),
+				named.Required("name", &state.Name),
+				named.Optional("suggested_at", &suggestedAt),
+				named.Optional("dismissed", &dismissed),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan suggestion: %w", err)
+			}
+			if suggestedAt != nil {
+				state.SuggestedAt = *suggestedAt
+			}
+			state.Dismissed = dismissed != nil && *dismissed
+			states = append(states, &state)
+		}
+	}
+
+	return states, nil
+}
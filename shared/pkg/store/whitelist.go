@@ -0,0 +1,155 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ydb-platform/ydb-go-sdk/v3/table"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/result/named"
+	"github.com/ydb-platform/ydb-go-sdk/v3/table/types"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+)
+
+// RemoveWhitelistEntry removes a single whitelist entry. Unlike ydb.RemoveFromWhitelist it
+// keeps entries of other types with the same name, e.g. a blacklist entry
+func RemoveWhitelistEntry(ctx context.Context, reviewerLogin, entryType, name string) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $entry_type AS Utf8;
+		DECLARE $name AS Utf8;
+
+		DELETE FROM user_project_whitelist
+		WHERE reviewer_login = $reviewer_login AND entry_type = $entry_type AND name = $name;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$entry_type", types.TextValue(entryType)),
+		table.ValueParam("$name", types.TextValue(name)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// WhitelistExpiry is the end of a temporary whitelist entry
+type WhitelistExpiry struct {
+	EntryType string `db:"entry_type"`
+	Name      string `db:"name"`
+	ExpiresAt int64  `db:"expires_at"`
+}
+
+// WhitelistRow is a whole row of user_project_whitelist, with the columns of this module
+type WhitelistRow struct {
+	ReviewerLogin string
+	EntryType     string
+	Name          string
+	ExpiresAt     *int64 // nil for permanent entries
+	Managed       bool
+}
+
+// UpsertWhitelistEntry stores an entry of either list, replacing the expiry and managed
+// flag of an entry already there. Unlike ydb.AddToWhitelist it does not fail on re-adding
+func UpsertWhitelistEntry(ctx context.Context, row *WhitelistRow) error {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $entry_type AS Utf8;
+		DECLARE $name AS Utf8;
+		DECLARE $expires_at AS Optional<Datetime>;
+		DECLARE $managed AS Bool;
+
+		UPSERT INTO user_project_whitelist (reviewer_login, entry_type, name, expires_at, managed)
+		VALUES ($reviewer_login, $entry_type, $name, $expires_at, $managed);
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(row.ReviewerLogin)),
+		table.ValueParam("$entry_type", types.TextValue(row.EntryType)),
+		table.ValueParam("$name", types.TextValue(row.Name)),
+		table.ValueParam("$expires_at", optionalDatetimeValue(row.ExpiresAt)),
+		table.ValueParam("$managed", types.BoolValue(row.Managed)),
+	}
+
+	return ydb.Exec(ctx, sql, params...)
+}
+
+// GetWhitelistExpiries retrieves the expiry of the user's temporary whitelist entries
+func GetWhitelistExpiries(ctx context.Context, reviewerLogin string) ([]*WhitelistExpiry, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+
+		SELECT entry_type, name, expires_at
+		FROM user_project_whitelist
+		WHERE reviewer_login = $reviewer_login AND expires_at IS NOT NULL;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query whitelist expiries for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	var expiries []*WhitelistExpiry
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var expiry WhitelistExpiry
+			var expiresAt *int64
+			err = res.ScanNamed(
+				named.Required("entry_type", &expiry.EntryType),
+				named.Required("name", &expiry.Name),
+				named.Optional("expires_at", &expiresAt),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to scan whitelist expiry: %w", err)
+			}
+			if expiresAt != nil {
+				expiry.ExpiresAt = *expiresAt
+				expiries = append(expiries, &expiry)
+			}
+		}
+	}
+
+	return expiries, nil
+}
+
+// GetManagedWhitelist retrieves the names of the user's managed project entries
+func GetManagedWhitelist(ctx context.Context, reviewerLogin string) ([]string, error) {
+	sql := ydb.TablePathPrefix("") + `
+		DECLARE $reviewer_login AS Utf8;
+		DECLARE $entry_type AS Utf8;
+
+		SELECT name
+		FROM user_project_whitelist
+		WHERE reviewer_login = $reviewer_login AND entry_type = $entry_type AND managed = true
+		ORDER BY name;
+	`
+
+	params := []table.ParameterOption{
+		table.ValueParam("$reviewer_login", types.TextValue(reviewerLogin)),
+		table.ValueParam("$entry_type", types.TextValue(models.EntryTypeProject)),
+	}
+
+	res, err := ydb.Query(ctx, sql, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query managed whitelist for %s: %w", reviewerLogin, err)
+	}
+	defer res.Close()
+
+	var names []string
+	for res.NextResultSet(ctx) {
+		for res.NextRow() {
+			var name string
+			if err := res.ScanNamed(named.Required("name", &name)); err != nil {
+				return nil, fmt.Errorf("failed to scan managed whitelist entry: %w", err)
+			}
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,112 @@
+package timezone
+
+import (
+	"strings"
+	"time"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+
+	// Embed the zone database, Cloud Functions runtimes do not ship one
+	_ "time/tzdata"
+)
+
+// DefaultZone is used when neither the user nor their campus sets a zone
+const DefaultZone = "Europe/Moscow"
+
+// ShortLayout renders times like timeutil.FormatShort, with the zone abbreviation of the location
+const ShortLayout = "Jan 2 15:04 MST"
+
+// campusZones maps keywords found in School 21 campus short names to their zones
+var campusZones = []struct {
+	keyword string
+	zone    string
+}{
+	{"moscow", "Europe/Moscow"},
+	{"kazan", "Europe/Moscow"},
+	{"novgorod", "Europe/Moscow"},
+	{"yaroslavl", "Europe/Moscow"},
+	{"magas", "Europe/Moscow"},
+	{"belgorod", "Europe/Moscow"},
+	{"samara", "Europe/Samara"},
+	{"ekaterinburg", "Asia/Yekaterinburg"},
+	{"yekaterinburg", "Asia/Yekaterinburg"},
+	{"chelyabinsk", "Asia/Yekaterinburg"},
+	{"surgut", "Asia/Yekaterinburg"},
+	{"tyumen", "Asia/Yekaterinburg"},
+	{"novosibirsk", "Asia/Novosibirsk"},
+	{"yakutsk", "Asia/Yakutsk"},
+	{"tashkent", "Asia/Tashkent"},
+	{"samarkand", "Asia/Samarkand"},
+	{"astana", "Asia/Almaty"},
+	{"almaty", "Asia/Almaty"},
+	{"bishkek", "Asia/Bishkek"},
+}
+
+// Parse validates an IANA zone name such as "Europe/Berlin" and returns its canonical form.
+// Matching is case-insensitive for the common Area/City form
+func Parse(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "local") {
+		return "", i18n.Errorf("timezone.invalid", name)
+	}
+
+	if strings.EqualFold(name, "utc") {
+		return "UTC", nil
+	}
+
+	for _, candidate := range []string{name, canonicalCase(name)} {
+		if _, err := time.LoadLocation(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", i18n.Errorf("timezone.invalid", name)
+}
+
+// Load returns the location for a stored zone name, falling back to DefaultZone
+// for empty or unknown names
+func Load(name string) *time.Location {
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+
+	loc, err := time.LoadLocation(DefaultZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ForCampus returns the zone of a campus given its short name, or DefaultZone
+// when the campus is not known
+func ForCampus(campus string) string {
+	campus = strings.ToLower(campus)
+	for _, c := range campusZones {
+		if strings.Contains(campus, c.keyword) {
+			return c.zone
+		}
+	}
+	return DefaultZone
+}
+
+// FormatShort formats t in loc, e.g. "Jan 2 15:04 MSK"
+func FormatShort(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(ShortLayout)
+}
+
+// canonicalCase turns "europe/moscow" into "Europe/Moscow" and "america/new_york" into "America/New_York"
+func canonicalCase(name string) string {
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		words := strings.Split(part, "_")
+		for j, word := range words {
+			if word != "" {
+				words[j] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+			}
+		}
+		parts[i] = strings.Join(words, "_")
+	}
+	return strings.Join(parts, "/")
+}
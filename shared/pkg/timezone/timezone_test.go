@@ -0,0 +1,85 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		hasError bool
+	}{
+		{"Canonical", "Europe/Moscow", "Europe/Moscow", false},
+		{"LowerCase", "europe/berlin", "Europe/Berlin", false},
+		{"Underscore", "america/new_york", "America/New_York", false},
+		{"UTC", "utc", "UTC", false},
+		{"Spaces", "  Asia/Tashkent ", "Asia/Tashkent", false},
+		{"Empty", "", "", true},
+		{"Local", "Local", "", true},
+		{"Unknown", "Mars/Olympus", "", true},
+		{"Offset", "+03:00", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, err := Parse(tt.input)
+			if tt.hasError {
+				var i18nErr *i18n.Error
+				require.ErrorAs(t, err, &i18nErr)
+				assert.Equal(t, "timezone.invalid", i18nErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, zone)
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	assert.Equal(t, "Asia/Novosibirsk", Load("Asia/Novosibirsk").String())
+	assert.Equal(t, DefaultZone, Load("").String())
+	assert.Equal(t, DefaultZone, Load("Mars/Olympus").String())
+}
+
+func TestForCampus(t *testing.T) {
+	tests := []struct {
+		campus   string
+		expected string
+	}{
+		{"21 Moscow", "Europe/Moscow"},
+		{"Novosibirsk", "Asia/Novosibirsk"},
+		{"21 Tashkent", "Asia/Tashkent"},
+		{"YAKUTSK", "Asia/Yakutsk"},
+		{"", DefaultZone},
+		{"Unknown campus", DefaultZone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.campus, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ForCampus(tt.campus))
+		})
+	}
+}
+
+func TestFormatShort(t *testing.T) {
+	tm := time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, "Jan 15 14:30 UTC", FormatShort(tm, time.UTC))
+	assert.Equal(t, "Jan 15 17:30 MSK", FormatShort(tm, Load("Europe/Moscow")))
+
+	t.Run("DST", func(t *testing.T) {
+		berlin := Load("Europe/Berlin")
+
+		// Berlin switches from CET to CEST on Mar 29 2026
+		assert.Equal(t, "Mar 28 19:00 CET", FormatShort(time.Date(2026, 3, 28, 18, 0, 0, 0, time.UTC), berlin))
+		assert.Equal(t, "Mar 29 20:00 CEST", FormatShort(time.Date(2026, 3, 29, 18, 0, 0, 0, time.UTC), berlin))
+	})
+}
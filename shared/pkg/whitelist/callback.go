@@ -0,0 +1,86 @@
+package whitelist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Whitelist button actions, used by the whitelist browser and by suggestions
+const (
+	ActionFamilies      = "families"
+	ActionFamily        = "family"
+	ActionToggleFamily  = "toggle_family"
+	ActionToggleProject = "toggle_project"
+	ActionAddFamily     = "add_family"
+	ActionAddProject    = "add_project"
+	// Blacklist suggestions of /blacklist_add
+	ActionBlacklistFamily  = "block_family"
+	ActionBlacklistProject = "block_project"
+	// Suggestions learned from the approval history the user does not want
+	ActionDismissFamily  = "dismiss_family"
+	ActionDismissProject = "dismiss_project"
+)
+
+const callbackPrefix = "WL"
+
+// Callback is the data of a whitelist button. Family and Project are IDs from
+// projects.ID, Project is empty for a family and both are empty for the family list.
+// ExpiresAt is the expiry of a temporary entry a suggestion adds as Unix seconds, or 0
+type Callback struct {
+	Action    string
+	Family    string
+	Project   string
+	Page      int
+	ExpiresAt int64
+}
+
+// FormatCallbackData creates callback data for a whitelist button, e.g.
+// "WL:toggle_project:1x2y3z:9a8b7c:0:0". Families and projects are referred to by ID, since
+// names may not fit the 64 byte limit of callback data and positions in the catalog shift
+// as it grows
+func FormatCallbackData(c Callback) string {
+	return fmt.Sprintf("%s:%s:%s:%s:%d:%d", callbackPrefix, c.Action, c.Family, c.Project, c.Page, c.ExpiresAt)
+}
+
+// ParseCallbackData parses callback data created by FormatCallbackData
+func ParseCallbackData(data string) (Callback, bool) {
+	parts := strings.Split(data, ":")
+	if len(parts) != 6 || parts[0] != callbackPrefix {
+		return Callback{}, false
+	}
+
+	switch parts[1] {
+	case ActionFamilies, ActionFamily, ActionToggleFamily,
+		ActionToggleProject, ActionAddFamily, ActionAddProject,
+		ActionBlacklistFamily, ActionBlacklistProject,
+		ActionDismissFamily, ActionDismissProject:
+	default:
+		return Callback{}, false
+	}
+
+	if !validID(parts[2]) || !validID(parts[3]) {
+		return Callback{}, false
+	}
+
+	page, err := strconv.Atoi(parts[4])
+	if err != nil || page < 0 {
+		return Callback{}, false
+	}
+	expiresAt, err := strconv.ParseInt(parts[5], 10, 64)
+	if err != nil || expiresAt < 0 {
+		return Callback{}, false
+	}
+
+	return Callback{Action: parts[1], Family: parts[2], Project: parts[3], Page: page, ExpiresAt: expiresAt}, true
+}
+
+// validID reports whether s may be an ID from projects.ID, or empty
+func validID(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return len(s) <= 10
+}
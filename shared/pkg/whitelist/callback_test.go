@@ -0,0 +1,54 @@
+package whitelist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/projects"
+)
+
+func TestCallbackData(t *testing.T) {
+	goID, projectID := projects.ID("Go"), projects.ID("go-concurrency")
+
+	tests := []struct {
+		name     string
+		data     string
+		callback Callback
+		ok       bool
+	}{
+		{"Families", FormatCallbackData(Callback{Action: ActionFamilies, Page: 1}), Callback{Action: ActionFamilies, Page: 1}, true},
+		{"ToggleProject", FormatCallbackData(Callback{Action: ActionToggleProject, Family: goID, Project: projectID}), Callback{Action: ActionToggleProject, Family: goID, Project: projectID}, true},
+		{"AddFamily", FormatCallbackData(Callback{Action: ActionAddFamily, Family: goID}), Callback{Action: ActionAddFamily, Family: goID}, true},
+		{"BlacklistProject", FormatCallbackData(Callback{Action: ActionBlacklistProject, Family: goID, Project: projectID}), Callback{Action: ActionBlacklistProject, Family: goID, Project: projectID}, true},
+		{"Temporary", FormatCallbackData(Callback{Action: ActionAddProject, Family: goID, Project: projectID, ExpiresAt: 1768867200}), Callback{Action: ActionAddProject, Family: goID, Project: projectID, ExpiresAt: 1768867200}, true},
+		{"DismissFamily", FormatCallbackData(Callback{Action: ActionDismissFamily, Family: goID}), Callback{Action: ActionDismissFamily, Family: goID}, true},
+		{"UnknownAction", "WL:drop:a:b:0:0", Callback{}, false},
+		{"NegativePage", "WL:families:::-1:0", Callback{}, false},
+		{"NegativeExpiry", "WL:add_family:a::0:-1", Callback{}, false},
+		{"NotAnID", "WL:family:Go!:x:0:0", Callback{}, false},
+		{"LongID", "WL:family:abcdefghijklm::0:0", Callback{}, false},
+		{"PageWithoutExpiry", "WL:family:a::1", Callback{}, false},
+		{"TooShort", "WL:family:1", Callback{}, false},
+		{"SettingsData", "SETTINGS:open:lookback", Callback{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callback, ok := ParseCallbackData(tt.data)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.callback, callback)
+		})
+	}
+
+	// Telegram limits callback data to 64 bytes, also with an expiry
+	longest := FormatCallbackData(Callback{
+		Action:    ActionBlacklistProject,
+		Family:    "zzzzzzzzzz",
+		Project:   "zzzzzzzzzz",
+		Page:      999,
+		ExpiresAt: time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+	})
+	assert.LessOrEqual(t, len(longest), 64)
+}
@@ -0,0 +1,167 @@
+package whitelist
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/availability"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+// MaxExpiry limits how far ahead a temporary entry may expire
+const MaxExpiry = 365 * 24 * time.Hour
+
+// Expiries holds the end of the user's temporary entries; permanent entries are absent
+type Expiries map[string]time.Time
+
+// NewExpiries indexes expiries read from the store
+func NewExpiries(rows []*store.WhitelistExpiry) Expiries {
+	expiries := make(Expiries, len(rows))
+	for _, row := range rows {
+		expiries[row.EntryType+":"+row.Name] = time.Unix(row.ExpiresAt, 0)
+	}
+	return expiries
+}
+
+// Of returns when entry expires, or false for a permanent entry
+func (e Expiries) Of(entry *models.WhitelistEntry) (time.Time, bool) {
+	t, ok := e[entry.EntryType+":"+entry.Name]
+	return t, ok
+}
+
+// Expired reports whether entry has expired at now
+func (e Expiries) Expired(entry *models.WhitelistEntry, now time.Time) bool {
+	t, ok := e.Of(entry)
+	return ok && !now.Before(t)
+}
+
+// Active returns the entries that have not expired at now
+func (e Expiries) Active(entries []*models.WhitelistEntry, now time.Time) []*models.WhitelistEntry {
+	var active []*models.WhitelistEntry
+	for _, entry := range entries {
+		if !e.Expired(entry, now) {
+			active = append(active, entry)
+		}
+	}
+	return active
+}
+
+// Entries returns the user's entries that have not expired, with the expiries of the
+// temporary ones. Use it instead of ydb.GetUserWhitelist, which knows nothing of expiry
+func Entries(ctx context.Context, reviewerLogin string, now time.Time) ([]*models.WhitelistEntry, Expiries, error) {
+	entries, err := ydb.GetUserWhitelist(ctx, reviewerLogin)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get whitelist for %s: %w", reviewerLogin, err)
+	}
+	rows, err := store.GetWhitelistExpiries(ctx, reviewerLogin)
+	if err != nil {
+		return nil, nil, err
+	}
+	expiries := NewExpiries(rows)
+	return expiries.Active(entries, now), expiries, nil
+}
+
+// upsertEntry writes a whole whitelist row, replaced by an in-memory table in tests
+var upsertEntry = store.UpsertWhitelistEntry
+
+// Add stores an entry of either list, temporary if expiresAt is set. Re-adding an entry
+// replaces its expiry, so re-adding a temporary entry without one makes it permanent, and
+// an entry the user adds is theirs, even if syncing added it before
+func Add(ctx context.Context, entry *models.WhitelistEntry, expiresAt *time.Time) error {
+	row := &store.WhitelistRow{ReviewerLogin: entry.ReviewerLogin, EntryType: entry.EntryType, Name: entry.Name}
+	if expiresAt != nil {
+		ts := expiresAt.Unix()
+		row.ExpiresAt = &ts
+	}
+	return upsertEntry(ctx, row)
+}
+
+// RemoveExpired deletes the user's entries that have expired at now and returns them
+func RemoveExpired(ctx context.Context, reviewerLogin string, now time.Time) ([]*models.WhitelistEntry, error) {
+	rows, err := store.GetWhitelistExpiries(ctx, reviewerLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []*models.WhitelistEntry
+	for _, row := range rows {
+		if now.Before(time.Unix(row.ExpiresAt, 0)) {
+			continue
+		}
+		if err := store.RemoveWhitelistEntry(ctx, reviewerLogin, row.EntryType, row.Name); err != nil {
+			return removed, fmt.Errorf("failed to remove expired entry %s: %w", row.Name, err)
+		}
+		removed = append(removed, &models.WhitelistEntry{ReviewerLogin: reviewerLogin, EntryType: row.EntryType, Name: row.Name})
+	}
+	return removed, nil
+}
+
+// SplitExpiry splits a trailing "until <YYYY-MM-DD>" or "for <duration>" off /whitelist_add
+// arguments. A date is inclusive, the entry expires at the end of that date in loc; a duration
+// is a number of hours, days or weeks such as "12h", "3d" or "2w". The expiry is nil when there is neither
+func SplitExpiry(args string, now time.Time, loc *time.Location) (string, *time.Time, error) {
+	args = strings.TrimSpace(args)
+	fields := strings.Fields(args)
+	if len(fields) < 3 {
+		return args, nil, nil
+	}
+
+	keyword, value := strings.ToLower(fields[len(fields)-2]), strings.ToLower(fields[len(fields)-1])
+	var expiresAt time.Time
+	switch keyword {
+	case "until":
+		date, err := time.ParseInLocation(availability.DateLayout, value, loc)
+		if err != nil {
+			return "", nil, i18n.Errorf("common.invalid_date", "2026-01-20")
+		}
+		expiresAt = date.AddDate(0, 0, 1)
+	case "for":
+		d, err := parseDuration(value)
+		if err != nil {
+			return "", nil, err
+		}
+		expiresAt = now.Add(d)
+	default:
+		return args, nil, nil
+	}
+
+	if !expiresAt.After(now) {
+		return "", nil, i18n.Errorf("whitelist.expiry_in_past")
+	}
+	if expiresAt.Sub(now) > MaxExpiry {
+		return "", nil, i18n.Errorf("whitelist.expiry_too_far", int(MaxExpiry.Hours()/24))
+	}
+
+	// Cut the two fields off the original text, keeping the spacing of the name
+	rest := strings.TrimSpace(args[:len(args)-len(fields[len(fields)-1])])
+	rest = strings.TrimSpace(rest[:len(rest)-len(fields[len(fields)-2])])
+	return rest, &expiresAt, nil
+}
+
+// parseDuration parses "12h", "3d" or "2w"
+func parseDuration(value string) (time.Duration, error) {
+	units := map[byte]time.Duration{
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}
+
+	if len(value) < 2 {
+		return 0, i18n.Errorf("whitelist.invalid_duration", value)
+	}
+	unit, ok := units[value[len(value)-1]]
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if !ok || err != nil || n <= 0 {
+		return 0, i18n.Errorf("whitelist.invalid_duration", value)
+	}
+	if time.Duration(n) > MaxExpiry/unit {
+		return 0, i18n.Errorf("whitelist.expiry_too_far", int(MaxExpiry.Hours()/24))
+	}
+	return time.Duration(n) * unit, nil
+}
@@ -0,0 +1,155 @@
+package whitelist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+func TestSplitExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		args     string
+		rest     string
+		expected *time.Time
+		errKey   string
+	}{
+		{"Permanent", `family "C - I"`, `family "C - I"`, nil, ""},
+		{"Until", `family "C - I" until 2026-01-20`, `family "C - I"`, timePtr(time.Date(2026, 1, 21, 0, 0, 0, 0, time.UTC)), ""},
+		{"UntilToday", "family Go until 2026-01-15", "family Go", timePtr(time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)), ""},
+		{"ForDays", "project go-concurrency for 3d", "project go-concurrency", timePtr(now.Add(72 * time.Hour)), ""},
+		{"ForHoursUpperCase", "glob CPP* FOR 12H", "glob CPP*", timePtr(now.Add(12 * time.Hour)), ""},
+		{"ForWeeks", "family Go for 2w", "family Go", timePtr(now.Add(14 * 24 * time.Hour)), ""},
+		{"KeepsSpacing", "family C  -  I for 1d", "family C  -  I", timePtr(now.Add(24 * time.Hour)), ""},
+		{"NameOnly", "family Go", "family Go", nil, ""},
+		{"BadDate", "family Go until 20.01.2026", "", nil, "common.invalid_date"},
+		{"PastDate", "family Go until 2026-01-14", "", nil, "whitelist.expiry_in_past"},
+		{"TooFar", "family Go until 2027-06-01", "", nil, "whitelist.expiry_too_far"},
+		{"BadUnit", "family Go for 3m", "", nil, "whitelist.invalid_duration"},
+		{"Zero", "family Go for 0d", "", nil, "whitelist.invalid_duration"},
+		{"Huge", "family Go for 99999999999w", "", nil, "whitelist.expiry_too_far"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest, expiresAt, err := SplitExpiry(tt.args, now, time.UTC)
+			if tt.errKey != "" {
+				var i18nErr *i18n.Error
+				require.ErrorAs(t, err, &i18nErr)
+				assert.Equal(t, tt.errKey, i18nErr.Key)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.rest, rest)
+			if tt.expected == nil {
+				assert.Nil(t, expiresAt)
+				return
+			}
+			require.NotNil(t, expiresAt)
+			assert.True(t, tt.expected.Equal(*expiresAt), "expected %s, got %s", tt.expected, expiresAt)
+		})
+	}
+
+	t.Run("EndOfDayInUserTimezone", func(t *testing.T) {
+		moscow, err := time.LoadLocation("Europe/Moscow")
+		require.NoError(t, err)
+
+		_, expiresAt, err := SplitExpiry("family Go until 2026-01-20", now, moscow)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 1, 20, 21, 0, 0, 0, time.UTC).Unix(), expiresAt.Unix())
+
+		// The entry lasts all of the given date and is gone right after it
+		entry := &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "Go"}
+		expiries := Expiries{entry.EntryType + ":" + entry.Name: *expiresAt}
+		assert.False(t, expiries.Expired(entry, time.Date(2026, 1, 20, 23, 59, 0, 0, moscow)))
+		assert.True(t, expiries.Expired(entry, time.Date(2026, 1, 21, 0, 0, 0, 0, moscow)))
+	})
+}
+
+func TestExpiries(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	permanent := &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "C - I"}
+	current := &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "Go"}
+	expired := &models.WhitelistEntry{EntryType: models.EntryTypeProject, Name: "DO1_Linux"}
+
+	expiries := NewExpiries([]*store.WhitelistExpiry{
+		{EntryType: models.EntryTypeFamily, Name: "Go", ExpiresAt: now.Add(time.Hour).Unix()},
+		{EntryType: models.EntryTypeProject, Name: "DO1_Linux", ExpiresAt: now.Unix()},
+	})
+
+	_, ok := expiries.Of(permanent)
+	assert.False(t, ok)
+	until, ok := expiries.Of(current)
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(time.Hour).Unix(), until.Unix())
+
+	assert.False(t, expiries.Expired(permanent, now))
+	assert.False(t, expiries.Expired(current, now))
+	assert.True(t, expiries.Expired(expired, now), "an entry expires at its expiry time")
+
+	active := expiries.Active([]*models.WhitelistEntry{permanent, current, expired}, now)
+	assert.Equal(t, []*models.WhitelistEntry{permanent, current}, active)
+	assert.Equal(t, []*models.WhitelistEntry{permanent}, expiries.Active([]*models.WhitelistEntry{permanent, current}, now.Add(time.Hour)))
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// fakeWhitelistTable stands in for user_project_whitelist while a test runs
+func fakeWhitelistTable(t *testing.T) map[string]store.WhitelistRow {
+	rows := map[string]store.WhitelistRow{}
+	saved := upsertEntry
+	upsertEntry = func(_ context.Context, row *store.WhitelistRow) error {
+		rows[row.EntryType+":"+row.Name] = *row
+		return nil
+	}
+	t.Cleanup(func() { upsertEntry = saved })
+	return rows
+}
+
+func TestAdd(t *testing.T) {
+	ctx := context.Background()
+	entry := &models.WhitelistEntry{ReviewerLogin: "testuser", EntryType: models.EntryTypeFamily, Name: "Go"}
+	first := time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC)
+	second := first.AddDate(0, 0, 7)
+
+	t.Run("ReAddChangesExpiry", func(t *testing.T) {
+		rows := fakeWhitelistTable(t)
+		require.NoError(t, Add(ctx, entry, &first))
+		require.NoError(t, Add(ctx, entry, &second))
+
+		row := rows[models.EntryTypeFamily+":Go"]
+		require.NotNil(t, row.ExpiresAt)
+		assert.Equal(t, second.Unix(), *row.ExpiresAt)
+	})
+
+	t.Run("ReAddWithoutExpiryIsPermanent", func(t *testing.T) {
+		rows := fakeWhitelistTable(t)
+		require.NoError(t, Add(ctx, entry, &first))
+		require.NoError(t, Add(ctx, entry, nil))
+
+		assert.Len(t, rows, 1)
+		assert.Nil(t, rows[models.EntryTypeFamily+":Go"].ExpiresAt)
+	})
+
+	t.Run("SyncedProjectBecomesTheUsers", func(t *testing.T) {
+		rows := fakeWhitelistTable(t)
+		rows[models.EntryTypeProject+":go-concurrency"] = store.WhitelistRow{ReviewerLogin: "testuser", EntryType: models.EntryTypeProject, Name: "go-concurrency", Managed: true}
+
+		project := &models.WhitelistEntry{ReviewerLogin: "testuser", EntryType: models.EntryTypeProject, Name: "go-concurrency"}
+		require.NoError(t, Add(ctx, project, nil))
+
+		assert.Len(t, rows, 1)
+		assert.False(t, rows[models.EntryTypeProject+":go-concurrency"].Managed, "syncing no longer removes it")
+	})
+}
@@ -0,0 +1,54 @@
+package whitelist
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+// MaxPresetNameLength limits the length of preset names
+const MaxPresetNameLength = 32
+
+var presetNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ValidatePresetName checks a preset name. Only letters, digits, "-" and "_" are allowed,
+// so names can be typed in commands without quotes
+func ValidatePresetName(name string) error {
+	if len(name) > MaxPresetNameLength || !presetNamePattern.MatchString(name) {
+		return i18n.Errorf("preset.invalid_name", MaxPresetNameLength)
+	}
+	return nil
+}
+
+// IsValidPresetEntryType checks if an entry type may be used in presets. Presets share
+// what a team reviews, so they take whitelist entries only
+func IsValidPresetEntryType(entryType string) bool {
+	return IsValidEntryType(entryType) && !IsBlacklisted(entryType)
+}
+
+// Effective returns the entries deciding on the user's bookings: the personal entries that
+// have not expired, followed by the entries of the presets the user subscribes to. The map
+// tells which preset each of the latter comes from
+func Effective(ctx context.Context, reviewerLogin string, now time.Time) ([]*models.WhitelistEntry, map[*models.WhitelistEntry]string, error) {
+	entries, _, err := Entries(ctx, reviewerLogin, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	presetEntries, err := store.GetSubscribedPresetEntries(ctx, reviewerLogin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	presets := make(map[*models.WhitelistEntry]string, len(presetEntries))
+	for _, pe := range presetEntries {
+		entry := &models.WhitelistEntry{ReviewerLogin: reviewerLogin, EntryType: pe.EntryType, Name: pe.Name}
+		entries = append(entries, entry)
+		presets[entry] = pe.PresetName
+	}
+	return entries, presets, nil
+}
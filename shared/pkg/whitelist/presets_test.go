@@ -0,0 +1,53 @@
+package whitelist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+)
+
+func TestValidatePresetName(t *testing.T) {
+	assert.NoError(t, ValidatePresetName("team-go"))
+	assert.NoError(t, ValidatePresetName("C_I_2026"))
+	assert.Error(t, ValidatePresetName(""))
+	assert.Error(t, ValidatePresetName("team go"))
+	assert.Error(t, ValidatePresetName("команда"))
+	assert.Error(t, ValidatePresetName(strings.Repeat("a", MaxPresetNameLength+1)))
+}
+
+func TestIsValidPresetEntryType(t *testing.T) {
+	assert.True(t, IsValidPresetEntryType(models.EntryTypeFamily))
+	assert.True(t, IsValidPresetEntryType(EntryTypeGlob))
+	assert.False(t, IsValidPresetEntryType(BlacklistType(models.EntryTypeFamily)))
+	assert.False(t, IsValidPresetEntryType("TEAM"))
+}
+
+func TestFindWithPresets(t *testing.T) {
+	personal := &models.WhitelistEntry{EntryType: models.EntryTypeProject, Name: "go-concurrency"}
+	denied := &models.WhitelistEntry{EntryType: BlacklistType(models.EntryTypeProject), Name: "DO1_Linux"}
+	fromPreset := &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "Go"}
+	devops := &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "DevOps"}
+	entries := []*models.WhitelistEntry{personal, denied, fromPreset, devops}
+	presets := map[*models.WhitelistEntry]string{fromPreset: "team-go", devops: "team-ops"}
+
+	tests := []struct {
+		name     string
+		project  string
+		family   string
+		expected *Match
+	}{
+		{"Personal", "go-concurrency", "Go", &Match{Entry: personal}},
+		{"Preset", "go-boilerplate", "Go", &Match{Entry: fromPreset, ByFamily: true, Preset: "team-go"}},
+		{"PersonalBlacklistWinsOverPreset", "DO1_Linux", "DevOps", &Match{Entry: denied, Blacklisted: true}},
+		{"NoMatch", "CPP1_s21_matrixplus", "CPP", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, FindWithPresets(entries, presets, tt.project, tt.family))
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package whitelist
+
+import (
+	"sort"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+// A family or project is suggested once the user decided on it at least SuggestMinDecisions
+// times, and SuggestMinPercent of the decisions went the same way
+const (
+	SuggestMinDecisions = 3
+	SuggestMinPercent   = 80
+)
+
+// Suggestion proposes an entry learned from the user's decisions
+type Suggestion struct {
+	// Entry is a family or project entry of the list the decisions point to
+	Entry    *models.WhitelistEntry
+	Approved int
+	Declined int
+}
+
+// Blacklist reports whether the suggestion is to blacklist rather than whitelist
+func (s *Suggestion) Blacklist() bool {
+	return IsBlacklisted(s.Entry.EntryType)
+}
+
+// tally counts the decisions on a family or project
+type tally struct {
+	entryType string
+	name      string
+	approved  int
+	declined  int
+}
+
+func (t *tally) add(status string) {
+	if status == models.StatusApproved {
+		t.approved++
+	} else {
+		t.declined++
+	}
+}
+
+// suggestion returns the entry the decisions point to, or nil if they are too few or mixed
+func (t *tally) suggestion() *Suggestion {
+	total := t.approved + t.declined
+	if total < SuggestMinDecisions {
+		return nil
+	}
+
+	entryType := t.entryType
+	switch {
+	case t.approved*100 >= total*SuggestMinPercent:
+	case t.declined*100 >= total*SuggestMinPercent:
+		entryType = BlacklistType(entryType)
+	default:
+		return nil
+	}
+	return &Suggestion{
+		Entry:    &models.WhitelistEntry{EntryType: entryType, Name: t.name},
+		Approved: t.approved,
+		Declined: t.declined,
+	}
+}
+
+// Suggest looks for families and projects the user keeps approving or declining by hand
+// and returns entries that would have decided for them, most decisions first. A family
+// is preferred over its projects. Families and projects an entry already covers are left
+// out, and so are those skip reports, given their FAMILY or PROJECT type and name
+func Suggest(decisions []*store.Decision, entries []*models.WhitelistEntry, skip func(entryType, name string) bool) []*Suggestion {
+	families := make(map[string]*tally)
+	projects := make(map[string]*tally)
+	familyOf := make(map[string]string)
+	var familyOrder, projectOrder []string
+
+	for _, d := range decisions {
+		if d.FamilyLabel != "" {
+			if families[d.FamilyLabel] == nil {
+				families[d.FamilyLabel] = &tally{entryType: models.EntryTypeFamily, name: d.FamilyLabel}
+				familyOrder = append(familyOrder, d.FamilyLabel)
+			}
+			families[d.FamilyLabel].add(d.Status)
+		}
+		if projects[d.ProjectName] == nil {
+			projects[d.ProjectName] = &tally{entryType: models.EntryTypeProject, name: d.ProjectName}
+			projectOrder = append(projectOrder, d.ProjectName)
+		}
+		projects[d.ProjectName].add(d.Status)
+		familyOf[d.ProjectName] = d.FamilyLabel
+	}
+
+	var suggestions []*Suggestion
+	suggested := make(map[string]bool)
+	for _, label := range familyOrder {
+		s := families[label].suggestion()
+		if s == nil || Find(entries, "", label) != nil {
+			continue
+		}
+		// A family of mixed projects is no single decision, whatever the total says
+		if !familyAgrees(projects, familyOf, label, s.Blacklist()) {
+			continue
+		}
+		suggested[label] = true
+		if skip(models.EntryTypeFamily, label) {
+			continue
+		}
+		suggestions = append(suggestions, s)
+	}
+
+	for _, name := range projectOrder {
+		if suggested[familyOf[name]] {
+			continue
+		}
+		s := projects[name].suggestion()
+		if s == nil || Find(entries, name, familyOf[name]) != nil || skip(models.EntryTypeProject, name) {
+			continue
+		}
+		suggestions = append(suggestions, s)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Approved+suggestions[i].Declined > suggestions[j].Approved+suggestions[j].Declined
+	})
+	return suggestions
+}
+
+// familyAgrees reports whether no project of the family was mostly decided the other way
+func familyAgrees(projects map[string]*tally, familyOf map[string]string, label string, blacklist bool) bool {
+	for name, t := range projects {
+		if familyOf[name] != label {
+			continue
+		}
+		if blacklist && t.approved > t.declined || !blacklist && t.declined > t.approved {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package whitelist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+func decisions(status, family string, projects ...string) []*store.Decision {
+	var result []*store.Decision
+	for _, project := range projects {
+		result = append(result, &store.Decision{ProjectName: project, FamilyLabel: family, Status: status})
+	}
+	return result
+}
+
+func TestSuggest(t *testing.T) {
+	noSkip := func(string, string) bool { return false }
+	approved, declined := models.StatusApproved, models.StatusCancelled
+
+	t.Run("Family", func(t *testing.T) {
+		history := decisions(approved, "C - I", "C2_SimpleBashUtils", "C3_s21_string+", "C2_SimpleBashUtils")
+		suggestions := Suggest(history, nil, noSkip)
+		require.Len(t, suggestions, 1)
+		assert.Equal(t, &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "C - I"}, suggestions[0].Entry)
+		assert.Equal(t, 3, suggestions[0].Approved)
+		assert.False(t, suggestions[0].Blacklist())
+	})
+
+	t.Run("Blacklist", func(t *testing.T) {
+		history := decisions(declined, "", "DO1_Linux", "DO1_Linux", "DO1_Linux")
+		suggestions := Suggest(history, nil, noSkip)
+		require.Len(t, suggestions, 1)
+		assert.Equal(t, BlacklistType(models.EntryTypeProject), suggestions[0].Entry.EntryType)
+		assert.True(t, suggestions[0].Blacklist())
+	})
+
+	t.Run("TooFewDecisions", func(t *testing.T) {
+		assert.Empty(t, Suggest(decisions(approved, "Go", "go-concurrency", "go-boilerplate"), nil, noSkip))
+	})
+
+	t.Run("MixedDecisions", func(t *testing.T) {
+		history := append(decisions(approved, "", "DO1_Linux", "DO1_Linux", "DO1_Linux"), decisions(declined, "", "DO1_Linux")...)
+		assert.Empty(t, Suggest(history, nil, noSkip), "75% is below the threshold")
+	})
+
+	t.Run("MixedFamilyFallsBackToProjects", func(t *testing.T) {
+		history := append(decisions(approved, "Go", "go-concurrency", "go-concurrency", "go-concurrency", "go-concurrency"),
+			decisions(declined, "Go", "go-boilerplate")...)
+		suggestions := Suggest(history, nil, noSkip)
+		require.Len(t, suggestions, 1)
+		assert.Equal(t, &models.WhitelistEntry{EntryType: models.EntryTypeProject, Name: "go-concurrency"}, suggestions[0].Entry)
+	})
+
+	t.Run("CoveredByEntry", func(t *testing.T) {
+		history := decisions(approved, "Go", "go-concurrency", "go-concurrency", "go-concurrency")
+		entries := []*models.WhitelistEntry{{EntryType: EntryTypeGlob, Name: "Go*"}}
+		assert.Empty(t, Suggest(history, entries, noSkip))
+
+		entries = []*models.WhitelistEntry{{EntryType: models.EntryTypeProject, Name: "go-concurrency"}}
+		suggestions := Suggest(history, entries, noSkip)
+		require.Len(t, suggestions, 1, "the family is still open")
+		assert.Equal(t, models.EntryTypeFamily, suggestions[0].Entry.EntryType)
+	})
+
+	t.Run("SkippedFamilyHidesItsProjects", func(t *testing.T) {
+		history := decisions(approved, "Go", "go-concurrency", "go-concurrency", "go-concurrency")
+		skip := func(entryType, name string) bool { return entryType == models.EntryTypeFamily && name == "Go" }
+		assert.Empty(t, Suggest(history, nil, skip))
+	})
+
+	t.Run("MostDecisionsFirst", func(t *testing.T) {
+		history := append(decisions(approved, "", "A", "A", "A"), decisions(declined, "", "B", "B", "B", "B")...)
+		suggestions := Suggest(history, nil, noSkip)
+		require.Len(t, suggestions, 2)
+		assert.Equal(t, "B", suggestions[0].Entry.Name)
+		assert.Equal(t, "A", suggestions[1].Entry.Name)
+	})
+}
@@ -0,0 +1,93 @@
+package whitelist
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+// PlanSync compares the user's completed projects with their entries and returns the managed
+// project entries to add and to remove. Projects with a manual project entry are left to the
+// user, and blacklisted projects get no managed entry. An empty completed list removes nothing
+// else, since a profile never loses its finished projects and an empty answer is more likely
+// a glitch
+func PlanSync(completed []string, entries []*models.WhitelistEntry, managed []string) (add, remove []string) {
+	isManaged := make(map[string]bool, len(managed))
+	for _, name := range managed {
+		isManaged[name] = true
+	}
+
+	manual := make(map[string]bool, len(entries))
+	blocked := make(map[string]bool)
+	for _, entry := range entries {
+		switch entry.EntryType {
+		case models.EntryTypeProject:
+			manual[entry.Name] = !isManaged[entry.Name]
+		case BlacklistType(models.EntryTypeProject):
+			blocked[entry.Name] = true
+		}
+	}
+
+	isCompleted := make(map[string]bool, len(completed))
+	for _, name := range completed {
+		if !isCompleted[name] && !manual[name] && !blocked[name] && !isManaged[name] {
+			add = append(add, name)
+		}
+		isCompleted[name] = true
+	}
+
+	for _, name := range managed {
+		if blocked[name] || (len(completed) > 0 && !isCompleted[name]) {
+			remove = append(remove, name)
+		}
+	}
+	return add, remove
+}
+
+// Sync keeps the user's managed project entries in line with their completed projects and
+// returns the projects it added
+func Sync(ctx context.Context, reviewerLogin string, completed []string) ([]string, error) {
+	entries, err := ydb.GetUserWhitelist(ctx, reviewerLogin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get whitelist for %s: %w", reviewerLogin, err)
+	}
+	managed, err := store.GetManagedWhitelist(ctx, reviewerLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	add, remove := PlanSync(completed, entries, managed)
+
+	var added []string
+	for _, name := range add {
+		row := &store.WhitelistRow{ReviewerLogin: reviewerLogin, EntryType: models.EntryTypeProject, Name: name, Managed: true}
+		if err := upsertEntry(ctx, row); err != nil {
+			return added, fmt.Errorf("failed to add completed project %s: %w", name, err)
+		}
+		added = append(added, name)
+	}
+
+	for _, name := range remove {
+		if err := store.RemoveWhitelistEntry(ctx, reviewerLogin, models.EntryTypeProject, name); err != nil {
+			return added, fmt.Errorf("failed to remove managed entry %s: %w", name, err)
+		}
+	}
+	return added, nil
+}
+
+// RemoveManaged deletes all managed entries of the user, after they opted out of syncing
+func RemoveManaged(ctx context.Context, reviewerLogin string) error {
+	managed, err := store.GetManagedWhitelist(ctx, reviewerLogin)
+	if err != nil {
+		return err
+	}
+	for _, name := range managed {
+		if err := store.RemoveWhitelistEntry(ctx, reviewerLogin, models.EntryTypeProject, name); err != nil {
+			return fmt.Errorf("failed to remove managed entry %s: %w", name, err)
+		}
+	}
+	return nil
+}
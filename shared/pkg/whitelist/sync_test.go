@@ -0,0 +1,83 @@
+package whitelist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+)
+
+func TestPlanSync(t *testing.T) {
+	project := func(name string) *models.WhitelistEntry {
+		return &models.WhitelistEntry{EntryType: models.EntryTypeProject, Name: name}
+	}
+	blocked := func(name string) *models.WhitelistEntry {
+		return &models.WhitelistEntry{EntryType: BlacklistType(models.EntryTypeProject), Name: name}
+	}
+
+	tests := []struct {
+		name      string
+		completed []string
+		entries   []*models.WhitelistEntry
+		managed   []string
+		add       []string
+		remove    []string
+	}{
+		{
+			name:      "AddsNewProjects",
+			completed: []string{"C2_SimpleBashUtils", "C3_s21_string+"},
+			add:       []string{"C2_SimpleBashUtils", "C3_s21_string+"},
+		},
+		{
+			name:      "SkipsManagedAndManualEntries",
+			completed: []string{"C2_SimpleBashUtils", "C3_s21_string+", "DO1_Linux"},
+			entries:   []*models.WhitelistEntry{project("C2_SimpleBashUtils"), project("C3_s21_string+")},
+			managed:   []string{"C2_SimpleBashUtils"},
+			add:       []string{"DO1_Linux"},
+		},
+		{
+			name:      "SkipsBlacklistedProjects",
+			completed: []string{"DO1_Linux"},
+			entries:   []*models.WhitelistEntry{blocked("DO1_Linux")},
+		},
+		{
+			name:      "RemovesBlacklistedManagedEntries",
+			completed: []string{"DO1_Linux"},
+			entries:   []*models.WhitelistEntry{project("DO1_Linux"), blocked("DO1_Linux")},
+			managed:   []string{"DO1_Linux"},
+			remove:    []string{"DO1_Linux"},
+		},
+		{
+			name:      "RemovesProjectsNoLongerCompleted",
+			completed: []string{"C2_SimpleBashUtils"},
+			entries:   []*models.WhitelistEntry{project("C2_SimpleBashUtils"), project("DO1_Linux")},
+			managed:   []string{"C2_SimpleBashUtils", "DO1_Linux"},
+			remove:    []string{"DO1_Linux"},
+		},
+		{
+			name:    "EmptyAnswerKeepsEntries",
+			entries: []*models.WhitelistEntry{project("DO1_Linux")},
+			managed: []string{"DO1_Linux"},
+		},
+		{
+			name:      "IgnoresDuplicates",
+			completed: []string{"DO1_Linux", "DO1_Linux"},
+			add:       []string{"DO1_Linux"},
+		},
+		{
+			name:      "FamilyEntriesDoNotCount",
+			completed: []string{"DO1_Linux"},
+			entries:   []*models.WhitelistEntry{{EntryType: models.EntryTypeFamily, Name: "DO1_Linux"}},
+			add:       []string{"DO1_Linux"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			add, remove := PlanSync(tt.completed, tt.entries, tt.managed)
+			assert.Equal(t, tt.add, add)
+			assert.Equal(t, tt.remove, remove)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package whitelist
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/ydb"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/render"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/store"
+)
+
+// Pattern entry types, stored in user_project_whitelist next to models.EntryTypeFamily
+// and models.EntryTypeProject. Both match the project name or the family label
+const (
+	EntryTypeGlob  = "GLOB"  // shell pattern such as "CPP*", ignoring case
+	EntryTypeRegex = "REGEX" // Go regular expression such as "^go-"
+)
+
+// BlacklistPrefix turns an entry type into its blacklist counterpart, e.g. "BLACKLIST_FAMILY".
+// Blacklist entries live in the same table and match the same way, but bookings they
+// match are cancelled right away
+const BlacklistPrefix = "BLACKLIST_"
+
+// StatusAutoCancelledBlacklisted is the terminal review request status of bookings
+// cancelled because a blacklist entry matched them
+const StatusAutoCancelledBlacklisted = "AUTO_CANCELLED_BLACKLISTED"
+
+// MaxPatternLength limits the length of glob and regex entries
+const MaxPatternLength = 100
+
+// IsValidEntryType checks if a whitelist entry type is valid
+func IsValidEntryType(entryType string) bool {
+	return models.IsValidEntryType(entryType) || IsPattern(entryType)
+}
+
+// IsPattern reports whether entries of the type are patterns rather than exact names
+func IsPattern(entryType string) bool {
+	entryType = BaseType(entryType)
+	return entryType == EntryTypeGlob || entryType == EntryTypeRegex
+}
+
+// BlacklistType returns the blacklist counterpart of a whitelist entry type
+func BlacklistType(entryType string) string {
+	return BlacklistPrefix + entryType
+}
+
+// IsBlacklisted reports whether entries of the type belong to the blacklist
+func IsBlacklisted(entryType string) bool {
+	return strings.HasPrefix(entryType, BlacklistPrefix)
+}
+
+// BaseType returns the entry type without the blacklist prefix
+func BaseType(entryType string) string {
+	return strings.TrimPrefix(entryType, BlacklistPrefix)
+}
+
+// Validate checks a pattern before it is added to the whitelist
+func Validate(entryType, pattern string) error {
+	if pattern == "" {
+		return i18n.Errorf("whitelist.pattern_empty")
+	}
+	if utf8.RuneCountInString(pattern) > MaxPatternLength {
+		return i18n.Errorf("whitelist.pattern_too_long", MaxPatternLength)
+	}
+
+	switch BaseType(entryType) {
+	case EntryTypeGlob:
+		if _, err := path.Match(pattern, ""); err != nil {
+			return i18n.Errorf("whitelist.invalid_glob", pattern)
+		}
+	case EntryTypeRegex:
+		if _, err := regexp.Compile(pattern); err != nil {
+			return i18n.Errorf("whitelist.invalid_regex", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether entry matches a name: exactly for families and projects,
+// by pattern otherwise. Invalid patterns never match
+func Matches(entry *models.WhitelistEntry, name string) bool {
+	if name == "" {
+		return false
+	}
+
+	switch BaseType(entry.EntryType) {
+	case EntryTypeGlob:
+		ok, err := path.Match(strings.ToLower(entry.Name), strings.ToLower(name))
+		return err == nil && ok
+	case EntryTypeRegex:
+		re, err := regexp.Compile(entry.Name)
+		return err == nil && re.MatchString(name)
+	default:
+		return entry.Name == name
+	}
+}
+
+// Describe names an entry of either list for messages, e.g. "glob pattern CPP*"
+func Describe(p *i18n.Printer, entry *models.WhitelistEntry) render.HTML {
+	return p.T("whitelist.entry_"+strings.ToLower(BaseType(entry.EntryType)), entry.Name)
+}
+
+// Match is the whitelist or blacklist entry matching a project
+type Match struct {
+	Entry *models.WhitelistEntry
+	// ByFamily is set when the entry matched the family label rather than the project name
+	ByFamily bool
+	// Blacklisted is set when the entry is a blacklist entry and the booking must be cancelled
+	Blacklisted bool
+	// Preset is the preset the entry comes from, empty for personal entries
+	Preset string
+}
+
+// Find returns the entry that matches the project, or nil. Blacklist entries win over
+// whitelist entries. Within each list exact entries win over patterns; patterns are tried
+// in order, against the project name first
+func Find(entries []*models.WhitelistEntry, projectName, familyLabel string) *Match {
+	if match := find(entries, true, projectName, familyLabel); match != nil {
+		return match
+	}
+	return find(entries, false, projectName, familyLabel)
+}
+
+// FindInList is Find over the blacklist or the whitelist entries only, for callers
+// that weigh the two lists themselves
+func FindInList(entries []*models.WhitelistEntry, blacklisted bool, projectName, familyLabel string) *Match {
+	return find(entries, blacklisted, projectName, familyLabel)
+}
+
+// find looks for a match among the blacklist or the whitelist entries only
+func find(entries []*models.WhitelistEntry, blacklisted bool, projectName, familyLabel string) *Match {
+	var list []*models.WhitelistEntry
+	for _, entry := range entries {
+		if IsBlacklisted(entry.EntryType) == blacklisted {
+			list = append(list, entry)
+		}
+	}
+
+	for _, entry := range list {
+		if BaseType(entry.EntryType) == models.EntryTypeProject && Matches(entry, projectName) {
+			return &Match{Entry: entry, Blacklisted: blacklisted}
+		}
+	}
+	for _, entry := range list {
+		if BaseType(entry.EntryType) == models.EntryTypeFamily && Matches(entry, familyLabel) {
+			return &Match{Entry: entry, ByFamily: true, Blacklisted: blacklisted}
+		}
+	}
+	for _, entry := range list {
+		if !IsPattern(entry.EntryType) {
+			continue
+		}
+		if Matches(entry, projectName) {
+			return &Match{Entry: entry, Blacklisted: blacklisted}
+		}
+		if Matches(entry, familyLabel) {
+			return &Match{Entry: entry, ByFamily: true, Blacklisted: blacklisted}
+		}
+	}
+	return nil
+}
+
+// Lookup returns the user's entry that matches the project, or nil, checking personal
+// entries and subscribed presets alike. It replaces ydb.IsInWhitelist, which only knows
+// exact personal entries and ignores expiry
+func Lookup(ctx context.Context, reviewerLogin, projectName, familyLabel string) (*Match, error) {
+	entries, presets, err := Effective(ctx, reviewerLogin, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return FindWithPresets(entries, presets, projectName, familyLabel), nil
+}
+
+// FindWithPresets is Find over the result of Effective, telling which preset matched
+func FindWithPresets(entries []*models.WhitelistEntry, presets map[*models.WhitelistEntry]string, projectName, familyLabel string) *Match {
+	match := Find(entries, projectName, familyLabel)
+	if match != nil {
+		match.Preset = presets[match.Entry]
+	}
+	return match
+}
+
+// Remove deletes the entries called name from the user's whitelist or blacklist, leaving
+// the other list alone. It reports whether anything was removed
+func Remove(ctx context.Context, reviewerLogin, name string, blacklisted bool) (bool, error) {
+	entries, err := ydb.GetUserWhitelist(ctx, reviewerLogin)
+	if err != nil {
+		return false, fmt.Errorf("failed to get whitelist for %s: %w", reviewerLogin, err)
+	}
+
+	removed := false
+	for _, entry := range entries {
+		if entry.Name != name || IsBlacklisted(entry.EntryType) != blacklisted {
+			continue
+		}
+		if err := store.RemoveWhitelistEntry(ctx, reviewerLogin, entry.EntryType, name); err != nil {
+			return removed, err
+		}
+		removed = true
+	}
+	return removed, nil
+}
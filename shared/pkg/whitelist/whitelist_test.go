@@ -0,0 +1,140 @@
+package whitelist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/arseniisemenow/review-slot-guard-bot-common/pkg/models"
+	"github.com/arseniisemenow/review-slot-guard-bot/shared/pkg/i18n"
+)
+
+func TestIsValidEntryType(t *testing.T) {
+	assert.True(t, IsValidEntryType(models.EntryTypeFamily))
+	assert.True(t, IsValidEntryType(models.EntryTypeProject))
+	assert.True(t, IsValidEntryType(EntryTypeGlob))
+	assert.True(t, IsValidEntryType(EntryTypeRegex))
+	assert.False(t, IsValidEntryType("glob"))
+	assert.False(t, IsValidEntryType("WILDCARD"))
+
+	assert.True(t, IsPattern(EntryTypeRegex))
+	assert.False(t, IsPattern(models.EntryTypeProject))
+	assert.True(t, IsPattern(BlacklistType(EntryTypeGlob)))
+}
+
+func TestBlacklistType(t *testing.T) {
+	entryType := BlacklistType(models.EntryTypeFamily)
+	assert.Equal(t, "BLACKLIST_FAMILY", entryType)
+	assert.True(t, IsBlacklisted(entryType))
+	assert.False(t, IsBlacklisted(models.EntryTypeFamily))
+	assert.Equal(t, models.EntryTypeFamily, BaseType(entryType))
+	assert.Equal(t, EntryTypeRegex, BaseType(EntryTypeRegex))
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		entryType string
+		pattern   string
+		errKey    string
+	}{
+		{"Glob", EntryTypeGlob, "CPP*", ""},
+		{"GlobClass", EntryTypeGlob, "C[0-9]_*", ""},
+		{"GlobUnclosedClass", EntryTypeGlob, "C[0-9", "whitelist.invalid_glob"},
+		{"Regex", EntryTypeRegex, "^go-", ""},
+		{"RegexInvalid", EntryTypeRegex, "^go-(", "whitelist.invalid_regex"},
+		{"Empty", EntryTypeRegex, "", "whitelist.pattern_empty"},
+		{"TooLong", EntryTypeGlob, strings.Repeat("a", MaxPatternLength+1), "whitelist.pattern_too_long"},
+		{"BlacklistRegexInvalid", BlacklistType(EntryTypeRegex), "[", "whitelist.invalid_regex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.entryType, tt.pattern)
+			if tt.errKey == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var i18nErr *i18n.Error
+			require.ErrorAs(t, err, &i18nErr)
+			assert.Equal(t, tt.errKey, i18nErr.Key)
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		entryType string
+		pattern   string
+		subject   string
+		expected  bool
+	}{
+		{"ProjectExact", models.EntryTypeProject, "go-concurrency", "go-concurrency", true},
+		{"ProjectCaseSensitive", models.EntryTypeProject, "go-concurrency", "Go-Concurrency", false},
+		{"FamilyExact", models.EntryTypeFamily, "C - I", "C - I", true},
+		{"GlobPrefix", EntryTypeGlob, "CPP*", "CPP1_s21_matrixplus", true},
+		{"GlobIgnoresCase", EntryTypeGlob, "cpp*", "CPP1_s21_matrixplus", true},
+		{"GlobWholeName", EntryTypeGlob, "CPP", "CPP1_s21_matrixplus", false},
+		{"GlobSingleChar", EntryTypeGlob, "C?_*", "C3_SimpleBashUtils", true},
+		{"GlobWithSpaces", EntryTypeGlob, "C - *", "C - I", true},
+		{"RegexAnchored", EntryTypeRegex, "^go-", "go-concurrency", true},
+		{"RegexAnchoredMiss", EntryTypeRegex, "^go-", "algo-go-", false},
+		{"RegexUnanchored", EntryTypeRegex, "matrix", "CPP1_s21_matrixplus", true},
+		{"RegexCaseFlag", EntryTypeRegex, "(?i)^GO-", "go-concurrency", true},
+		{"RegexInvalidNeverMatches", EntryTypeRegex, "(", "(", false},
+		{"EmptySubject", EntryTypeGlob, "*", "", false},
+		{"BlacklistGlob", BlacklistType(EntryTypeGlob), "cpp*", "CPP1_s21_matrixplus", true},
+		{"BlacklistProject", BlacklistType(models.EntryTypeProject), "DO1_Linux", "DO1_Linux", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &models.WhitelistEntry{EntryType: tt.entryType, Name: tt.pattern}
+			assert.Equal(t, tt.expected, Matches(entry, tt.subject))
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	regex := &models.WhitelistEntry{EntryType: EntryTypeRegex, Name: "^go-"}
+	glob := &models.WhitelistEntry{EntryType: EntryTypeGlob, Name: "DevOps*"}
+	project := &models.WhitelistEntry{EntryType: models.EntryTypeProject, Name: "go-concurrency"}
+	family := &models.WhitelistEntry{EntryType: models.EntryTypeFamily, Name: "C - I"}
+	entries := []*models.WhitelistEntry{regex, glob, project, family}
+
+	tests := []struct {
+		name     string
+		project  string
+		family   string
+		expected *Match
+	}{
+		{"ExactProjectWinsOverPattern", "go-concurrency", "Go", &Match{Entry: project}},
+		{"ExactFamily", "C3_SimpleBashUtils", "C - I", &Match{Entry: family, ByFamily: true}},
+		{"PatternOnProject", "go-boilerplate", "Go", &Match{Entry: regex}},
+		{"PatternOnFamily", "DO1_Linux", "DevOps - I", &Match{Entry: glob, ByFamily: true}},
+		{"NoMatch", "CPP1_s21_matrixplus", "CPP", nil},
+		{"UnknownFamily", "DO1_Linux", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Find(entries, tt.project, tt.family))
+		})
+	}
+
+	assert.Nil(t, Find(nil, "go-concurrency", "Go"))
+
+	t.Run("BlacklistWins", func(t *testing.T) {
+		deniedFamily := &models.WhitelistEntry{EntryType: BlacklistType(models.EntryTypeFamily), Name: "Go"}
+		deniedGlob := &models.WhitelistEntry{EntryType: BlacklistType(EntryTypeGlob), Name: "C3_*"}
+		entries := append([]*models.WhitelistEntry{deniedFamily, deniedGlob}, entries...)
+
+		assert.Equal(t, &Match{Entry: deniedFamily, ByFamily: true, Blacklisted: true}, Find(entries, "go-concurrency", "Go"),
+			"a blacklisted family beats a whitelisted project")
+		assert.Equal(t, &Match{Entry: deniedGlob, Blacklisted: true}, Find(entries, "C3_SimpleBashUtils", "C - I"))
+		assert.Equal(t, &Match{Entry: family, ByFamily: true}, Find(entries, "C2_s21_stringplus", "C - I"))
+	})
+}
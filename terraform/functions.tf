@@ -50,6 +50,7 @@ resource "yandex_function" "telegram_handler" {
     YDB_ENDPOINT         = "grpcs://ydb.serverless.yandexcloud.net:2135"
     YDB_DATABASE         = yandex_ydb_database_serverless.review_slot_guard_bot.database_path
     TELEGRAM_BOT_TOKEN    = var.telegram_bot_token
+    BOT_ADMINS           = var.bot_admins
   }
 
   service_account_id = yandex_iam_service_account.review_slot_guard_bot.id
@@ -20,6 +20,12 @@ variable "telegram_bot_token" {
   sensitive   = true
 }
 
+variable "bot_admins" {
+  description = "Comma-separated School 21 logins allowed to edit every whitelist preset"
+  type        = string
+  default     = ""
+}
+
 variable "s21auto_api_token" {
   description = "s21auto API token (for reference - actual tokens stored per user in Lockbox)"
   type        = string